@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/StageAutoControl/controller/pkg/api/datastore"
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/audio"
+	"github.com/StageAutoControl/controller/pkg/cntl/beat"
+)
+
+var (
+	beatOptions     beat.Options
+	analyzeSongID   string
+	analyzeSongName string
+	saveAnalyzed    bool
+)
+
+// analyzeBeatsCmd represents the analyze-beats command
+var analyzeBeatsCmd = &cobra.Command{
+	Use:   "analyze-beats file.wav",
+	Short: "Detects the beats and tempo changes of a recorded song and proposes a song with its bar changes",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		wav, err := audio.OpenWAV(args[0])
+		if err != nil {
+			logger.Fatalf("Unable to read audio file: %v", err)
+		}
+
+		a, err := beat.Analyze(wav, beatOptions)
+		if err != nil {
+			logger.Fatalf("Unable to analyze audio file: %v", err)
+		}
+
+		a.Song.ID = analyzeSongID
+		a.Song.Name = analyzeSongName
+		fmt.Printf("Detected %d beats, the first one at %v\n", len(a.Beats), a.Beats[0])
+
+		if saveAnalyzed {
+			reply := &cntl.Song{}
+			if err := datastore.NewSongController(logger, storage, loader).Create(nil, a.Song, reply); err != nil {
+				logger.Fatalf("Unable to save song: %v", err)
+			}
+
+			fmt.Printf("%s (%s): saved with %d bar changes\n", reply.ID, reply.Name, len(reply.BarChanges))
+			return
+		}
+
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(a.Song); err != nil {
+			logger.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(analyzeBeatsCmd)
+
+	analyzeBeatsCmd.Flags().Uint8Var(&beatOptions.NoteCount, "note-count", 4, "Amount of beats per bar")
+	analyzeBeatsCmd.Flags().Uint8Var(&beatOptions.NoteValue, "note-value", 4, "Note value of a beat")
+	analyzeBeatsCmd.Flags().Float64Var(&beatOptions.MinBPM, "min-bpm", 60, "Slowest tempo of the beats to detect")
+	analyzeBeatsCmd.Flags().Float64Var(&beatOptions.MaxBPM, "max-bpm", 200, "Fastest tempo of the beats to detect")
+	analyzeBeatsCmd.Flags().Float64Var(&beatOptions.Tolerance, "tolerance", 2, "BPM the tempo of a bar may differ before a new bar change is proposed")
+	analyzeBeatsCmd.Flags().BoolVar(&beatOptions.Sections, "sections", false, "Propose a new section whenever the loudness changes significantly")
+	analyzeBeatsCmd.Flags().StringVar(&analyzeSongID, "id", "", "ID of the proposed song, a new one is generated on save if empty")
+	analyzeBeatsCmd.Flags().StringVar(&analyzeSongName, "name", "", "Name of the proposed song")
+	analyzeBeatsCmd.Flags().BoolVar(&saveAnalyzed, "save", false, "Save the proposed song instead of printing it")
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/StageAutoControl/controller/pkg/cntl/audio"
+	"github.com/StageAutoControl/controller/pkg/cntl/click"
+)
+
+var (
+	clickOptions  = click.Options{SampleRate: click.DefaultSampleRate}
+	clickCuesDir  string
+	clickToneCues bool
+)
+
+// clickTrackCmd represents the click-track command
+var clickTrackCmd = &cobra.Command{
+	Use:   "click-track song-valid-uuid-1 click.wav",
+	Short: "Renders the bar changes of a song into a WAV click track with accented downbeats",
+	Long: `Renders the bar changes of a song into a WAV click track with accented downbeats.
+Section cues are played at the start of the bar before the section. Bundled tone cues are used for common
+section names like intro, verse, prechorus, chorus, bridge, solo, breakdown and outro. No spoken cues are
+bundled, they require --cues-dir, a directory of WAV files named after the sections, e.g. chorus.wav,
+which replace the tone cues.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		data, err := loader.Load()
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		clickOptions.Cues = make(map[string][]float32)
+		if clickToneCues {
+			clickOptions.Cues = click.ToneCues(clickOptions.SampleRate)
+		}
+
+		if clickCuesDir != "" {
+			cues, err := click.LoadCues(clickCuesDir, clickOptions.SampleRate)
+			if err != nil {
+				logger.Fatal(err)
+			}
+
+			for name, cue := range cues {
+				clickOptions.Cues[name] = cue
+			}
+		}
+
+		samples, err := click.Render(data, args[0], clickOptions)
+		if err != nil {
+			logger.Fatalf("Unable to render click track: %v", err)
+		}
+
+		file, err := os.Create(args[1])
+		if err != nil {
+			logger.Fatal(err)
+		}
+		defer file.Close()
+
+		if err := audio.WriteWAV(file, clickOptions.SampleRate, samples); err != nil {
+			logger.Fatalf("Unable to write click track: %v", err)
+		}
+
+		fmt.Printf("%s: rendered click track to %s\n", args[0], args[1])
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(clickTrackCmd)
+
+	clickTrackCmd.Flags().Uint32Var(&clickOptions.SampleRate, "sample-rate", click.DefaultSampleRate, "Sample rate of the click track")
+	clickTrackCmd.Flags().Uint8Var(&clickOptions.CountIn, "count-in", 0, "Amount of bars to count in before the song starts")
+	clickTrackCmd.Flags().Float64Var(&clickOptions.Tempo.Multiplier, "tempo-multiplier", 0, "Multiplier for the speed of the whole song, e.g. 1.05 to play 5% faster")
+	clickTrackCmd.Flags().Uint16Var(&clickOptions.Tempo.BPM, "bpm", 0, "Speed of the first bar change in BPM, all other bar changes are scaled accordingly")
+	clickTrackCmd.Flags().StringVar(&clickCuesDir, "cues-dir", "", "Directory of WAV files named after sections to play as spoken cues, no spoken cues are bundled")
+	clickTrackCmd.Flags().BoolVar(&clickToneCues, "tone-cues", true, "Play the bundled tone cues for common section names")
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/StageAutoControl/controller/pkg/cntl/smf"
+)
+
+// exportMIDICmd represents the export-midi command
+var exportMIDICmd = &cobra.Command{
+	Use:   "export-midi song-valid-uuid-1 file.mid",
+	Short: "Writes the bar changes, MIDI commands and scene starts of a rendered song to a Standard MIDI File",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		data, err := loader.Load()
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		f, err := smf.Export(data, args[0])
+		if err != nil {
+			logger.Fatalf("Unable to export song: %v", err)
+		}
+
+		file, err := os.Create(args[1])
+		if err != nil {
+			logger.Fatal(err)
+		}
+		defer file.Close()
+
+		if err := smf.Write(file, f); err != nil {
+			logger.Fatalf("Unable to write midi file: %v", err)
+		}
+
+		fmt.Printf("%s: exported to %s\n", args[0], args[1])
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(exportMIDICmd)
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/satori/go.uuid"
+	"github.com/spf13/cobra"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/smf"
+	"github.com/StageAutoControl/controller/pkg/cntl/song"
+)
+
+var (
+	importSongID     string
+	importSongName   string
+	importMIDITracks []int
+	importMIDIScenes map[string]string
+)
+
+// importMIDICmd represents the import-midi command
+var importMIDICmd = &cobra.Command{
+	Use:   "import-midi file.mid",
+	Short: "Creates a song from the tempo map and events of a Standard MIDI File",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := smf.ImportOptions{Tracks: importMIDITracks, Scenes: make(map[uint8]string)}
+		for note, sceneID := range importMIDIScenes {
+			n, err := strconv.ParseUint(note, 10, 7)
+			if err != nil {
+				logger.Fatalf("Invalid note %q of scene %s: %v", note, sceneID, err)
+			}
+			opts.Scenes[uint8(n)] = sceneID
+		}
+
+		file, err := os.Open(args[0])
+		if err != nil {
+			logger.Fatal(err)
+		}
+		defer file.Close()
+
+		f, err := smf.Read(file)
+		if err != nil {
+			logger.Fatalf("Unable to read midi file: %v", err)
+		}
+
+		s, err := smf.Import(f, opts)
+		if err != nil {
+			logger.Fatalf("Unable to import midi file: %v", err)
+		}
+
+		s.ID = importSongID
+		if s.ID == "" {
+			s.ID = uuid.NewV4().String()
+		}
+		if importSongName != "" {
+			s.Name = importSongName
+		}
+
+		if storage.Has(s.ID, &cntl.Song{}) {
+			logger.Fatalf("Song %s already exists", s.ID)
+		}
+
+		data, err := loader.Load()
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		if err := song.Validate(data, s); err != nil {
+			logger.Fatalf("Imported song is invalid: %v", err)
+		}
+
+		if err := storage.Write(s.ID, s); err != nil {
+			logger.Fatalf("Unable to write song: %v", err)
+		}
+
+		fmt.Printf("%s (%s): imported %d bar changes, %d MIDI commands and %d scenes\n", s.ID, s.Name, len(s.BarChanges), len(s.MIDICommands), len(s.DMXScenes))
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(importMIDICmd)
+
+	importMIDICmd.Flags().StringVar(&importSongID, "id", "", "ID of the created song, a new one is generated if empty")
+	importMIDICmd.Flags().StringVar(&importSongName, "name", "", "Name of the created song, the name of the first track is used if empty")
+	importMIDICmd.Flags().IntSliceVar(&importMIDITracks, "track", []int{}, "Index of a track to import the MIDI commands of, all tracks if none is given")
+	importMIDICmd.Flags().StringToStringVar(&importMIDIScenes, "scene", map[string]string{}, "Note number mapped to the ID of the DMX scene it places instead of MIDI commands, e.g. 36=scene-valid-uuid")
+}
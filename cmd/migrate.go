@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/song"
+)
+
+// migrateCmd represents the migrate command
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Converts all stored songs to the current song format, e.g. to the 192 frame bar grid",
+	Run: func(cmd *cobra.Command, args []string) {
+		migrated, err := migrateSongs()
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		for _, id := range migrated {
+			fmt.Printf("%s: migrated to format version %d\n", id, cntl.SongFormatVersion)
+		}
+		fmt.Printf("Migrated %d songs\n", len(migrated))
+	},
+}
+
+// migrateSongs converts all stored songs in an older format and writes them back, returning the IDs of the converted songs
+func migrateSongs() ([]string, error) {
+	var ids []string
+	for _, id := range storage.List(&cntl.Song{}) {
+		s := &cntl.Song{}
+		if err := storage.Read(id, s); err != nil {
+			return ids, fmt.Errorf("failed to read song %s: %v", id, err)
+		}
+
+		migrated, changed, err := song.Migrate(s)
+		if err != nil {
+			return ids, fmt.Errorf("failed to migrate song %s: %v", id, err)
+		}
+		if !changed {
+			continue
+		}
+
+		if err := storage.Write(id, migrated); err != nil {
+			return ids, fmt.Errorf("failed to write song %s: %v", id, err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func init() {
+	RootCmd.AddCommand(migrateCmd)
+}
@@ -34,6 +34,8 @@ var (
 
 	viualizerEndpoint string
 	midiDeviceID      int8
+	midiClock         bool
+	midiTransport     bool
 
 	waiterTypes = []string{
 		waiter.TypeNone,
@@ -41,6 +43,17 @@ var (
 	}
 	usedWaiters          []string
 	audioWaiterThreshold float32
+
+	startOptions playback.StartOptions
+
+	followMIDIClock      bool
+	midiClockInputDevice int8
+
+	chaseMTC       bool
+	mtcInputDevice int8
+
+	chaseLTC bool
+	ltcFile  string
 )
 
 // playbackCmd represents the playback command
@@ -96,6 +109,10 @@ var playbackCmd = &cobra.Command{
 					logger.Fatalf("Unable to connect to midi device: %v", err)
 				}
 
+				if midiClock {
+					w.SendClock(midiTransport)
+				}
+
 				writers = append(writers, w)
 
 			default:
@@ -118,10 +135,37 @@ var playbackCmd = &cobra.Command{
 		ctx := exitcontext.New()
 		player := playback.NewPlayer(logger.Logger.WithField("player", "default"), data, writers, waiters)
 
+		if followMIDIClock {
+			in, err := transport.NewMIDIInput(logger.WithField(cntl.LoggerFieldTransport, transport.TypeMidi), midiClockInputDevice)
+			if err != nil {
+				logger.Fatalf("Unable to open midi clock input: %v", err)
+			}
+
+			player.FollowMIDIClock(ctx, in)
+		}
+
+		if chaseMTC {
+			in, err := transport.NewMTCInput(logger.WithField(cntl.LoggerFieldTransport, transport.TypeMidi), mtcInputDevice)
+			if err != nil {
+				logger.Fatalf("Unable to open mtc input: %v", err)
+			}
+
+			player.ChaseTimecode(ctx, in)
+		}
+
+		if chaseLTC {
+			in, err := transport.OpenLTCInput(logger.WithField(cntl.LoggerFieldTransport, transport.TypeLTC), ltcFile)
+			if err != nil {
+				logger.Fatalf("Unable to open ltc input: %v", err)
+			}
+
+			player.ChaseTimecode(ctx, in)
+		}
+
 		switch args[0] {
 		case playbackTypeSong:
 			songID := args[1]
-			if err = player.PlaySong(ctx, songID); err != nil {
+			if err = player.PlaySongFrom(ctx, songID, startOptions); err != nil {
 				logger.Fatal(err)
 			}
 
@@ -140,6 +184,20 @@ func init() {
 	playbackCmd.Flags().StringSliceVarP(&usedTransports, "transport", "t", []string{}, fmt.Sprintf("Which usedTransports to use from %s.", transportTypes))
 	playbackCmd.Flags().StringVar(&viualizerEndpoint, "visualizer-endpoint", "localhost:1337", "Endpoint of the visualizer backend if visualizer transport is chosen.")
 	playbackCmd.Flags().Int8VarP(&midiDeviceID, "midi-device-id", "m", -1, "DeviceID of MIDI output to use (On empty string the default device is used)")
+	playbackCmd.Flags().BoolVar(&midiClock, "midi-clock", false, "Send MIDI clock ticks to the MIDI output")
+	playbackCmd.Flags().BoolVar(&midiTransport, "midi-transport", false, "Send MIDI start, stop, continue and song position pointer messages along with the MIDI clock")
 	playbackCmd.Flags().StringSliceVarP(&usedWaiters, "wait-for", "w", []string{waiter.TypeNone}, fmt.Sprintf("Wait for a specific signal before playing a song (required to be used on stage, otherwise the next song would start immediately), one of %s", waiterTypes))
+	playbackCmd.Flags().Uint16Var(&startOptions.Bar, "start-bar", 0, "Bar to start a song at, e.g. during rehearsals")
+	playbackCmd.Flags().StringVar(&startOptions.Marker, "start-marker", "", "Section or scene marker to start a song at, e.g. during rehearsals")
+	playbackCmd.Flags().Uint8Var(&startOptions.CountIn, "count-in", 0, "Amount of bars to count in before starting a song")
+	playbackCmd.Flags().BoolVar(&startOptions.Click, "click", false, "Send MIDI clicks during the count-in")
+	playbackCmd.Flags().Float64Var(&startOptions.Tempo.Multiplier, "tempo-multiplier", 0, "Multiplier for the speed of the whole song, e.g. 1.05 to play 5% faster")
+	playbackCmd.Flags().Uint16Var(&startOptions.Tempo.BPM, "bpm", 0, "Speed of the first bar change in BPM, all other bar changes are scaled accordingly")
+	playbackCmd.Flags().BoolVar(&followMIDIClock, "follow-midi-clock", false, "Follow the MIDI clock of an input device, e.g. a DAW, instead of the tempo of the song")
+	playbackCmd.Flags().Int8Var(&midiClockInputDevice, "midi-clock-input-id", -1, "DeviceID of the MIDI input to follow the clock of (On -1 the default device is used)")
+	playbackCmd.Flags().BoolVar(&chaseMTC, "chase-mtc", false, "Play songs at the position of the MIDI timecode of an input device, songs need to be positioned in timecode")
+	playbackCmd.Flags().Int8Var(&mtcInputDevice, "mtc-input-id", -1, "DeviceID of the MIDI input to chase the timecode of (On -1 the default device is used)")
+	playbackCmd.Flags().BoolVar(&chaseLTC, "chase-ltc", false, "Play songs at the position of the linear timecode of the default audio input, songs need to be positioned in timecode")
+	playbackCmd.Flags().StringVar(&ltcFile, "ltc-file", "", "WAV file to replay the linear timecode from instead of the audio input, e.g. for testing")
 	playbackCmd.Flags().Float32Var(&audioWaiterThreshold, "audio-waiter-threshold", 0.9, "Threshold frequency for audio waiter to trigger a signal")
 }
@@ -6,6 +6,8 @@ import (
 	"github.com/spf13/cobra"
 
 	apiServer "github.com/StageAutoControl/controller/pkg/api/server"
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/live"
 	"github.com/StageAutoControl/controller/pkg/cntl/playback"
 	"github.com/StageAutoControl/controller/pkg/disk"
 	"github.com/StageAutoControl/controller/pkg/process"
@@ -17,9 +19,25 @@ var serverCmd = &cobra.Command{
 	Use:   "server",
 	Short: "Opens the RPC API to manage the data and control the processes",
 	Run: func(cmd *cobra.Command, args []string) {
+		// songs are served as stored, so older formats are converted before the API is opened
+		migrated, err := migrateSongs()
+		if err != nil {
+			logger.Fatal(err)
+		}
+		if len(migrated) > 0 {
+			logger.Infof("Migrated %d songs to format version %d", len(migrated), cntl.SongFormatVersion)
+		}
+
 		pm := process.NewManager(ctx, logger)
 		visualizer := visualizer.NewServer(logger.WithField("module", "visualizer"))
 
+		// the live output applies the master fader, blackout and flashes of the midi mappings to everything played
+		var output *live.Output
+		if !disableController {
+			output = live.NewOutput(controller)
+			controller = output
+		}
+
 		server, err := apiServer.New(logger.WithField("module", "api"), storage, loader, controller, pm, visualizer)
 		if err != nil {
 			logger.Fatal(err)
@@ -37,13 +55,26 @@ var serverCmd = &cobra.Command{
 			if err := playback.EnsureDefaultConfig(storage); err != nil {
 				logger.Fatal(err)
 			}
-			if err := pm.AddProcess(playback.ProcessName, playback.NewProcess(loader, storage, controller, visualizer), true); err != nil {
+			playbackProcess := playback.NewProcess(loader, storage, controller, visualizer)
+			if err := pm.AddProcess(playback.ProcessName, playbackProcess, true); err != nil {
+				logger.Fatal(err)
+			}
+			if err := live.EnsureDefaultConfig(storage); err != nil {
+				logger.Fatal(err)
+			}
+			liveProcess := live.NewProcess(loader, storage, output, playbackProcess)
+			playbackProcess.SetMIDIInputUser(liveProcess)
+			if err := pm.AddProcess(live.ProcessName, liveProcess, true); err != nil {
 				logger.Fatal(err)
 			}
 			if err := controller.Start(ctx); err != nil {
 				logger.Fatal(err)
 			}
 			logger.Info("Started ArtNet Controller")
+
+			if _, err := pm.Start(live.ProcessName); err != nil {
+				logger.Fatal(err)
+			}
 		}
 
 		if err := server.Run(ctx, endpoint); err != nil {
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/StageAutoControl/controller/pkg/cntl/song"
+)
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate [song-valid-uuid-1 ...]",
+	Short: "Validates the given songs, or all songs when none are given",
+	Run: func(cmd *cobra.Command, args []string) {
+		data, err := loader.Load()
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		songIDs := args
+		if len(songIDs) == 0 {
+			for id := range data.Songs {
+				songIDs = append(songIDs, id)
+			}
+			sort.Strings(songIDs)
+		}
+
+		failed := false
+		for _, id := range songIDs {
+			s, ok := data.Songs[id]
+			if !ok {
+				fmt.Printf("%s: cannot find song\n", id)
+				failed = true
+				continue
+			}
+
+			err := song.Validate(data, s)
+			if err == nil {
+				fmt.Printf("%s (%s): ok\n", id, s.Name)
+				continue
+			}
+
+			failed = true
+			fmt.Printf("%s (%s):\n", id, s.Name)
+			if errs, ok := err.(song.ValidationErrors); ok {
+				for _, err := range errs {
+					fmt.Printf("  - %v\n", err)
+				}
+			} else {
+				fmt.Printf("  - %v\n", err)
+			}
+		}
+
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(validateCmd)
+}
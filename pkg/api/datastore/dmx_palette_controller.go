@@ -0,0 +1,126 @@
+package datastore
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/StageAutoControl/controller/pkg/api"
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/jinzhu/copier"
+	"github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// DMXPaletteController controls the DMXPalette entity
+type DMXPaletteController struct {
+	logger  *logrus.Entry
+	storage api.Storage
+}
+
+// NewDMXPaletteController returns a new DMXPaletteController instance
+func NewDMXPaletteController(logger *logrus.Entry, storage api.Storage) *DMXPaletteController {
+	return &DMXPaletteController{
+		logger:  logger,
+		storage: storage,
+	}
+}
+
+func (c *DMXPaletteController) validate(entity *cntl.DMXPalette) error {
+	if entity.Name == "" {
+		return errors.New("palette needs to have a name")
+	}
+
+	if entity.Device != nil && entity.Group != nil {
+		return errors.New("palette cannot have both a device and a group")
+	}
+
+	return nil
+}
+
+// Create a new DMXPalette
+func (c *DMXPaletteController) Create(r *http.Request, entity *cntl.DMXPalette, reply *cntl.DMXPalette) error {
+	if entity.ID == "" {
+		entity.ID = uuid.NewV4().String()
+	}
+
+	if c.storage.Has(entity.ID, entity) {
+		return api.ErrExists
+	}
+
+	if err := c.validate(entity); err != nil {
+		return fmt.Errorf("failed to validate entity: %v", err)
+	}
+
+	if err := c.storage.Write(entity.ID, entity); err != nil {
+		return fmt.Errorf("failed to write to disk: %v", err)
+	}
+
+	return copier.Copy(reply, entity)
+}
+
+// Update a new DMXPalette
+func (c *DMXPaletteController) Update(r *http.Request, entity *cntl.DMXPalette, reply *cntl.DMXPalette) error {
+	if !c.storage.Has(entity.ID, entity) {
+		return api.ErrNotExists
+	}
+
+	if err := c.validate(entity); err != nil {
+		return fmt.Errorf("failed to validate entity: %v", err)
+	}
+
+	if err := c.storage.Write(entity.ID, entity); err != nil {
+		return fmt.Errorf("failed to update to disk: %v", err)
+	}
+
+	return copier.Copy(reply, entity)
+}
+
+// Get a DMXPalette
+func (c *DMXPaletteController) Get(r *http.Request, idReq *api.IDBody, reply *cntl.DMXPalette) error {
+	if idReq.ID == "" {
+		return api.ErrNoIDGiven
+	}
+
+	if !c.storage.Has(idReq.ID, &cntl.DMXPalette{}) {
+		return api.ErrNotExists
+	}
+
+	if err := c.storage.Read(idReq.ID, reply); err != nil {
+		return fmt.Errorf("failed to read entity: %v", err)
+	}
+
+	return nil
+}
+
+// GetAll returns all entities of DMXPalette
+func (c *DMXPaletteController) GetAll(r *http.Request, idReq *api.Empty, reply *[]*cntl.DMXPalette) error {
+	*reply = []*cntl.DMXPalette{}
+	for _, id := range c.storage.List(&cntl.DMXPalette{}) {
+		entity := &cntl.DMXPalette{}
+		if err := c.storage.Read(id, entity); err != nil {
+			return fmt.Errorf("failed to read entity %s: %v", id, err)
+		}
+		*reply = append(*reply, entity)
+	}
+
+	return nil
+}
+
+// Delete a DMXPalette
+func (c *DMXPaletteController) Delete(r *http.Request, idReq *api.IDBody, reply *api.SuccessResponse) error {
+	if idReq.ID == "" {
+		return api.ErrNoIDGiven
+	}
+
+	if !c.storage.Has(idReq.ID, &cntl.DMXPalette{}) {
+		return api.ErrNotExists
+	}
+
+	if err := c.storage.Delete(idReq.ID, &cntl.DMXPalette{}); err != nil {
+		return fmt.Errorf("failed to delete entity: %v", err)
+	}
+
+	reply.Success = true
+	return nil
+}
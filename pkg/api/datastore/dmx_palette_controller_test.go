@@ -0,0 +1,164 @@
+package datastore
+
+import (
+	"testing"
+
+	"github.com/StageAutoControl/controller/pkg/api"
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	internalTesting "github.com/StageAutoControl/controller/pkg/internal/testing"
+	"github.com/jinzhu/copier"
+)
+
+func TestDMXPaletteController_Create_WithID(t *testing.T) {
+	defer internalTesting.Cleanup(t, path)
+	controller := NewDMXPaletteController(logger, store)
+	key := "f1c2a6c4-6b0e-4c55-9f43-8a2c0b1fd8e7"
+	entity := ds.DMXPalettes[key]
+
+	createReply := &cntl.DMXPalette{}
+	if err := controller.Create(req, entity, createReply); err != nil {
+		t.Errorf("failed to call apiController: %v", err)
+	}
+
+	if createReply.ID != key {
+		t.Errorf("Expected createReply to have id %s, but has %s", key, createReply.ID)
+	}
+}
+
+func TestDMXPaletteController_Create_WithoutID(t *testing.T) {
+	defer internalTesting.Cleanup(t, path)
+	controller := NewDMXPaletteController(logger, store)
+	key := "f1c2a6c4-6b0e-4c55-9f43-8a2c0b1fd8e7"
+	entity := ds.DMXPalettes[key]
+
+	createEntity := &cntl.DMXPalette{}
+	if err := copier.Copy(createEntity, entity); err != nil {
+		t.Fatal(err)
+	}
+
+	createEntity.ID = ""
+
+	createReply := &cntl.DMXPalette{}
+	if err := controller.Create(req, entity, createReply); err != nil {
+		t.Errorf("failed to call apiController: %v", err)
+	}
+
+	if createReply.ID != key {
+		t.Errorf("Expected createReply to have id %s, but has %s", key, createReply.ID)
+	}
+}
+
+func TestDMXPaletteController_Get_NotExisting(t *testing.T) {
+	defer internalTesting.Cleanup(t, path)
+	controller := NewDMXPaletteController(logger, store)
+	key := "f1c2a6c4-6b0e-4c55-9f43-8a2c0b1fd8e7"
+
+	reply := &cntl.DMXPalette{}
+
+	idReq := &api.IDBody{ID: key}
+	if err := controller.Get(req, idReq, reply); err != api.ErrNotExists {
+		t.Errorf("expected to get api.ErrNotExists, but got %v", err)
+	}
+}
+
+func TestDMXPaletteController_Get_Existing(t *testing.T) {
+	defer internalTesting.Cleanup(t, path)
+	controller := NewDMXPaletteController(logger, store)
+	key := "f1c2a6c4-6b0e-4c55-9f43-8a2c0b1fd8e7"
+	entity := ds.DMXPalettes[key]
+
+	createReply := &cntl.DMXPalette{}
+	if err := controller.Create(req, entity, createReply); err != nil {
+		t.Errorf("failed to call apiController: %v", err)
+	}
+
+	if createReply.ID != key {
+		t.Errorf("Expected createReply to have id %s, but has %s", key, createReply.ID)
+	}
+
+	reply := &cntl.DMXPalette{}
+	idReq := &api.IDBody{ID: key}
+	t.Log("idReq has ID:", idReq.ID)
+	if err := controller.Get(req, idReq, reply); err != nil {
+		t.Errorf("failed to call apiController: %v", err)
+	}
+
+	if reply.ID != key {
+		t.Errorf("Expected reply to have id %s, but has %s", key, reply.ID)
+	}
+}
+
+func TestDMXPaletteController_Update_NotExisting(t *testing.T) {
+	defer internalTesting.Cleanup(t, path)
+	controller := NewDMXPaletteController(logger, store)
+	key := "f1c2a6c4-6b0e-4c55-9f43-8a2c0b1fd8e7"
+	entity := ds.DMXPalettes[key]
+
+	reply := &cntl.DMXPalette{}
+
+	if err := controller.Update(req, entity, reply); err != api.ErrNotExists {
+		t.Errorf("expected to get api.ErrNotExists, but got %v", err)
+	}
+}
+
+func TestDMXPaletteController_Update_Existing(t *testing.T) {
+	defer internalTesting.Cleanup(t, path)
+	controller := NewDMXPaletteController(logger, store)
+	key := "f1c2a6c4-6b0e-4c55-9f43-8a2c0b1fd8e7"
+	entity := ds.DMXPalettes[key]
+
+	createReply := &cntl.DMXPalette{}
+	if err := controller.Create(req, entity, createReply); err != nil {
+		t.Errorf("failed to call apiController: %v", err)
+	}
+
+	if createReply.ID != key {
+		t.Errorf("Expected createReply to have id %s, but has %s", key, createReply.ID)
+	}
+
+	reply := &cntl.DMXPalette{}
+	if err := controller.Update(req, entity, reply); err != nil {
+		t.Errorf("expected to get no error, but got %v", err)
+	}
+
+	if reply.ID != key {
+		t.Errorf("Expected reply to have id %s, but has %s", key, reply.ID)
+	}
+}
+func TestDMXPaletteController_Delete_NotExisting(t *testing.T) {
+	defer internalTesting.Cleanup(t, path)
+	controller := NewDMXPaletteController(logger, store)
+	key := "f1c2a6c4-6b0e-4c55-9f43-8a2c0b1fd8e7"
+
+	reply := &api.SuccessResponse{}
+	idReq := &api.IDBody{ID: key}
+	if err := controller.Delete(req, idReq, reply); err != api.ErrNotExists {
+		t.Errorf("expected to get api.ErrNotExists, but got %v", err)
+	}
+}
+
+func TestDMXPaletteController_Delete_Existing(t *testing.T) {
+	defer internalTesting.Cleanup(t, path)
+	controller := NewDMXPaletteController(logger, store)
+	key := "f1c2a6c4-6b0e-4c55-9f43-8a2c0b1fd8e7"
+	entity := ds.DMXPalettes[key]
+
+	createReply := &cntl.DMXPalette{}
+	if err := controller.Create(req, entity, createReply); err != nil {
+		t.Errorf("failed to call apiController: %v", err)
+	}
+
+	if createReply.ID != key {
+		t.Errorf("Expected createReply to have id %s, but has %s", key, createReply.ID)
+	}
+
+	reply := &api.SuccessResponse{}
+	idReq := &api.IDBody{ID: key}
+	if err := controller.Delete(req, idReq, reply); err != nil {
+		t.Errorf("expected to get no error, but got %v", err)
+	}
+
+	if !reply.Success {
+		t.Error("Expected to get result true, but got false")
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/StageAutoControl/controller/pkg/api"
 	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/dmx"
 	"github.com/jinzhu/copier"
 	"github.com/satori/go.uuid"
 	"github.com/sirupsen/logrus"
@@ -25,6 +26,30 @@ func NewDMXPresetController(logger *logrus.Entry, storage api.Storage) *DMXPrese
 	}
 }
 
+func (c *DMXPresetController) validate(entity *cntl.DMXPreset) error {
+	// the animations and transitions may reference the parameters of the preset as well
+	ds := cntl.NewStore()
+	for _, dp := range entity.DeviceParams {
+		if dp.Animation != nil && c.storage.Has(*dp.Animation, &cntl.DMXAnimation{}) {
+			a := &cntl.DMXAnimation{}
+			if err := c.storage.Read(*dp.Animation, a); err != nil {
+				return fmt.Errorf("failed to read DMXAnimation %q: %v", *dp.Animation, err)
+			}
+			ds.DMXAnimations[a.ID] = a
+		}
+
+		if dp.Transition != nil && c.storage.Has(*dp.Transition, &cntl.DMXTransition{}) {
+			t := &cntl.DMXTransition{}
+			if err := c.storage.Read(*dp.Transition, t); err != nil {
+				return fmt.Errorf("failed to read DMXTransition %q: %v", *dp.Transition, err)
+			}
+			ds.DMXTransitions[t.ID] = t
+		}
+	}
+
+	return dmx.ValidatePreset(ds, entity)
+}
+
 // Create a new DMXPreset
 func (c *DMXPresetController) Create(r *http.Request, entity *cntl.DMXPreset, reply *cntl.DMXPreset) error {
 	if entity.ID == "" {
@@ -35,6 +60,10 @@ func (c *DMXPresetController) Create(r *http.Request, entity *cntl.DMXPreset, re
 		return api.ErrExists
 	}
 
+	if err := c.validate(entity); err != nil {
+		return fmt.Errorf("failed to validate entity: %v", err)
+	}
+
 	if err := c.storage.Write(entity.ID, entity); err != nil {
 		return fmt.Errorf("failed to write to disk: %v", err)
 	}
@@ -48,6 +77,10 @@ func (c *DMXPresetController) Update(r *http.Request, entity *cntl.DMXPreset, re
 		return api.ErrNotExists
 	}
 
+	if err := c.validate(entity); err != nil {
+		return fmt.Errorf("failed to validate entity: %v", err)
+	}
+
 	if err := c.storage.Write(entity.ID, entity); err != nil {
 		return fmt.Errorf("failed to update to disk: %v", err)
 	}
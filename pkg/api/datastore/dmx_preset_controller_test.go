@@ -5,6 +5,7 @@ import (
 
 	"github.com/StageAutoControl/controller/pkg/api"
 	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/internal/fixtures"
 	internalTesting "github.com/StageAutoControl/controller/pkg/internal/testing"
 	"github.com/jinzhu/copier"
 )
@@ -48,6 +49,55 @@ func TestDMXPresetController_Create_WithoutID(t *testing.T) {
 	}
 }
 
+func TestDMXPresetController_Create_Invalid(t *testing.T) {
+	defer internalTesting.Cleanup(t, path)
+	controller := NewDMXPresetController(logger, store)
+
+	exp := []*cntl.DMXPreset{
+		{Params: []cntl.DMXPresetParam{{Name: "level", Type: cntl.PresetParamDimmer, Default: fixtures.StrPtr("abc")}}},
+		{DeviceParams: []cntl.DMXDeviceParams{{Group: fixtures.StrPtr("$group")}}},
+	}
+
+	for i, e := range exp {
+		if err := controller.Create(req, e, &cntl.DMXPreset{}); err == nil {
+			t.Errorf("Expected to get an error at index %d", i)
+		}
+	}
+}
+
+func TestDMXPresetController_Get_RoundTrip(t *testing.T) {
+	defer internalTesting.Cleanup(t, path)
+	controller := NewDMXPresetController(logger, store)
+
+	entity := &cntl.DMXPreset{
+		ID: "5a6f3f0e-4c2b-4d8e-9d6a-1c2b3d4e5f60",
+		Params: []cntl.DMXPresetParam{
+			{Name: "washes", Type: cntl.PresetParamGroup},
+			{Name: "level", Type: cntl.PresetParamDimmer, Default: fixtures.StrPtr("128")},
+		},
+		DeviceParams: []cntl.DMXDeviceParams{{Group: fixtures.StrPtr("$washes")}},
+	}
+
+	if err := controller.Create(req, entity, &cntl.DMXPreset{}); err != nil {
+		t.Fatalf("failed to call apiController: %v", err)
+	}
+
+	reply := &cntl.DMXPreset{}
+	if err := controller.Get(req, &api.IDBody{ID: entity.ID}, reply); err != nil {
+		t.Fatalf("failed to call apiController: %v", err)
+	}
+
+	if !reply.Equals(*entity) {
+		t.Errorf("Expected to get %+v, got %+v", entity, reply)
+	}
+
+	changed := *entity
+	changed.Params = []cntl.DMXPresetParam{entity.Params[0], {Name: "level", Type: cntl.PresetParamDimmer, Default: fixtures.StrPtr("64")}}
+	if reply.Equals(changed) {
+		t.Error("Expected a changed param default to be detected")
+	}
+}
+
 func TestDMXPresetController_Get_NotExisting(t *testing.T) {
 	defer internalTesting.Cleanup(t, path)
 	controller := NewDMXPresetController(logger, store)
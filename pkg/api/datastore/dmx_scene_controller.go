@@ -6,6 +6,7 @@ import (
 
 	"github.com/StageAutoControl/controller/pkg/api"
 	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/dmx"
 	"github.com/jinzhu/copier"
 	"github.com/satori/go.uuid"
 	"github.com/sirupsen/logrus"
@@ -25,6 +26,26 @@ func NewDMXSceneController(logger *logrus.Entry, storage api.Storage) *DMXSceneC
 	}
 }
 
+func (c *DMXSceneController) validate(entity *cntl.DMXScene) error {
+	// presets that are not stored yet are reported when a song using the scene is validated
+	for i, ss := range entity.SubScenes {
+		if ss.Preset == nil || !c.storage.Has(*ss.Preset, &cntl.DMXPreset{}) {
+			continue
+		}
+
+		p := &cntl.DMXPreset{}
+		if err := c.storage.Read(*ss.Preset, p); err != nil {
+			return fmt.Errorf("failed to read DMXPreset %q: %v", *ss.Preset, err)
+		}
+
+		if _, err := dmx.ResolvePresetArgs(p, ss.PresetArgs); err != nil {
+			return fmt.Errorf("sub scene %d: %v", i, err)
+		}
+	}
+
+	return nil
+}
+
 // Create a new DMXScene
 func (c *DMXSceneController) Create(r *http.Request, entity *cntl.DMXScene, reply *cntl.DMXScene) error {
 	if entity.ID == "" {
@@ -35,6 +56,10 @@ func (c *DMXSceneController) Create(r *http.Request, entity *cntl.DMXScene, repl
 		return api.ErrExists
 	}
 
+	if err := c.validate(entity); err != nil {
+		return fmt.Errorf("failed to validate entity: %v", err)
+	}
+
 	if err := c.storage.Write(entity.ID, entity); err != nil {
 		return fmt.Errorf("failed to write to disk: %v", err)
 	}
@@ -48,6 +73,10 @@ func (c *DMXSceneController) Update(r *http.Request, entity *cntl.DMXScene, repl
 		return api.ErrNotExists
 	}
 
+	if err := c.validate(entity); err != nil {
+		return fmt.Errorf("failed to validate entity: %v", err)
+	}
+
 	if err := c.storage.Write(entity.ID, entity); err != nil {
 		return fmt.Errorf("failed to update to disk: %v", err)
 	}
@@ -1,10 +1,12 @@
 package datastore
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/StageAutoControl/controller/pkg/api"
 	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/internal/fixtures"
 	internalTesting "github.com/StageAutoControl/controller/pkg/internal/testing"
 	"github.com/jinzhu/copier"
 )
@@ -48,6 +50,115 @@ func TestDMXSceneController_Create_WithoutID(t *testing.T) {
 	}
 }
 
+func TestDMXSceneController_Create_InvalidPresetArgs(t *testing.T) {
+	defer internalTesting.Cleanup(t, path)
+	controller := NewDMXSceneController(logger, store)
+
+	preset := &cntl.DMXPreset{
+		ID: "5a6f3f0e-4c2b-4d8e-9d6a-1c2b3d4e5f60",
+		Params: []cntl.DMXPresetParam{
+			{Name: "washes", Type: cntl.PresetParamGroup},
+			{Name: "level", Type: cntl.PresetParamDimmer, Default: fixtures.StrPtr("255")},
+		},
+	}
+	if err := store.Write(preset.ID, preset); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []cntl.DMXPresetArgs{
+		nil,
+		{"level": "128"},
+		{"washes": "group-1", "level": "abc"},
+		{"washes": "group-1", "unknown": "1"},
+	}
+
+	for i, e := range exp {
+		entity := &cntl.DMXScene{
+			NoteCount: 4,
+			NoteValue: 4,
+			SubScenes: []cntl.DMXSubScene{{At: []uint64{0}, Preset: fixtures.StrPtr(preset.ID), PresetArgs: e}},
+		}
+
+		if err := controller.Create(req, entity, &cntl.DMXScene{}); err == nil {
+			t.Errorf("Expected to get an error at index %d", i)
+		}
+	}
+}
+
+func TestDMXSceneController_Create_MissingRequiredPresetArg(t *testing.T) {
+	defer internalTesting.Cleanup(t, path)
+	controller := NewDMXSceneController(logger, store)
+
+	preset := &cntl.DMXPreset{
+		ID:     "5a6f3f0e-4c2b-4d8e-9d6a-1c2b3d4e5f60",
+		Params: []cntl.DMXPresetParam{{Name: "washes", Type: cntl.PresetParamGroup}},
+	}
+	if err := store.Write(preset.ID, preset); err != nil {
+		t.Fatal(err)
+	}
+
+	entity := &cntl.DMXScene{
+		NoteCount: 4,
+		NoteValue: 4,
+		SubScenes: []cntl.DMXSubScene{{At: []uint64{0}, Preset: fixtures.StrPtr(preset.ID)}},
+	}
+
+	err := controller.Create(req, entity, &cntl.DMXScene{})
+	if err == nil || !strings.Contains(err.Error(), `"washes"`) {
+		t.Errorf("Expected error naming the missing argument %q, got %v", "washes", err)
+	}
+}
+
+func TestDMXSceneController_Get_RoundTrip(t *testing.T) {
+	defer internalTesting.Cleanup(t, path)
+	controller := NewDMXSceneController(logger, store)
+
+	preset := &cntl.DMXPreset{
+		ID:     "5a6f3f0e-4c2b-4d8e-9d6a-1c2b3d4e5f60",
+		Params: []cntl.DMXPresetParam{{Name: "washes", Type: cntl.PresetParamGroup}},
+	}
+	if err := store.Write(preset.ID, preset); err != nil {
+		t.Fatal(err)
+	}
+
+	entity := &cntl.DMXScene{
+		ID:        "d1c5e7a2-8f3b-4a6c-b2d9-0e1f2a3b4c5d",
+		NoteCount: 4,
+		NoteValue: 4,
+		SubScenes: []cntl.DMXSubScene{
+			{At: []uint64{0}, Preset: fixtures.StrPtr(preset.ID), PresetArgs: cntl.DMXPresetArgs{"washes": "group-1"}},
+			{At: []uint64{2}, Scene: &cntl.DMXSceneRef{ID: "492cef2e-0b14-11e7-be89-c3fa25f9cabb", Offset: 1}},
+		},
+	}
+
+	if err := controller.Create(req, entity, &cntl.DMXScene{}); err != nil {
+		t.Fatalf("failed to call apiController: %v", err)
+	}
+
+	reply := &cntl.DMXScene{}
+	if err := controller.Get(req, &api.IDBody{ID: entity.ID}, reply); err != nil {
+		t.Fatalf("failed to call apiController: %v", err)
+	}
+
+	if !reply.Equals(*entity) {
+		t.Errorf("Expected to get %+v, got %+v", entity, reply)
+	}
+
+	changed := []cntl.DMXSubScene{
+		{At: []uint64{0}, Preset: fixtures.StrPtr(preset.ID), PresetArgs: cntl.DMXPresetArgs{"washes": "group-2"}},
+		{At: []uint64{2}, Scene: &cntl.DMXSceneRef{ID: "492cef2e-0b14-11e7-be89-c3fa25f9cabb", Offset: 2}},
+	}
+
+	for i, ss := range changed {
+		c := *entity
+		c.SubScenes = append([]cntl.DMXSubScene{}, entity.SubScenes...)
+		c.SubScenes[i] = ss
+		if reply.Equals(c) {
+			t.Errorf("Expected sub scene change at index %d to be detected", i)
+		}
+	}
+}
+
 func TestDMXSceneController_Get_NotExisting(t *testing.T) {
 	defer internalTesting.Cleanup(t, path)
 	controller := NewDMXSceneController(logger, store)
@@ -0,0 +1,150 @@
+package datastore
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/StageAutoControl/controller/pkg/api"
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/live"
+	"github.com/StageAutoControl/controller/pkg/process"
+	"github.com/jinzhu/copier"
+	"github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// reloader is implemented by processes applying changed entities while they run, e.g. the MIDI input
+type reloader interface {
+	Reload()
+}
+
+// MIDIMappingController controls the MIDIMapping entity
+type MIDIMappingController struct {
+	logger  *logrus.Entry
+	storage api.Storage
+	pm      process.Manager
+}
+
+// NewMIDIMappingController returns a new MIDIMappingController instance, the process manager is optional
+func NewMIDIMappingController(logger *logrus.Entry, storage api.Storage, pm process.Manager) *MIDIMappingController {
+	return &MIDIMappingController{
+		logger:  logger,
+		storage: storage,
+		pm:      pm,
+	}
+}
+
+// reload applies the changed mappings to the MIDI input, if there is one
+func (c *MIDIMappingController) reload() {
+	if c.pm == nil {
+		return
+	}
+
+	p, _, err := c.pm.GetProcess(live.ProcessName)
+	if err != nil {
+		return
+	}
+
+	if r, ok := p.(reloader); ok {
+		r.Reload()
+	}
+}
+
+func (c *MIDIMappingController) validate(entity *cntl.MIDIMapping) error {
+	if entity.Name == "" {
+		return errors.New("midi mapping needs to have a name")
+	}
+
+	return live.ValidateMapping(entity)
+}
+
+// Create a new MIDIMapping
+func (c *MIDIMappingController) Create(r *http.Request, entity *cntl.MIDIMapping, reply *cntl.MIDIMapping) error {
+	if entity.ID == "" {
+		entity.ID = uuid.NewV4().String()
+	}
+
+	if c.storage.Has(entity.ID, entity) {
+		return api.ErrExists
+	}
+
+	if err := c.validate(entity); err != nil {
+		return fmt.Errorf("failed to validate entity: %v", err)
+	}
+
+	if err := c.storage.Write(entity.ID, entity); err != nil {
+		return fmt.Errorf("failed to write to disk: %v", err)
+	}
+	c.reload()
+
+	return copier.Copy(reply, entity)
+}
+
+// Update a new MIDIMapping
+func (c *MIDIMappingController) Update(r *http.Request, entity *cntl.MIDIMapping, reply *cntl.MIDIMapping) error {
+	if !c.storage.Has(entity.ID, entity) {
+		return api.ErrNotExists
+	}
+
+	if err := c.validate(entity); err != nil {
+		return fmt.Errorf("failed to validate entity: %v", err)
+	}
+
+	if err := c.storage.Write(entity.ID, entity); err != nil {
+		return fmt.Errorf("failed to update to disk: %v", err)
+	}
+	c.reload()
+
+	return copier.Copy(reply, entity)
+}
+
+// Get a MIDIMapping
+func (c *MIDIMappingController) Get(r *http.Request, idReq *api.IDBody, reply *cntl.MIDIMapping) error {
+	if idReq.ID == "" {
+		return api.ErrNoIDGiven
+	}
+
+	if !c.storage.Has(idReq.ID, &cntl.MIDIMapping{}) {
+		return api.ErrNotExists
+	}
+
+	if err := c.storage.Read(idReq.ID, reply); err != nil {
+		return fmt.Errorf("failed to read entity: %v", err)
+	}
+
+	return nil
+}
+
+// GetAll returns all entities of MIDIMapping
+func (c *MIDIMappingController) GetAll(r *http.Request, idReq *api.Empty, reply *[]*cntl.MIDIMapping) error {
+	*reply = []*cntl.MIDIMapping{}
+	for _, id := range c.storage.List(&cntl.MIDIMapping{}) {
+		entity := &cntl.MIDIMapping{}
+		if err := c.storage.Read(id, entity); err != nil {
+			return fmt.Errorf("failed to read entity %s: %v", id, err)
+		}
+		*reply = append(*reply, entity)
+	}
+
+	return nil
+}
+
+// Delete a MIDIMapping
+func (c *MIDIMappingController) Delete(r *http.Request, idReq *api.IDBody, reply *api.SuccessResponse) error {
+	if idReq.ID == "" {
+		return api.ErrNoIDGiven
+	}
+
+	if !c.storage.Has(idReq.ID, &cntl.MIDIMapping{}) {
+		return api.ErrNotExists
+	}
+
+	if err := c.storage.Delete(idReq.ID, &cntl.MIDIMapping{}); err != nil {
+		return fmt.Errorf("failed to delete entity: %v", err)
+	}
+	c.reload()
+
+	reply.Success = true
+	return nil
+}
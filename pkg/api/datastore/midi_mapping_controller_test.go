@@ -0,0 +1,228 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/StageAutoControl/controller/pkg/api"
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/live"
+	"github.com/StageAutoControl/controller/pkg/internal/logging"
+	internalTesting "github.com/StageAutoControl/controller/pkg/internal/testing"
+	"github.com/StageAutoControl/controller/pkg/process"
+	"github.com/jinzhu/copier"
+)
+
+type reloadingProcess struct {
+	reloads int
+}
+
+func (p *reloadingProcess) SetLogger(logger logging.Logger) {}
+func (p *reloadingProcess) Start(ctx context.Context) error { return nil }
+func (p *reloadingProcess) Stop() error                     { return nil }
+func (p *reloadingProcess) Blocking() bool                  { return false }
+func (p *reloadingProcess) Reload()                         { p.reloads++ }
+
+func TestMIDIMappingController_Create_WithID(t *testing.T) {
+	defer internalTesting.Cleanup(t, path)
+	controller := NewMIDIMappingController(logger, store, nil)
+	key := "c3a9e2b4-5d1f-4f7a-9b6e-2e8d4a1c7f30"
+	entity := ds.MIDIMappings[key]
+
+	createReply := &cntl.MIDIMapping{}
+	if err := controller.Create(req, entity, createReply); err != nil {
+		t.Errorf("failed to call apiController: %v", err)
+	}
+
+	if createReply.ID != key {
+		t.Errorf("Expected createReply to have id %s, but has %s", key, createReply.ID)
+	}
+}
+
+func TestMIDIMappingController_Create_WithoutID(t *testing.T) {
+	defer internalTesting.Cleanup(t, path)
+	controller := NewMIDIMappingController(logger, store, nil)
+	key := "c3a9e2b4-5d1f-4f7a-9b6e-2e8d4a1c7f30"
+	entity := ds.MIDIMappings[key]
+
+	createEntity := &cntl.MIDIMapping{}
+	if err := copier.Copy(createEntity, entity); err != nil {
+		t.Fatal(err)
+	}
+
+	createEntity.ID = ""
+
+	createReply := &cntl.MIDIMapping{}
+	if err := controller.Create(req, entity, createReply); err != nil {
+		t.Errorf("failed to call apiController: %v", err)
+	}
+
+	if createReply.ID != key {
+		t.Errorf("Expected createReply to have id %s, but has %s", key, createReply.ID)
+	}
+}
+
+func TestMIDIMappingController_Create_Invalid(t *testing.T) {
+	defer internalTesting.Cleanup(t, path)
+	controller := NewMIDIMappingController(logger, store, nil)
+
+	exp := []*cntl.MIDIMapping{
+		{Message: cntl.MIDIMessageNote, Action: cntl.MIDIActionBlackout},
+		{Name: "Unknown message", Message: "sysex", Action: cntl.MIDIActionBlackout},
+		{Name: "Channel 17", Message: cntl.MIDIMessageNote, Channel: 17, Action: cntl.MIDIActionBlackout},
+		{Name: "No scene", Message: cntl.MIDIMessageNote, Action: cntl.MIDIActionScene},
+		{Name: "Stuck flash", Message: cntl.MIDIMessageProgramChange, Action: cntl.MIDIActionFlash, Target: "group"},
+		{Name: "Unknown action", Message: cntl.MIDIMessageControlChange, Action: "fog"},
+	}
+
+	for i, e := range exp {
+		if err := controller.Create(req, e, &cntl.MIDIMapping{}); err == nil {
+			t.Errorf("Expected to get an error at index %d", i)
+		}
+	}
+}
+
+func TestMIDIMappingController_Get_NotExisting(t *testing.T) {
+	defer internalTesting.Cleanup(t, path)
+	controller := NewMIDIMappingController(logger, store, nil)
+	key := "c3a9e2b4-5d1f-4f7a-9b6e-2e8d4a1c7f30"
+
+	reply := &cntl.MIDIMapping{}
+
+	idReq := &api.IDBody{ID: key}
+	if err := controller.Get(req, idReq, reply); err != api.ErrNotExists {
+		t.Errorf("expected to get api.ErrNotExists, but got %v", err)
+	}
+}
+
+func TestMIDIMappingController_Get_Existing(t *testing.T) {
+	defer internalTesting.Cleanup(t, path)
+	controller := NewMIDIMappingController(logger, store, nil)
+	key := "c3a9e2b4-5d1f-4f7a-9b6e-2e8d4a1c7f30"
+	entity := ds.MIDIMappings[key]
+
+	createReply := &cntl.MIDIMapping{}
+	if err := controller.Create(req, entity, createReply); err != nil {
+		t.Errorf("failed to call apiController: %v", err)
+	}
+
+	if createReply.ID != key {
+		t.Errorf("Expected createReply to have id %s, but has %s", key, createReply.ID)
+	}
+
+	reply := &cntl.MIDIMapping{}
+	idReq := &api.IDBody{ID: key}
+	t.Log("idReq has ID:", idReq.ID)
+	if err := controller.Get(req, idReq, reply); err != nil {
+		t.Errorf("failed to call apiController: %v", err)
+	}
+
+	if reply.ID != key {
+		t.Errorf("Expected reply to have id %s, but has %s", key, reply.ID)
+	}
+}
+
+func TestMIDIMappingController_Update_NotExisting(t *testing.T) {
+	defer internalTesting.Cleanup(t, path)
+	controller := NewMIDIMappingController(logger, store, nil)
+	key := "c3a9e2b4-5d1f-4f7a-9b6e-2e8d4a1c7f30"
+	entity := ds.MIDIMappings[key]
+
+	reply := &cntl.MIDIMapping{}
+
+	if err := controller.Update(req, entity, reply); err != api.ErrNotExists {
+		t.Errorf("expected to get api.ErrNotExists, but got %v", err)
+	}
+}
+
+func TestMIDIMappingController_Update_Existing(t *testing.T) {
+	defer internalTesting.Cleanup(t, path)
+	controller := NewMIDIMappingController(logger, store, nil)
+	key := "c3a9e2b4-5d1f-4f7a-9b6e-2e8d4a1c7f30"
+	entity := ds.MIDIMappings[key]
+
+	createReply := &cntl.MIDIMapping{}
+	if err := controller.Create(req, entity, createReply); err != nil {
+		t.Errorf("failed to call apiController: %v", err)
+	}
+
+	if createReply.ID != key {
+		t.Errorf("Expected createReply to have id %s, but has %s", key, createReply.ID)
+	}
+
+	reply := &cntl.MIDIMapping{}
+	if err := controller.Update(req, entity, reply); err != nil {
+		t.Errorf("expected to get no error, but got %v", err)
+	}
+
+	if reply.ID != key {
+		t.Errorf("Expected reply to have id %s, but has %s", key, reply.ID)
+	}
+}
+func TestMIDIMappingController_Delete_NotExisting(t *testing.T) {
+	defer internalTesting.Cleanup(t, path)
+	controller := NewMIDIMappingController(logger, store, nil)
+	key := "c3a9e2b4-5d1f-4f7a-9b6e-2e8d4a1c7f30"
+
+	reply := &api.SuccessResponse{}
+	idReq := &api.IDBody{ID: key}
+	if err := controller.Delete(req, idReq, reply); err != api.ErrNotExists {
+		t.Errorf("expected to get api.ErrNotExists, but got %v", err)
+	}
+}
+
+func TestMIDIMappingController_Delete_Existing(t *testing.T) {
+	defer internalTesting.Cleanup(t, path)
+	controller := NewMIDIMappingController(logger, store, nil)
+	key := "c3a9e2b4-5d1f-4f7a-9b6e-2e8d4a1c7f30"
+	entity := ds.MIDIMappings[key]
+
+	createReply := &cntl.MIDIMapping{}
+	if err := controller.Create(req, entity, createReply); err != nil {
+		t.Errorf("failed to call apiController: %v", err)
+	}
+
+	if createReply.ID != key {
+		t.Errorf("Expected createReply to have id %s, but has %s", key, createReply.ID)
+	}
+
+	reply := &api.SuccessResponse{}
+	idReq := &api.IDBody{ID: key}
+	if err := controller.Delete(req, idReq, reply); err != nil {
+		t.Errorf("expected to get no error, but got %v", err)
+	}
+
+	if !reply.Success {
+		t.Error("Expected to get result true, but got false")
+	}
+}
+
+func TestMIDIMappingController_Reload(t *testing.T) {
+	defer internalTesting.Cleanup(t, path)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := &reloadingProcess{}
+	pm := process.NewManager(ctx, logger)
+	if err := pm.AddProcess(live.ProcessName, p, false); err != nil {
+		t.Fatal(err)
+	}
+
+	controller := NewMIDIMappingController(logger, store, pm)
+	key := "c3a9e2b4-5d1f-4f7a-9b6e-2e8d4a1c7f30"
+	entity := ds.MIDIMappings[key]
+
+	if err := controller.Create(req, entity, &cntl.MIDIMapping{}); err != nil {
+		t.Fatalf("failed to call apiController: %v", err)
+	}
+	if err := controller.Update(req, entity, &cntl.MIDIMapping{}); err != nil {
+		t.Fatalf("failed to call apiController: %v", err)
+	}
+	if err := controller.Delete(req, &api.IDBody{ID: key}, &api.SuccessResponse{}); err != nil {
+		t.Fatalf("failed to call apiController: %v", err)
+	}
+
+	if p.reloads != 3 {
+		t.Errorf("Expected the MIDI input to be reloaded after every write, got %d reloads", p.reloads)
+	}
+}
@@ -1,12 +1,16 @@
 package datastore
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/StageAutoControl/controller/pkg/api"
 	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/audio"
+	"github.com/StageAutoControl/controller/pkg/cntl/beat"
+	"github.com/StageAutoControl/controller/pkg/cntl/smf"
 	"github.com/StageAutoControl/controller/pkg/cntl/song"
 	"github.com/jinzhu/copier"
 	"github.com/satori/go.uuid"
@@ -17,13 +21,15 @@ import (
 type SongController struct {
 	logger  *logrus.Entry
 	storage api.Storage
+	loader  api.Loader
 }
 
 // NewSongController returns a new SongController instance
-func NewSongController(logger *logrus.Entry, storage api.Storage) *SongController {
+func NewSongController(logger *logrus.Entry, storage api.Storage, loader api.Loader) *SongController {
 	return &SongController{
 		logger:  logger,
 		storage: storage,
+		loader:  loader,
 	}
 }
 
@@ -36,11 +42,27 @@ func (c *SongController) validate(entity *cntl.Song) error {
 		return errors.New("song needs to have at least one BarChange")
 	}
 
-	if err := song.ValidateBarChanges(song.StreamlineBarChanges(entity)); err != nil {
-		return fmt.Errorf("failed to validate bar changes: %v", err)
+	// a song without a format version is treated as a new one in the current format, only songs stored on disk
+	// before format versions existed are on the legacy grid and those are migrated when the server starts
+	switch {
+	case entity.FormatVersion == 0:
+		entity.FormatVersion = cntl.SongFormatVersion
+	case entity.FormatVersion > cntl.SongFormatVersion:
+		return fmt.Errorf("unknown song format version %d, the latest is %d", entity.FormatVersion, cntl.SongFormatVersion)
+	case entity.FormatVersion < cntl.SongFormatVersion:
+		migrated, _, err := song.Migrate(entity)
+		if err != nil {
+			return fmt.Errorf("failed to migrate song from format version %d: %v", entity.FormatVersion, err)
+		}
+		*entity = *migrated
 	}
 
-	return nil
+	ds, err := c.loader.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load data store: %v", err)
+	}
+
+	return song.Validate(ds, entity)
 }
 
 // Create a new Song
@@ -112,6 +134,121 @@ func (c *SongController) GetAll(r *http.Request, idReq *api.Empty, reply *[]*cnt
 	return nil
 }
 
+// ConvertToPositions converts all frame positions of a Song to positions in bars and beats and stores it
+func (c *SongController) ConvertToPositions(r *http.Request, idReq *api.IDBody, reply *cntl.Song) error {
+	if idReq.ID == "" {
+		return api.ErrNoIDGiven
+	}
+
+	if !c.storage.Has(idReq.ID, &cntl.Song{}) {
+		return api.ErrNotExists
+	}
+
+	entity := &cntl.Song{}
+	if err := c.storage.Read(idReq.ID, entity); err != nil {
+		return fmt.Errorf("failed to read entity: %v", err)
+	}
+
+	converted, err := song.ConvertToPositions(entity)
+	if err != nil {
+		return fmt.Errorf("failed to convert positions: %v", err)
+	}
+
+	if err := c.storage.Write(converted.ID, converted); err != nil {
+		return fmt.Errorf("failed to update to disk: %v", err)
+	}
+
+	return copier.Copy(reply, converted)
+}
+
+// ImportMIDIFileRequest contains a Standard MIDI File and what to import from it into a new Song
+type ImportMIDIFileRequest struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// File is the content of the .mid file, base64 encoded in JSON
+	File    []byte            `json:"file"`
+	Options smf.ImportOptions `json:"options"`
+}
+
+// ImportMIDIFile creates a new Song from the tempo map and events of a Standard MIDI File
+func (c *SongController) ImportMIDIFile(r *http.Request, req *ImportMIDIFileRequest, reply *cntl.Song) error {
+	f, err := smf.Read(bytes.NewReader(req.File))
+	if err != nil {
+		return fmt.Errorf("failed to read midi file: %v", err)
+	}
+
+	entity, err := smf.Import(f, req.Options)
+	if err != nil {
+		return fmt.Errorf("failed to import midi file: %v", err)
+	}
+
+	entity.ID = req.ID
+	if req.Name != "" {
+		entity.Name = req.Name
+	}
+
+	return c.Create(r, entity, reply)
+}
+
+// ExportMIDIFileResponse contains a rendered Song as Standard MIDI File
+type ExportMIDIFileResponse struct {
+	// File is the content of the .mid file, base64 encoded in JSON
+	File []byte `json:"file"`
+}
+
+// ExportMIDIFile renders a Song and returns its bar changes, MIDI commands and scene starts as Standard MIDI File
+func (c *SongController) ExportMIDIFile(r *http.Request, idReq *api.IDBody, reply *ExportMIDIFileResponse) error {
+	if idReq.ID == "" {
+		return api.ErrNoIDGiven
+	}
+
+	if !c.storage.Has(idReq.ID, &cntl.Song{}) {
+		return api.ErrNotExists
+	}
+
+	ds, err := c.loader.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load data store: %v", err)
+	}
+
+	f, err := smf.Export(ds, idReq.ID)
+	if err != nil {
+		return fmt.Errorf("failed to export song: %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := smf.Write(buf, f); err != nil {
+		return fmt.Errorf("failed to write midi file: %v", err)
+	}
+
+	reply.File = buf.Bytes()
+	return nil
+}
+
+// AnalyzeAudioFileRequest contains a recording of a song and how to detect its beats
+type AnalyzeAudioFileRequest struct {
+	// File is the content of a .wav file, base64 encoded in JSON
+	File    []byte       `json:"file"`
+	Options beat.Options `json:"options"`
+}
+
+// AnalyzeAudioFile detects the beats and tempo changes of a recorded song and proposes a Song with its bar changes,
+// which can be saved with Create
+func (c *SongController) AnalyzeAudioFile(r *http.Request, req *AnalyzeAudioFileRequest, reply *beat.Analysis) error {
+	wav, err := audio.ReadWAV(bytes.NewReader(req.File))
+	if err != nil {
+		return fmt.Errorf("failed to read audio file: %v", err)
+	}
+
+	a, err := beat.Analyze(wav, req.Options)
+	if err != nil {
+		return fmt.Errorf("failed to analyze audio file: %v", err)
+	}
+
+	*reply = *a
+	return nil
+}
+
 // Delete a Song
 func (c *SongController) Delete(r *http.Request, idReq *api.IDBody, reply *api.SuccessResponse) error {
 	if idReq.ID == "" {
@@ -1,17 +1,55 @@
 package datastore
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/StageAutoControl/controller/pkg/api"
 	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/audio"
+	"github.com/StageAutoControl/controller/pkg/cntl/beat"
 	internalTesting "github.com/StageAutoControl/controller/pkg/internal/testing"
 	"github.com/jinzhu/copier"
 )
 
+// writeSongDependencies writes all entities the fixture song references, so it passes validation
+func writeSongDependencies(t *testing.T) {
+	for id, e := range ds.DMXScenes {
+		if err := store.Write(id, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for id, e := range ds.DMXPresets {
+		if err := store.Write(id, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for id, e := range ds.DMXAnimations {
+		if err := store.Write(id, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for id, e := range ds.DMXDevices {
+		if err := store.Write(id, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for id, e := range ds.DMXDeviceTypes {
+		if err := store.Write(id, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for id, e := range ds.DMXColorVariables {
+		if err := store.Write(id, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
 func TestSongController_Create_WithID(t *testing.T) {
 	defer internalTesting.Cleanup(t, path)
-	controller := NewSongController(logger, store)
+	controller := NewSongController(logger, store, loader)
+	writeSongDependencies(t)
 	key := "3c1065c8-0b14-11e7-96eb-5b134621c411"
 	entity := ds.Songs[key]
 
@@ -27,7 +65,8 @@ func TestSongController_Create_WithID(t *testing.T) {
 
 func TestSongController_Create_WithoutID(t *testing.T) {
 	defer internalTesting.Cleanup(t, path)
-	controller := NewSongController(logger, store)
+	controller := NewSongController(logger, store, loader)
+	writeSongDependencies(t)
 	key := "3c1065c8-0b14-11e7-96eb-5b134621c411"
 	entity := ds.Songs[key]
 
@@ -50,7 +89,7 @@ func TestSongController_Create_WithoutID(t *testing.T) {
 
 func TestSongController_Get_NotExisting(t *testing.T) {
 	defer internalTesting.Cleanup(t, path)
-	controller := NewSongController(logger, store)
+	controller := NewSongController(logger, store, loader)
 	key := "3c1065c8-0b14-11e7-96eb-5b134621c411"
 
 	reply := &cntl.Song{}
@@ -63,7 +102,8 @@ func TestSongController_Get_NotExisting(t *testing.T) {
 
 func TestSongController_Get_Existing(t *testing.T) {
 	defer internalTesting.Cleanup(t, path)
-	controller := NewSongController(logger, store)
+	controller := NewSongController(logger, store, loader)
+	writeSongDependencies(t)
 	key := "3c1065c8-0b14-11e7-96eb-5b134621c411"
 	entity := ds.Songs[key]
 
@@ -86,11 +126,15 @@ func TestSongController_Get_Existing(t *testing.T) {
 	if reply.ID != key {
 		t.Errorf("Expected reply to have id %s, but has %s", key, reply.ID)
 	}
+
+	if !reply.Equals(entity) {
+		t.Errorf("Expected to get %+v, got %+v", entity, reply)
+	}
 }
 
 func TestSongController_Update_NotExisting(t *testing.T) {
 	defer internalTesting.Cleanup(t, path)
-	controller := NewSongController(logger, store)
+	controller := NewSongController(logger, store, loader)
 	key := "3c1065c8-0b14-11e7-96eb-5b134621c411"
 	entity := ds.Songs[key]
 
@@ -103,7 +147,8 @@ func TestSongController_Update_NotExisting(t *testing.T) {
 
 func TestSongController_Update_Existing(t *testing.T) {
 	defer internalTesting.Cleanup(t, path)
-	controller := NewSongController(logger, store)
+	controller := NewSongController(logger, store, loader)
+	writeSongDependencies(t)
 	key := "3c1065c8-0b14-11e7-96eb-5b134621c411"
 	entity := ds.Songs[key]
 
@@ -127,7 +172,7 @@ func TestSongController_Update_Existing(t *testing.T) {
 }
 func TestSongController_Delete_NotExisting(t *testing.T) {
 	defer internalTesting.Cleanup(t, path)
-	controller := NewSongController(logger, store)
+	controller := NewSongController(logger, store, loader)
 	key := "3c1065c8-0b14-11e7-96eb-5b134621c411"
 
 	reply := &api.SuccessResponse{}
@@ -139,7 +184,8 @@ func TestSongController_Delete_NotExisting(t *testing.T) {
 
 func TestSongController_Delete_Existing(t *testing.T) {
 	defer internalTesting.Cleanup(t, path)
-	controller := NewSongController(logger, store)
+	controller := NewSongController(logger, store, loader)
+	writeSongDependencies(t)
 	key := "3c1065c8-0b14-11e7-96eb-5b134621c411"
 	entity := ds.Songs[key]
 
@@ -162,3 +208,192 @@ func TestSongController_Delete_Existing(t *testing.T) {
 		t.Error("Expected to get result true, but got false")
 	}
 }
+
+func TestSongController_ConvertToPositions(t *testing.T) {
+	defer internalTesting.Cleanup(t, path)
+	controller := NewSongController(logger, store, loader)
+	writeSongDependencies(t)
+	key := "3c1065c8-0b14-11e7-96eb-5b134621c411"
+	entity := ds.Songs[key]
+
+	createReply := &cntl.Song{}
+	if err := controller.Create(req, entity, createReply); err != nil {
+		t.Errorf("failed to call apiController: %v", err)
+	}
+
+	reply := &cntl.Song{}
+	idReq := &api.IDBody{ID: key}
+	if err := controller.ConvertToPositions(req, idReq, reply); err != nil {
+		t.Fatalf("expected to get no error, but got %v", err)
+	}
+
+	if reply.DMXScenes[1].Position != "9.1" || reply.DMXScenes[1].At != 0 {
+		t.Errorf("Expected scene to be converted to position 9.1, got %q at frame %d", reply.DMXScenes[1].Position, reply.DMXScenes[1].At)
+	}
+
+	getReply := &cntl.Song{}
+	if err := controller.Get(req, idReq, getReply); err != nil {
+		t.Fatalf("expected to get no error, but got %v", err)
+	}
+
+	if getReply.BarChanges[2].Position != "23.1" {
+		t.Errorf("Expected converted song to be stored, got bar change at %q", getReply.BarChanges[2].Position)
+	}
+}
+
+func TestSongController_Create_Invalid(t *testing.T) {
+	defer internalTesting.Cleanup(t, path)
+	controller := NewSongController(logger, store, loader)
+	key := "3c1065c8-0b14-11e7-96eb-5b134621c411"
+	entity := ds.Songs[key]
+
+	createReply := &cntl.Song{}
+	if err := controller.Create(req, entity, createReply); err == nil {
+		t.Error("Expected to get an error for a song referencing missing scenes, got nil")
+	}
+}
+
+func TestSongController_Create_MissingPreset(t *testing.T) {
+	defer internalTesting.Cleanup(t, path)
+	controller := NewSongController(logger, store, loader)
+	writeSongDependencies(t)
+	key := "3c1065c8-0b14-11e7-96eb-5b134621c411"
+	entity := ds.Songs[key]
+
+	presetID := *ds.DMXScenes["492cef2e-0b14-11e7-be89-c3fa25f9cabb"].SubScenes[0].Preset
+	if err := store.Delete(presetID, &cntl.DMXPreset{}); err != nil {
+		t.Fatal(err)
+	}
+
+	createReply := &cntl.Song{}
+	if err := controller.Create(req, entity, createReply); err == nil {
+		t.Error("Expected to get an error for a song whose scene references a missing preset, got nil")
+	}
+}
+
+func TestSongController_Create_FormatVersion(t *testing.T) {
+	exp := []struct {
+		formatVersion uint8
+		expected      uint8
+		err           bool
+	}{
+		{formatVersion: 0, expected: cntl.SongFormatVersion},
+		{formatVersion: cntl.SongFormatVersion, expected: cntl.SongFormatVersion},
+		{formatVersion: cntl.SongFormatVersion + 1, err: true},
+	}
+
+	for i, e := range exp {
+		func() {
+			defer internalTesting.Cleanup(t, path)
+			controller := NewSongController(logger, store, loader)
+			writeSongDependencies(t)
+
+			entity := &cntl.Song{}
+			if err := copier.Copy(entity, ds.Songs["3c1065c8-0b14-11e7-96eb-5b134621c411"]); err != nil {
+				t.Fatal(err)
+			}
+			entity.FormatVersion = e.formatVersion
+
+			reply := &cntl.Song{}
+			err := controller.Create(req, entity, reply)
+			if e.err {
+				if err == nil {
+					t.Errorf("Expected to get an error at index %d", i)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Expected to get no error at index %d, got %v", i, err)
+			}
+
+			if reply.FormatVersion != e.expected {
+				t.Errorf("Expected format version %d at index %d, got %d", e.expected, i, reply.FormatVersion)
+			}
+		}()
+	}
+}
+
+func TestSongController_ImportMIDIFile(t *testing.T) {
+	defer internalTesting.Cleanup(t, path)
+	controller := NewSongController(logger, store, loader)
+
+	file := []byte{
+		'M', 'T', 'h', 'd', 0, 0, 0, 6, 0, 0, 0, 1, 0, 96,
+		'M', 'T', 'r', 'k', 0, 0, 0, 19,
+		0x00, 0xFF, 0x51, 0x03, 0x07, 0xA1, 0x20,
+		0x00, 0x90, 60, 100,
+		0x60, 0x80, 60, 0,
+		0x00, 0xFF, 0x2F, 0x00,
+	}
+
+	reply := &cntl.Song{}
+	if err := controller.ImportMIDIFile(req, &ImportMIDIFileRequest{Name: "imported", File: file}, reply); err != nil {
+		t.Fatalf("expected to get no error, but got %v", err)
+	}
+
+	if reply.ID == "" || reply.Name != "imported" || len(reply.BarChanges) != 1 || len(reply.MIDICommands) != 2 {
+		t.Errorf("Expected to get a named song with a bar change and two MIDI commands, got %+v", reply)
+	}
+
+	if !store.Has(reply.ID, &cntl.Song{}) {
+		t.Errorf("Expected imported song %s to be stored", reply.ID)
+	}
+
+	if err := controller.ImportMIDIFile(req, &ImportMIDIFileRequest{File: file[:10]}, reply); err == nil {
+		t.Error("Expected to get an error for a truncated file, got nil")
+	}
+}
+
+func TestSongController_ExportMIDIFile(t *testing.T) {
+	defer internalTesting.Cleanup(t, path)
+	controller := NewSongController(logger, store, loader)
+	writeSongDependencies(t)
+	key := "3c1065c8-0b14-11e7-96eb-5b134621c411"
+	if err := store.Write(key, ds.Songs[key]); err != nil {
+		t.Fatal(err)
+	}
+
+	reply := &ExportMIDIFileResponse{}
+	if err := controller.ExportMIDIFile(req, &api.IDBody{ID: key}, reply); err != nil {
+		t.Fatalf("expected to get no error, but got %v", err)
+	}
+
+	if !bytes.HasPrefix(reply.File, []byte("MThd")) {
+		t.Errorf("Expected to get a midi file, got %q", reply.File)
+	}
+
+	if err := controller.ExportMIDIFile(req, &api.IDBody{ID: "unknown"}, reply); err != api.ErrNotExists {
+		t.Errorf("Expected to get error %v, got %v", api.ErrNotExists, err)
+	}
+}
+
+func TestSongController_AnalyzeAudioFile(t *testing.T) {
+	controller := NewSongController(logger, store, loader)
+
+	// a click every half second, 120 BPM
+	samples := make([]float32, 6*44100)
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 200; j++ {
+			samples[44100/2+i*44100/2+j] = 0.8
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := audio.WriteWAV(buf, 44100, samples); err != nil {
+		t.Fatal(err)
+	}
+
+	reply := &beat.Analysis{}
+	if err := controller.AnalyzeAudioFile(req, &AnalyzeAudioFileRequest{File: buf.Bytes()}, reply); err != nil {
+		t.Fatalf("expected to get no error, but got %v", err)
+	}
+
+	if len(reply.Song.BarChanges) != 1 || reply.Song.BarChanges[0].Speed != 120 {
+		t.Errorf("Expected to get a song with a bar change at 120 BPM, got %+v", reply.Song.BarChanges)
+	}
+
+	if err := controller.AnalyzeAudioFile(req, &AnalyzeAudioFileRequest{File: []byte("RIFF")}, reply); err == nil {
+		t.Error("Expected to get an error for an invalid file, got nil")
+	}
+}
@@ -15,6 +15,7 @@ var (
 	logger *logrus.Entry
 	path   string
 	store  api.Storage
+	loader api.Loader
 	ds     = fixtures.DataStore()
 	req    = httptest.NewRequest(http.MethodPost, api.RPCPath, nil)
 )
@@ -27,5 +28,7 @@ func init() {
 		panic(err)
 	}
 
-	store = disk.New(path)
+	storage := disk.New(path)
+	store = storage
+	loader = disk.NewLoader(storage)
 }
@@ -0,0 +1,115 @@
+package live
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/StageAutoControl/controller/pkg/api"
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/live"
+	"github.com/StageAutoControl/controller/pkg/process"
+)
+
+// Controller handles the MIDI input process which triggers the MIDI mappings
+type Controller struct {
+	pm process.Manager
+}
+
+// NewController returns a new live controller instance
+func NewController(pm process.Manager) *Controller {
+	return &Controller{
+		pm: pm,
+	}
+}
+
+// Status Response of the MIDI input process
+type Status struct {
+	Process process.Status `json:"process"`
+}
+
+func (c *Controller) process() (*live.Process, *process.Status, error) {
+	p, s, err := c.pm.GetProcess(live.ProcessName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch midi input process: %v", err)
+	}
+
+	return p.(*live.Process), s, nil
+}
+
+// Start listening to the MIDI input
+func (c *Controller) Start(r *http.Request, req *api.Empty, res *Status) error {
+	s, err := c.pm.Start(live.ProcessName)
+	if err != nil {
+		return fmt.Errorf("failed to start midi input: %v", err)
+	}
+
+	res.Process = *s
+	return nil
+}
+
+// Stop listening to the MIDI input
+func (c *Controller) Stop(r *http.Request, req *api.Empty, res *Status) error {
+	s, err := c.pm.Stop(live.ProcessName)
+	if err != nil {
+		return fmt.Errorf("failed to stop midi input: %v", err)
+	}
+
+	res.Process = *s
+	return nil
+}
+
+// Status returns the current status of the MIDI input
+func (c *Controller) Status(r *http.Request, req *api.Empty, res *Status) error {
+	_, s, err := c.process()
+	if err != nil {
+		return err
+	}
+
+	res.Process = *s
+	return nil
+}
+
+// Reload applies changed MIDI mappings, scenes, presets and devices to the running MIDI input
+func (c *Controller) Reload(r *http.Request, req *api.Empty, res *api.SuccessResponse) error {
+	p, _, err := c.process()
+	if err != nil {
+		return err
+	}
+
+	p.Reload()
+
+	res.Success = true
+	return nil
+}
+
+// LearnRequest contains how many seconds to wait for a MIDI message, live.LearnTimeout if not given
+type LearnRequest struct {
+	Timeout uint16 `json:"timeout"`
+}
+
+// Learn waits for the next MIDI message and returns a mapping with its type, channel and data byte filled in,
+// which can be completed and created as a MIDIMapping
+func (c *Controller) Learn(r *http.Request, req *LearnRequest, res *cntl.MIDIMapping) error {
+	p, _, err := c.process()
+	if err != nil {
+		return err
+	}
+
+	timeout := live.LearnTimeout
+	if req.Timeout > 0 {
+		timeout = time.Duration(req.Timeout) * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	m, err := p.Learn(ctx)
+	if err != nil {
+		return err
+	}
+
+	*res = m
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/StageAutoControl/controller/pkg/api"
 	"github.com/StageAutoControl/controller/pkg/cntl/playback"
@@ -85,3 +86,165 @@ func (c *Controller) Status(r *http.Request, req *api.IDBody, res *Status) error
 
 	return nil
 }
+
+// MarkerRequest names a section or scene marker of the played song
+type MarkerRequest struct {
+	Marker string `json:"marker"`
+}
+
+// SectionRequest names a section of the played song, an empty name means the current section
+type SectionRequest struct {
+	Section string `json:"section"`
+}
+
+func (c *Controller) navigation() (*playback.Navigation, error) {
+	p, _, err := c.pm.GetProcess(playback.ProcessName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch playback process: %v", err)
+	}
+
+	return p.(*playback.Process).Navigation()
+}
+
+// JumpTo jumps to the given marker at the next bar boundary
+func (c *Controller) JumpTo(r *http.Request, req *MarkerRequest, res *api.SuccessResponse) error {
+	nav, err := c.navigation()
+	if err != nil {
+		return err
+	}
+
+	if err := nav.JumpTo(req.Marker); err != nil {
+		return err
+	}
+
+	res.Success = true
+	return nil
+}
+
+// Skip jumps to the next section at the next bar boundary
+func (c *Controller) Skip(r *http.Request, req *api.Empty, res *api.SuccessResponse) error {
+	nav, err := c.navigation()
+	if err != nil {
+		return err
+	}
+
+	if err := nav.Skip(); err != nil {
+		return err
+	}
+
+	res.Success = true
+	return nil
+}
+
+// Loop repeats the given section until it is released
+func (c *Controller) Loop(r *http.Request, req *SectionRequest, res *api.SuccessResponse) error {
+	nav, err := c.navigation()
+	if err != nil {
+		return err
+	}
+
+	if err := nav.Loop(req.Section); err != nil {
+		return err
+	}
+
+	res.Success = true
+	return nil
+}
+
+// Release stops looping a section
+func (c *Controller) Release(r *http.Request, req *api.Empty, res *api.SuccessResponse) error {
+	nav, err := c.navigation()
+	if err != nil {
+		return err
+	}
+
+	nav.Release()
+
+	res.Success = true
+	return nil
+}
+
+// SeekRequest names the bar the playback should continue at
+type SeekRequest struct {
+	Bar uint16 `json:"bar"`
+}
+
+// Pause holds the playback at the current frame
+func (c *Controller) Pause(r *http.Request, req *api.Empty, res *api.SuccessResponse) error {
+	nav, err := c.navigation()
+	if err != nil {
+		return err
+	}
+
+	nav.Pause()
+
+	res.Success = true
+	return nil
+}
+
+// Resume continues a paused playback
+func (c *Controller) Resume(r *http.Request, req *api.Empty, res *api.SuccessResponse) error {
+	nav, err := c.navigation()
+	if err != nil {
+		return err
+	}
+
+	nav.Resume()
+
+	res.Success = true
+	return nil
+}
+
+// Seek continues the playback at the start of the given bar, replaying the channel state at that position
+func (c *Controller) Seek(r *http.Request, req *SeekRequest, res *api.SuccessResponse) error {
+	nav, err := c.navigation()
+	if err != nil {
+		return err
+	}
+
+	if err := nav.Seek(req.Bar); err != nil {
+		return err
+	}
+
+	res.Success = true
+	return nil
+}
+
+// NudgeRequest contains the BPM the played song is sped up, or slowed down if negative
+type NudgeRequest struct {
+	BPM float64 `json:"bpm"`
+}
+
+// Nudge changes the speed of the played song by the given BPM
+func (c *Controller) Nudge(r *http.Request, req *NudgeRequest, res *api.SuccessResponse) error {
+	nav, err := c.navigation()
+	if err != nil {
+		return err
+	}
+
+	nav.Nudge(req.BPM)
+
+	res.Success = true
+	return nil
+}
+
+// TapRequest contains whether the tap marks the start of a bar
+type TapRequest struct {
+	Downbeat bool `json:"downbeat"`
+}
+
+// TapResponse contains the averaged tempo of the taps so far, 0 after the first tap
+type TapResponse struct {
+	BPM float64 `json:"bpm"`
+}
+
+// Tap taps the tempo of the played song, which is applied at the next bar
+func (c *Controller) Tap(r *http.Request, req *TapRequest, res *TapResponse) error {
+	nav, err := c.navigation()
+	if err != nil {
+		return err
+	}
+
+	res.BPM = nav.Tap(time.Now(), req.Downbeat)
+	return nil
+}
@@ -58,20 +58,7 @@ func (c *DMXPlaygroundController) SetChannelValues(r *http.Request, values *[]ar
 type PlayOnceRequest struct {
 	api.IDBody
 	cntl.BarParams
-}
-
-func (c *DMXPlaygroundController) defaultBarParams(bp *cntl.BarParams) {
-	if bp.Speed == 0 {
-		bp.Speed = 140
-	}
-
-	if bp.NoteCount == 0 {
-		bp.NoteCount = 4
-	}
-
-	if bp.NoteValue == 0 {
-		bp.NoteValue = 4
-	}
+	PresetArgs cntl.DMXPresetArgs `json:"presetArgs"`
 }
 
 // PlayScene plays the given Scene once
@@ -95,7 +82,7 @@ func (c *DMXPlaygroundController) PlayScene(r *http.Request, req *PlayOnceReques
 		return fmt.Errorf("failed to render scene %s: %v", req.ID, err)
 	}
 
-	c.defaultBarParams(&req.BarParams)
+	playback.DefaultBarParams(&req.BarParams)
 	commands := playback.ToPlayable(req.BarParams, dmxCommands)
 	if err := playback.Play(context.Background(), c.logger, []playback.TransportWriter{c.controller}, commands); err != nil {
 		return fmt.Errorf("failed to start playback: %v", err)
@@ -120,12 +107,12 @@ func (c *DMXPlaygroundController) PlayPreset(r *http.Request, req *PlayOnceReque
 		return fmt.Errorf("failed to find preset with id %s", req.ID)
 	}
 
-	dmxCommands, err := dmx.RenderPreset(ds, preset)
+	dmxCommands, err := dmx.RenderPreset(ds, preset, req.PresetArgs)
 	if err != nil {
 		return fmt.Errorf("failed to render preset %s: %v", req.ID, err)
 	}
 
-	c.defaultBarParams(&req.BarParams)
+	playback.DefaultBarParams(&req.BarParams)
 	commands := playback.ToPlayable(req.BarParams, dmxCommands)
 	if err := playback.Play(context.Background(), c.logger, []playback.TransportWriter{c.controller}, commands); err != nil {
 		return fmt.Errorf("failed to start playback: %v", err)
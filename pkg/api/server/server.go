@@ -13,6 +13,7 @@ import (
 
 	"github.com/StageAutoControl/controller/pkg/api"
 	"github.com/StageAutoControl/controller/pkg/api/datastore"
+	"github.com/StageAutoControl/controller/pkg/api/live"
 	"github.com/StageAutoControl/controller/pkg/api/playback"
 	"github.com/StageAutoControl/controller/pkg/api/playground"
 	"github.com/StageAutoControl/controller/pkg/artnet"
@@ -69,10 +70,13 @@ func (s *Server) registerControllers() error {
 		"DMXScene":         datastore.NewDMXSceneController(s.logger, s.storage),
 		"DMXTransition":    datastore.NewDMXTransitionController(s.logger, s.storage),
 		"DMXColorVariable": datastore.NewDMXColorVariableController(s.logger, s.storage),
-		"Song":             datastore.NewSongController(s.logger, s.storage),
+		"DMXPalette":       datastore.NewDMXPaletteController(s.logger, s.storage),
+		"MIDIMapping":      datastore.NewMIDIMappingController(s.logger, s.storage, s.pm),
+		"Song":             datastore.NewSongController(s.logger, s.storage, s.loader),
 		"SetList":          datastore.NewSetListController(s.logger, s.storage),
 		"DMXPlayground":    playground.NewDMXPlaygroundController(s.logger, s.cntl, s.loader),
 		"Playback":         playback.NewController(s.pm),
+		"Live":             live.NewController(s.pm),
 	}
 
 	for name, cntl := range s.apiController {
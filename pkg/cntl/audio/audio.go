@@ -0,0 +1,106 @@
+// Package audio provides mono audio samples from a sound card or from WAV files.
+package audio
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gordonklaus/portaudio"
+)
+
+// Source provides mono audio samples in the range of -1 to 1
+type Source interface {
+	SampleRate() float64
+	// Read fills the given buffer with the next samples and returns how many were read, io.EOF when there are no more
+	Read(buf []float32) (int, error)
+	Close() error
+}
+
+// Input reads samples from the default input device using portaudio, which needs to be initialized
+type Input struct {
+	stream     *portaudio.Stream
+	sampleRate float64
+	buf        []float32
+	pending    []float32
+}
+
+// NewInput opens the default input device with the given sample rate, reading bufferSize samples at once
+func NewInput(sampleRate float64, bufferSize int) (*Input, error) {
+	i := &Input{
+		sampleRate: sampleRate,
+		buf:        make([]float32, bufferSize),
+	}
+
+	var err error
+	i.stream, err = portaudio.OpenDefaultStream(1, 0, sampleRate, len(i.buf), i.buf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open default portaudio stream: %v", err)
+	}
+
+	if err := i.stream.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start portaudio stream: %v", err)
+	}
+
+	return i, nil
+}
+
+// SampleRate returns the sample rate of the input
+func (i *Input) SampleRate() float64 {
+	return i.sampleRate
+}
+
+// Read reads the next samples from the input device, blocking until they are recorded
+func (i *Input) Read(buf []float32) (int, error) {
+	if len(i.pending) == 0 {
+		if err := i.stream.Read(); err != nil {
+			return 0, err
+		}
+		i.pending = i.buf
+	}
+
+	n := copy(buf, i.pending)
+	i.pending = i.pending[n:]
+	return n, nil
+}
+
+// Close stops and closes the input stream
+func (i *Input) Close() error {
+	if err := i.stream.Stop(); err != nil {
+		// the stream has to be closed anyway
+		_ = i.stream.Close()
+		return fmt.Errorf("failed to stop portaudio stream: %v", err)
+	}
+
+	if err := i.stream.Close(); err != nil {
+		return fmt.Errorf("failed to close portaudio stream: %v", err)
+	}
+
+	return nil
+}
+
+// realtime delivers the samples of a source not faster than they would be recorded
+type realtime struct {
+	Source
+	start time.Time
+	read  int
+}
+
+// Realtime returns a source which delivers the samples of the given source not faster than they would be recorded,
+// e.g. to replay a WAV file instead of a live input
+func Realtime(s Source) Source {
+	return &realtime{Source: s}
+}
+
+func (r *realtime) Read(buf []float32) (int, error) {
+	if r.start.IsZero() {
+		r.start = time.Now()
+	}
+
+	n, err := r.Source.Read(buf)
+	r.read += n
+
+	due := r.start.Add(time.Duration(float64(r.read) / r.SampleRate() * float64(time.Second)))
+	time.Sleep(time.Until(due))
+
+	return n, err
+}
@@ -0,0 +1,23 @@
+package audio
+
+// Resample converts the given samples from one sample rate to another using linear interpolation
+func Resample(samples []float32, from, to float64) []float32 {
+	if from == to || len(samples) == 0 {
+		return samples
+	}
+
+	out := make([]float32, int(float64(len(samples))*to/from))
+	for i := range out {
+		pos := float64(i) * from / to
+		j := int(pos)
+		if j+1 >= len(samples) {
+			out[i] = samples[len(samples)-1]
+			continue
+		}
+
+		frac := float32(pos - float64(j))
+		out[i] = samples[j] + (samples[j+1]-samples[j])*frac
+	}
+
+	return out
+}
@@ -0,0 +1,49 @@
+package audio
+
+import "testing"
+
+func TestResample(t *testing.T) {
+	exp := []struct {
+		samples  []float32
+		from, to float64
+		result   []float32
+	}{
+		{
+			samples: []float32{0, 1, 0, -1},
+			from:    48000,
+			to:      48000,
+			result:  []float32{0, 1, 0, -1},
+		},
+		{
+			samples: []float32{0, 1, 0, -1},
+			from:    24000,
+			to:      48000,
+			result:  []float32{0, 0.5, 1, 0.5, 0, -0.5, -1, -1},
+		},
+		{
+			samples: []float32{0, 0.5, 1, 0.5, 0, -0.5},
+			from:    48000,
+			to:      24000,
+			result:  []float32{0, 1, 0},
+		},
+		{
+			samples: []float32{},
+			from:    44100,
+			to:      48000,
+			result:  []float32{},
+		},
+	}
+
+	for i, e := range exp {
+		result := Resample(e.samples, e.from, e.to)
+		if len(result) != len(e.result) {
+			t.Fatalf("Expected to get %d samples, got %d at index %d", len(e.result), len(result), i)
+		}
+
+		for j := range result {
+			if result[j] != e.result[j] {
+				t.Errorf("Expected sample %d to be %v, got %v at index %d", j, e.result[j], result[j], i)
+			}
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+)
+
+// WAV format codes
+const (
+	wavFormatPCM   = 1
+	wavFormatFloat = 3
+)
+
+// ErrInvalidWAV is returned if a WAV file cannot be parsed
+var ErrInvalidWAV = errors.New("invalid WAV file")
+
+// WAV provides the samples of the first channel of a WAV file as a Source.
+// 8, 16, 24 and 32 bit PCM as well as 32 bit float samples are supported.
+type WAV struct {
+	sampleRate float64
+	samples    []float32
+	pos        int
+}
+
+type wavFormat struct {
+	AudioFormat   uint16
+	Channels      uint16
+	SampleRate    uint32
+	ByteRate      uint32
+	BlockAlign    uint16
+	BitsPerSample uint16
+}
+
+// OpenWAV reads the WAV file at the given path
+func OpenWAV(path string) (*WAV, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadWAV(f)
+}
+
+// ReadWAV reads a WAV file from the given reader
+func ReadWAV(r io.Reader) (*WAV, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, ErrInvalidWAV
+	}
+
+	var format *wavFormat
+	for pos := 12; pos+8 <= len(data); {
+		id := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		pos += 8
+		if pos+size > len(data) {
+			size = len(data) - pos
+		}
+		chunk := data[pos : pos+size]
+		// chunks are padded to an even size
+		pos += size + size%2
+
+		switch id {
+		case "fmt ":
+			format = &wavFormat{}
+			if err := binary.Read(bytes.NewReader(chunk), binary.LittleEndian, format); err != nil {
+				return nil, ErrInvalidWAV
+			}
+
+		case "data":
+			if format == nil {
+				return nil, ErrInvalidWAV
+			}
+
+			samples, err := decodeSamples(format, chunk)
+			if err != nil {
+				return nil, err
+			}
+
+			return &WAV{sampleRate: float64(format.SampleRate), samples: samples}, nil
+		}
+	}
+
+	return nil, ErrInvalidWAV
+}
+
+func decodeSamples(f *wavFormat, data []byte) ([]float32, error) {
+	width := int(f.BitsPerSample / 8)
+	if f.Channels == 0 || width == 0 || int(f.BlockAlign) < int(f.Channels)*width {
+		return nil, ErrInvalidWAV
+	}
+
+	samples := make([]float32, len(data)/int(f.BlockAlign))
+	for i := range samples {
+		b := data[i*int(f.BlockAlign):]
+
+		switch {
+		case f.AudioFormat == wavFormatFloat && width == 4:
+			samples[i] = math.Float32frombits(binary.LittleEndian.Uint32(b))
+		case f.AudioFormat == wavFormatPCM && width == 1:
+			samples[i] = (float32(b[0]) - 128) / 128
+		case f.AudioFormat == wavFormatPCM && width == 2:
+			samples[i] = float32(int16(binary.LittleEndian.Uint16(b))) / (1 << 15)
+		case f.AudioFormat == wavFormatPCM && width == 3:
+			samples[i] = float32(int32(uint32(b[0])<<8|uint32(b[1])<<16|uint32(b[2])<<24)>>8) / (1 << 23)
+		case f.AudioFormat == wavFormatPCM && width == 4:
+			samples[i] = float32(int32(binary.LittleEndian.Uint32(b))) / (1 << 31)
+		default:
+			return nil, fmt.Errorf("WAV files with format %d and %d bits per sample are not supported", f.AudioFormat, f.BitsPerSample)
+		}
+	}
+
+	return samples, nil
+}
+
+// SampleRate returns the sample rate of the WAV file
+func (w *WAV) SampleRate() float64 {
+	return w.sampleRate
+}
+
+// Samples returns all samples of the WAV file
+func (w *WAV) Samples() []float32 {
+	return w.samples
+}
+
+// Read reads the next samples of the WAV file
+func (w *WAV) Read(buf []float32) (int, error) {
+	if w.pos >= len(w.samples) {
+		return 0, io.EOF
+	}
+
+	n := copy(buf, w.samples[w.pos:])
+	w.pos += n
+	return n, nil
+}
+
+// Close does nothing, the WAV file is read completely when opened
+func (w *WAV) Close() error {
+	return nil
+}
+
+// WriteWAV writes the given samples as a mono 16 bit PCM WAV file
+func WriteWAV(w io.Writer, sampleRate uint32, samples []float32) error {
+	const bitsPerSample = 16
+	dataSize := uint32(len(samples) * bitsPerSample / 8)
+
+	buf := &bytes.Buffer{}
+	buf.WriteString("RIFF")
+	_ = binary.Write(buf, binary.LittleEndian, 36+dataSize)
+	buf.WriteString("WAVEfmt ")
+	_ = binary.Write(buf, binary.LittleEndian, uint32(16))
+	_ = binary.Write(buf, binary.LittleEndian, wavFormat{
+		AudioFormat:   wavFormatPCM,
+		Channels:      1,
+		SampleRate:    sampleRate,
+		ByteRate:      sampleRate * bitsPerSample / 8,
+		BlockAlign:    bitsPerSample / 8,
+		BitsPerSample: bitsPerSample,
+	})
+	buf.WriteString("data")
+	_ = binary.Write(buf, binary.LittleEndian, dataSize)
+
+	for _, s := range samples {
+		s = float32(math.Max(-1, math.Min(1, float64(s))))
+		_ = binary.Write(buf, binary.LittleEndian, int16(s*math.MaxInt16))
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,63 @@
+package audio
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"testing"
+)
+
+func TestWAV(t *testing.T) {
+	samples := []float32{0, 0.5, -0.5, 1, -1, 0.25}
+
+	buf := &bytes.Buffer{}
+	if err := WriteWAV(buf, 48000, samples); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	w, err := ReadWAV(buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if w.SampleRate() != 48000 {
+		t.Errorf("Expected to get sample rate 48000, got %v", w.SampleRate())
+	}
+
+	read := make([]float32, 4)
+	var res []float32
+	for {
+		n, err := w.Read(read)
+		res = append(res, read[:n]...)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if len(res) != len(samples) {
+		t.Fatalf("Expected to get %d samples, got %d", len(samples), len(res))
+	}
+
+	for i, s := range samples {
+		if math.Abs(float64(res[i]-s)) > 1.0/math.MaxInt16 {
+			t.Errorf("Expected to get sample %v at index %d, got %v", s, i, res[i])
+		}
+	}
+}
+
+func TestReadWAV_Invalid(t *testing.T) {
+	exp := [][]byte{
+		{},
+		[]byte("RIFF\x00\x00\x00\x00WAVX"),
+		[]byte("RIFF\x00\x00\x00\x00WAVEdata\x00\x00\x00\x00"),
+	}
+
+	for i, e := range exp {
+		if _, err := ReadWAV(bytes.NewReader(e)); err == nil {
+			t.Errorf("Expected to get an error at index %d", i)
+		}
+	}
+}
@@ -0,0 +1,328 @@
+// Package beat detects the beats and the tempo map of a recorded song to propose the bar changes of a new song.
+package beat
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/audio"
+	"github.com/StageAutoControl/controller/pkg/cntl/song"
+)
+
+// analysis parameters
+const (
+	// hopsPerSecond defines the resolution of the onset detection
+	hopsPerSecond = 200
+
+	// tempoOctaveWidth defines how strongly tempos far from preferredBPM are avoided, in octaves
+	tempoOctaveWidth = 1.0
+	preferredBPM     = 120
+
+	// tightness defines how strongly the beat tracking keeps the tempo instead of following onsets
+	tightness = 100
+
+	// weakBeat defines the onset strength relative to the median of all beats below which beats at the start and end
+	// of the recording are dropped, e.g. in silence
+	weakBeat = 0.1
+
+	// sectionLoudness defines how many dB the loudness of a bar has to differ from the current section to start a new one
+	sectionLoudness = 6
+)
+
+// ErrNoBeats is returned if not enough beats are found for a single bar
+var ErrNoBeats = errors.New("not enough beats detected")
+
+// Options configure the beat detection, the zero value uses 4/4 between 60 and 200 BPM
+type Options struct {
+	NoteCount uint8 `json:"noteCount"`
+	NoteValue uint8 `json:"noteValue"`
+	// MinBPM and MaxBPM limit the detected tempo of the beats, a beat is a note of NoteValue
+	MinBPM float64 `json:"minBpm"`
+	MaxBPM float64 `json:"maxBpm"`
+	// Tolerance defines how many BPM the tempo of a bar may differ before a new bar change is proposed
+	Tolerance float64 `json:"tolerance"`
+	// Sections proposes a new section whenever the loudness changes significantly
+	Sections bool `json:"sections"`
+}
+
+// Analysis is the result of a beat detection
+type Analysis struct {
+	// Beats are the times of all detected beats, the first one is expected to be a downbeat
+	Beats []time.Duration `json:"beats"`
+	// Song is the proposed skeleton of a song starting at the first beat
+	Song *cntl.Song `json:"song"`
+}
+
+func (o *Options) setDefaults() error {
+	if o.NoteCount == 0 {
+		o.NoteCount = 4
+	}
+	if o.NoteValue == 0 {
+		o.NoteValue = 4
+	}
+	if o.MinBPM == 0 {
+		o.MinBPM = 60
+	}
+	if o.MaxBPM == 0 {
+		o.MaxBPM = 200
+	}
+	if o.Tolerance == 0 {
+		o.Tolerance = 2
+	}
+
+	if !cntl.ValidNoteValue(o.NoteValue) {
+		return fmt.Errorf("note value %d does not divide the %d render frames of a bar", o.NoteValue, cntl.RenderFrames)
+	}
+	if o.MinBPM <= 0 || o.MaxBPM <= o.MinBPM {
+		return fmt.Errorf("the tempo range of %v to %v BPM is invalid", o.MinBPM, o.MaxBPM)
+	}
+
+	return nil
+}
+
+// Analyze reads the whole source and detects its beats and tempo changes
+func Analyze(src audio.Source, opts Options) (*Analysis, error) {
+	if err := opts.setDefaults(); err != nil {
+		return nil, err
+	}
+
+	samples, err := readAll(src)
+	if err != nil {
+		return nil, err
+	}
+
+	hop := int(src.SampleRate() / hopsPerSecond)
+	// the actual rate of hops differs slightly as a hop has a whole number of samples
+	rate := src.SampleRate() / float64(hop)
+	onsets := onsetStrength(samples, hop)
+	if len(onsets) == 0 {
+		return nil, ErrNoBeats
+	}
+
+	period := estimatePeriod(onsets, rate, opts.MinBPM, opts.MaxBPM)
+	beats := trimWeakBeats(onsets, trackBeats(onsets, period))
+	if len(beats) <= int(opts.NoteCount) {
+		return nil, ErrNoBeats
+	}
+
+	a := &Analysis{Beats: make([]time.Duration, len(beats))}
+	for i, b := range beats {
+		a.Beats[i] = time.Duration(float64(b) / rate * float64(time.Second))
+	}
+
+	a.Song = propose(samples, hop, rate, beats, opts)
+	return a, nil
+}
+
+func readAll(src audio.Source) ([]float32, error) {
+	var samples []float32
+	buf := make([]float32, 4096)
+	for {
+		n, err := src.Read(buf)
+		samples = append(samples, buf[:n]...)
+		if err == io.EOF {
+			return samples, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read audio: %v", err)
+		}
+	}
+}
+
+// onsetStrength returns the increase of the log energy of every hop, normalized to a standard deviation of 1
+func onsetStrength(samples []float32, hop int) []float64 {
+	if hop <= 0 || len(samples) < 2*hop {
+		return nil
+	}
+
+	onsets := make([]float64, len(samples)/hop)
+	prev := 0.0
+	for i := range onsets {
+		var energy float64
+		for _, s := range samples[i*hop : (i+1)*hop] {
+			energy += float64(s) * float64(s)
+		}
+
+		l := math.Log10(energy/float64(hop) + 1e-10)
+		if i > 0 && l > prev {
+			onsets[i] = l - prev
+		}
+		prev = l
+	}
+
+	var sum, sq float64
+	for _, o := range onsets {
+		sum += o
+		sq += o * o
+	}
+	mean := sum / float64(len(onsets))
+	if std := math.Sqrt(sq/float64(len(onsets)) - mean*mean); std > 0 {
+		for i := range onsets {
+			onsets[i] /= std
+		}
+	}
+
+	return onsets
+}
+
+// estimatePeriod returns the beat period in hops with the strongest autocorrelation of the onsets,
+// weighted towards preferredBPM to avoid picking half or double the tempo
+func estimatePeriod(onsets []float64, rate, minBPM, maxBPM float64) float64 {
+	minLag := int(60 * rate / maxBPM)
+	maxLag := int(math.Ceil(60 * rate / minBPM))
+	preferred := 60 * rate / preferredBPM
+
+	best, bestScore := float64(minLag), math.Inf(-1)
+	for lag := minLag; lag <= maxLag && lag < len(onsets); lag++ {
+		var acf float64
+		for i := lag; i < len(onsets); i++ {
+			acf += onsets[i] * onsets[i-lag]
+		}
+
+		octaves := math.Log2(float64(lag) / preferred)
+		score := acf * math.Exp(-0.5*octaves*octaves/(tempoOctaveWidth*tempoOctaveWidth))
+		if score > bestScore {
+			best, bestScore = float64(lag), score
+		}
+	}
+
+	return best
+}
+
+// trackBeats finds the beats which best match the onsets while keeping a steady tempo around the given period,
+// using dynamic programming
+func trackBeats(onsets []float64, period float64) []int {
+	score := make([]float64, len(onsets))
+	prev := make([]int, len(onsets))
+
+	for t := range onsets {
+		best, bestScore := -1, 0.0
+		for p := t - int(2*period); p <= t-int(period/2); p++ {
+			if p < 0 {
+				continue
+			}
+
+			deviation := math.Log(float64(t-p) / period)
+			if s := score[p] - tightness*deviation*deviation; best < 0 || s > bestScore {
+				best, bestScore = p, s
+			}
+		}
+
+		score[t] = onsets[t]
+		prev[t] = -1
+		if best >= 0 && bestScore > 0 {
+			score[t] += bestScore
+			prev[t] = best
+		}
+	}
+
+	// the last beat is the best one within the last period
+	last := len(onsets) - 1
+	for t := len(onsets) - int(period); t < len(onsets); t++ {
+		if t >= 0 && score[t] > score[last] {
+			last = t
+		}
+	}
+
+	var beats []int
+	for t := last; t >= 0; t = prev[t] {
+		beats = append(beats, t)
+	}
+
+	// the beats were collected from the end
+	for i, j := 0, len(beats)-1; i < j; i, j = i+1, j-1 {
+		beats[i], beats[j] = beats[j], beats[i]
+	}
+
+	return beats
+}
+
+// trimWeakBeats drops beats at the start and the end which have almost no onset, e.g. in silence
+func trimWeakBeats(onsets []float64, beats []int) []int {
+	if len(beats) == 0 {
+		return beats
+	}
+
+	strengths := make([]float64, len(beats))
+	for i, b := range beats {
+		strengths[i] = onsets[b]
+	}
+	sort.Float64s(strengths)
+	threshold := weakBeat * strengths[len(strengths)/2]
+
+	for len(beats) > 0 && onsets[beats[0]] < threshold {
+		beats = beats[1:]
+	}
+	for len(beats) > 0 && onsets[beats[len(beats)-1]] < threshold {
+		beats = beats[:len(beats)-1]
+	}
+
+	return beats
+}
+
+// propose groups the beats into bars and proposes a bar change whenever the tempo changes
+func propose(samples []float32, hop int, rate float64, beats []int, opts Options) *cntl.Song {
+	n := int(opts.NoteCount)
+	bars := (len(beats) - 1) / n
+	bc := cntl.BarChange{BarParams: cntl.BarParams{NoteCount: opts.NoteCount, NoteValue: opts.NoteValue}}
+	barLength := song.CalcBarLength(&bc)
+
+	s := &cntl.Song{MIDICommands: []cntl.MIDICommand{}, FormatVersion: cntl.SongFormatVersion}
+
+	// start is the first bar of the current tempo
+	start := 0
+	for bar := 1; bar <= bars; bar++ {
+		if bar < bars && math.Abs(beatBPM(rate, beats, bar*n, (bar+1)*n)-beatBPM(rate, beats, start*n, bar*n)) <= opts.Tolerance {
+			continue
+		}
+
+		bc.At = uint64(start) * barLength
+		bc.Speed = uint16(math.Round(beatBPM(rate, beats, start*n, bar*n) * 4 / float64(opts.NoteValue)))
+		if len(s.BarChanges) == 0 || s.BarChanges[len(s.BarChanges)-1].Speed != bc.Speed {
+			s.BarChanges = append(s.BarChanges, bc)
+		}
+		start = bar
+	}
+
+	if opts.Sections {
+		s.Sections = proposeSections(samples, hop, beats, n, bars, barLength)
+	}
+
+	return s
+}
+
+// beatBPM returns the tempo of the beats from the beat with index from to the one with index to
+func beatBPM(rate float64, beats []int, from, to int) float64 {
+	return 60 * rate * float64(to-from) / float64(beats[to]-beats[from])
+}
+
+// proposeSections starts a new section whenever the loudness of a bar differs from the current section
+func proposeSections(samples []float32, hop int, beats []int, n, bars int, barLength uint64) []cntl.SongSection {
+	var sections []cntl.SongSection
+	var sum float64
+	var count int
+
+	for bar := 0; bar < bars; bar++ {
+		var energy float64
+		from, to := beats[bar*n]*hop, beats[(bar+1)*n]*hop
+		for _, s := range samples[from:to] {
+			energy += float64(s) * float64(s)
+		}
+		loudness := 10 * math.Log10(energy/float64(to-from)+1e-10)
+
+		if count == 0 || math.Abs(loudness-sum/float64(count)) > sectionLoudness {
+			sections = append(sections, cntl.SongSection{Name: fmt.Sprintf("section %d", len(sections)+1), At: uint64(bar) * barLength})
+			sum, count = 0, 0
+		}
+
+		sum += loudness
+		count++
+	}
+
+	return sections
+}
@@ -0,0 +1,137 @@
+package beat
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/audio"
+)
+
+const testSampleRate = 44100
+
+// clicks returns a click at every given time, each one a short decaying sine with the given amplitude
+func clicks(length time.Duration, times []time.Duration, amplitudes []float32) []float32 {
+	samples := make([]float32, int(length.Seconds()*testSampleRate))
+	for i, t := range times {
+		start := int(t.Seconds() * testSampleRate)
+		for j := 0; j < testSampleRate/50 && start+j < len(samples); j++ {
+			decay := math.Exp(-float64(j) / (testSampleRate / 200))
+			samples[start+j] = amplitudes[i] * float32(decay*math.Sin(2*math.Pi*1000*float64(j)/testSampleRate))
+		}
+	}
+
+	return samples
+}
+
+// tempoMap returns the times and amplitudes of the given number of beats at the given BPM, starting at the given time
+func tempoMap(start time.Duration, parts ...struct {
+	beats     int
+	bpm       float64
+	amplitude float32
+}) ([]time.Duration, []float32) {
+	var times []time.Duration
+	var amplitudes []float32
+
+	t := start
+	for _, p := range parts {
+		for i := 0; i < p.beats; i++ {
+			times = append(times, t)
+			amplitudes = append(amplitudes, p.amplitude)
+			t += time.Duration(float64(time.Minute) / p.bpm)
+		}
+	}
+
+	// the last bar ends with a beat
+	return append(times, t), append(amplitudes, amplitudes[len(amplitudes)-1])
+}
+
+func wavSource(t *testing.T, samples []float32) audio.Source {
+	buf := &bytes.Buffer{}
+	if err := audio.WriteWAV(buf, testSampleRate, samples); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := audio.ReadWAV(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return w
+}
+
+type part = struct {
+	beats     int
+	bpm       float64
+	amplitude float32
+}
+
+func TestAnalyze(t *testing.T) {
+	exp := []struct {
+		parts      []part
+		opts       Options
+		barChanges []cntl.BarChange
+		sections   []cntl.SongSection
+	}{
+		{
+			parts: []part{{16, 120, 0.9}},
+			barChanges: []cntl.BarChange{
+				{At: 0, BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 120}},
+			},
+		},
+		{
+			parts: []part{{16, 120, 0.9}, {16, 100, 0.2}},
+			opts:  Options{Sections: true},
+			barChanges: []cntl.BarChange{
+				{At: 0, BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 120}},
+				{At: 768, BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 100}},
+			},
+			sections: []cntl.SongSection{{Name: "section 1", At: 0}, {Name: "section 2", At: 768}},
+		},
+		{
+			parts: []part{{18, 150, 0.5}, {12, 130, 0.5}},
+			opts:  Options{NoteCount: 6, NoteValue: 8},
+			barChanges: []cntl.BarChange{
+				{At: 0, BarParams: cntl.BarParams{NoteCount: 6, NoteValue: 8, Speed: 75}},
+				{At: 432, BarParams: cntl.BarParams{NoteCount: 6, NoteValue: 8, Speed: 65}},
+			},
+		},
+	}
+
+	for i, e := range exp {
+		times, amplitudes := tempoMap(time.Second, e.parts...)
+		length := times[len(times)-1] + time.Second
+
+		a, err := Analyze(wavSource(t, clicks(length, times, amplitudes)), e.opts)
+		if err != nil {
+			t.Fatalf("Unexpected error at index %d: %v", i, err)
+		}
+
+		if len(a.Beats) != len(times) {
+			t.Fatalf("Expected to detect %d beats at index %d, got %d: %v", len(times), i, len(a.Beats), a.Beats)
+		}
+
+		for j, b := range a.Beats {
+			if d := b - times[j]; d < -10*time.Millisecond || d > 10*time.Millisecond {
+				t.Errorf("Expected to detect beat %d at %v at index %d, got %v", j, times[j], i, b)
+			}
+		}
+
+		if !reflect.DeepEqual(a.Song.BarChanges, e.barChanges) {
+			t.Errorf("Expected to get bar changes %+v at index %d, got %+v", e.barChanges, i, a.Song.BarChanges)
+		}
+
+		if !reflect.DeepEqual(a.Song.Sections, e.sections) {
+			t.Errorf("Expected to get sections %+v at index %d, got %+v", e.sections, i, a.Song.Sections)
+		}
+	}
+}
+
+func TestAnalyze_Silence(t *testing.T) {
+	if _, err := Analyze(wavSource(t, make([]float32, 5*testSampleRate)), Options{}); err != ErrNoBeats {
+		t.Errorf("Expected to get error %v, got %v", ErrNoBeats, err)
+	}
+}
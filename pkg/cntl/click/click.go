@@ -0,0 +1,273 @@
+// Package click renders the tempo map of a song into an audio click track, e.g. for in-ear monitoring.
+package click
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/audio"
+	"github.com/StageAutoControl/controller/pkg/cntl/playback"
+	"github.com/StageAutoControl/controller/pkg/cntl/song"
+)
+
+const (
+	// DefaultSampleRate is used if no sample rate is given
+	DefaultSampleRate = 48000
+
+	// clickLength defines how long a single click sounds
+	clickLength = 30 * time.Millisecond
+	// clickDecay defines how fast a click fades out, per second
+	clickDecay = 150
+
+	accentFrequency = 1600
+	accentGain      = 0.9
+	beatFrequency   = 1000
+	beatGain        = 0.6
+	cueGain         = 0.8
+)
+
+// Options configure the rendering of a click track
+type Options struct {
+	// SampleRate defaults to DefaultSampleRate
+	SampleRate uint32
+	// CountIn defines how many bars are clicked before the song starts
+	CountIn uint8
+	Tempo   playback.TempoOptions
+	// Cues map section names to samples at SampleRate, e.g. the ToneCues or spoken cues read with LoadCues,
+	// which are played at the start of the bar before the section.
+	// Section names without a cue of their own use the cue of their lower case name, e.g. of the ToneCues.
+	Cues map[string][]float32
+}
+
+// Render renders a click for every beat of the given song, accenting the downbeats.
+// The click lasts until the end of the song, or until the end of the last bar containing anything.
+func Render(ds *cntl.DataStore, songID string, opts Options) ([]float32, error) {
+	if opts.SampleRate == 0 {
+		opts.SampleRate = DefaultSampleRate
+	}
+
+	s, ok := ds.Songs[songID]
+	if !ok {
+		return nil, fmt.Errorf("cannot find Song %q", songID)
+	}
+
+	resolved, err := song.ResolvePositions(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve positions: %v", err)
+	}
+
+	commands, err := tempoMap(ds, resolved)
+	if err != nil {
+		return nil, err
+	}
+
+	countIn, err := playback.CountIn(commands, 0, playback.StartOptions{CountIn: opts.CountIn, Tempo: opts.Tempo})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render count-in: %v", err)
+	}
+
+	// the count-in already has the adjusted tempo
+	countInTimes, err := playback.FrameTimes(countIn, playback.TempoOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	times, err := playback.FrameTimes(commands, opts.Tempo)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := countInTimes[len(countIn)]
+	for i := range times {
+		times[i] += offset
+	}
+
+	commands = append(countIn, commands...)
+	times = append(countInTimes[:len(countIn)], times...)
+
+	cues := make(map[int]string)
+	for _, sec := range resolved.Sections {
+		cues[len(countIn)+int(sec.At)] = sec.Name
+	}
+
+	rate := float64(opts.SampleRate)
+	accent := clickSound(rate, accentFrequency, accentGain)
+	beat := clickSound(rate, beatFrequency, beatGain)
+
+	samples := make([]float32, sampleAt(times[len(commands)], rate))
+	var noteLength uint64
+	barChange, bar, prevBar := 0, -1, -1
+	for i, cmd := range commands {
+		if cmd.BarChange != nil {
+			noteLength = song.CalcNoteLength(cmd.BarChange)
+			barChange = i
+		}
+
+		if uint64(i-barChange)%noteLength == 0 {
+			if cmd.Note == 1 {
+				prevBar, bar = bar, i
+				mix(samples, accent, sampleAt(times[i], rate))
+			} else {
+				mix(samples, beat, sampleAt(times[i], rate))
+			}
+		}
+
+		name, ok := cues[i]
+		if !ok {
+			continue
+		}
+
+		cue, ok := opts.Cues[name]
+		if !ok {
+			cue, ok = opts.Cues[strings.ToLower(name)]
+		}
+		if !ok {
+			continue
+		}
+
+		at := i
+		switch {
+		case bar == i && prevBar >= 0:
+			at = prevBar
+		case bar >= 0 && bar < i:
+			at = bar
+		}
+
+		mix(samples, scale(cue, cueGain), sampleAt(times[at], rate))
+	}
+
+	return samples, nil
+}
+
+// tempoMap returns a command for every frame of the given resolved song, carrying only its bar changes and beats
+func tempoMap(ds *cntl.DataStore, s *cntl.Song) ([]cntl.Command, error) {
+	bcs := song.StreamlineBarChanges(s)
+	if err := song.ValidateBarChanges(bcs); err != nil {
+		return nil, fmt.Errorf("failed to validate bar changes: %v", err)
+	}
+
+	rendered, err := song.Render(ds, s.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	length := uint64(len(rendered))
+	for at := range bcs {
+		if at >= length {
+			length = at + 1
+		}
+	}
+	for _, sec := range s.Sections {
+		if sec.At >= length {
+			length = sec.At + 1
+		}
+	}
+
+	end, hasEnd, err := song.ResolveEnd(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve song end: %v", err)
+	}
+	if hasEnd {
+		length = end
+	}
+
+	var cs []cntl.Command
+	var bc cntl.BarChange
+	var barStart uint64
+	var bar uint16
+	for frame := uint64(0); ; frame++ {
+		var barChange *cntl.BarChange
+		if b, ok := bcs[frame]; ok {
+			bc, barStart = b, frame
+			barChange = &b
+		}
+
+		// without an end the last bar is completed
+		rel := frame - barStart
+		atBarStart := rel%song.CalcBarLength(&bc) == 0
+		if frame >= length && (hasEnd || atBarStart) {
+			break
+		}
+		if atBarStart {
+			bar++
+		}
+
+		noteLength := song.CalcNoteLength(&bc)
+		cs = append(cs, cntl.Command{
+			FrameState: cntl.FrameState{
+				Frame: frame,
+				Bar:   bar,
+				Note:  uint8(rel/noteLength%uint64(bc.NoteCount)) + 1,
+			},
+			DMXCommands:  cntl.DMXCommands{},
+			MIDICommands: cntl.MIDICommands{},
+			BarChange:    barChange,
+		})
+	}
+
+	return cs, nil
+}
+
+// LoadCues reads all WAV files in the given directory as cues, named after the file without its extension
+func LoadCues(dir string, sampleRate uint32) (map[string][]float32, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cue directory: %v", err)
+	}
+
+	cues := make(map[string][]float32)
+	for _, f := range files {
+		if f.IsDir() || strings.ToLower(filepath.Ext(f.Name())) != ".wav" {
+			continue
+		}
+
+		w, err := audio.OpenWAV(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cue %q: %v", f.Name(), err)
+		}
+
+		name := strings.TrimSuffix(f.Name(), filepath.Ext(f.Name()))
+		cues[name] = audio.Resample(w.Samples(), w.SampleRate(), float64(sampleRate))
+	}
+
+	return cues, nil
+}
+
+// clickSound returns a decaying sine at the given frequency
+func clickSound(rate, frequency, gain float64) []float32 {
+	samples := make([]float32, int(clickLength.Seconds()*rate))
+	for i := range samples {
+		t := float64(i) / rate
+		samples[i] = float32(gain * math.Exp(-clickDecay*t) * math.Sin(2*math.Pi*frequency*t))
+	}
+
+	return samples
+}
+
+func scale(samples []float32, gain float32) []float32 {
+	scaled := make([]float32, len(samples))
+	for i, s := range samples {
+		scaled[i] = s * gain
+	}
+
+	return scaled
+}
+
+// mix adds the given sound to the samples at the given index, cutting it off at the end of the samples
+func mix(samples, sound []float32, at int) {
+	for i, s := range sound {
+		if at+i >= len(samples) {
+			return
+		}
+		samples[at+i] += s
+	}
+}
+
+func sampleAt(d time.Duration, rate float64) int {
+	return int(math.Round(d.Seconds() * rate))
+}
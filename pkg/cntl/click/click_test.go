@@ -0,0 +1,199 @@
+package click
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/playback"
+)
+
+const testSampleRate = 8000
+
+func clickDataStore() *cntl.DataStore {
+	return &cntl.DataStore{
+		Songs: map[string]*cntl.Song{
+			"straight": {
+				ID: "straight",
+				BarChanges: []cntl.BarChange{
+					{At: 0, BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 120}},
+				},
+				End: "3.1",
+			},
+			"changing": {
+				ID: "changing",
+				BarChanges: []cntl.BarChange{
+					{At: 0, BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 120}},
+					{Position: "2.1", BarParams: cntl.BarParams{NoteCount: 3, NoteValue: 4, Speed: 60}},
+				},
+				End: "3.1",
+			},
+			"open": {
+				ID: "open",
+				BarChanges: []cntl.BarChange{
+					{At: 0, BarParams: cntl.BarParams{NoteCount: 2, NoteValue: 4, Speed: 120}},
+				},
+				MIDICommands: []cntl.MIDICommand{
+					{Position: "2.2", Status: 0x90, Data1: 60, Data2: 100},
+				},
+			},
+		},
+	}
+}
+
+// clicks returns the times of all clicks in the given samples and whether they are accented
+func clicks(samples []float32) ([]time.Duration, []bool) {
+	var times []time.Duration
+	var accents []bool
+
+	silence := 0
+	for i := 0; i < len(samples); i++ {
+		if samples[i] == 0 {
+			silence++
+			continue
+		}
+
+		// the sine of a click starts at zero
+		if len(times) == 0 && silence == i || silence > testSampleRate/100 {
+			var peak float64
+			for j := i; j < i+testSampleRate/100 && j < len(samples); j++ {
+				peak = math.Max(peak, math.Abs(float64(samples[j])))
+			}
+
+			times = append(times, time.Duration(float64(i-1)/testSampleRate*float64(time.Second)).Round(time.Millisecond))
+			accents = append(accents, peak > (accentGain+beatGain)/2)
+		}
+		silence = 0
+	}
+
+	return times, accents
+}
+
+func TestRender(t *testing.T) {
+	ms := time.Millisecond
+	exp := []struct {
+		songID  string
+		opts    Options
+		length  time.Duration
+		times   []time.Duration
+		accents []bool
+	}{
+		{
+			songID:  "straight",
+			opts:    Options{SampleRate: testSampleRate},
+			length:  4 * time.Second,
+			times:   []time.Duration{0, 500 * ms, 1000 * ms, 1500 * ms, 2000 * ms, 2500 * ms, 3000 * ms, 3500 * ms},
+			accents: []bool{true, false, false, false, true, false, false, false},
+		},
+		{
+			songID:  "straight",
+			opts:    Options{SampleRate: testSampleRate, CountIn: 1, Tempo: playback.TempoOptions{Multiplier: 2}},
+			length:  3 * time.Second,
+			times:   []time.Duration{0, 250 * ms, 500 * ms, 750 * ms, 1000 * ms, 1250 * ms, 1500 * ms, 1750 * ms, 2000 * ms, 2250 * ms, 2500 * ms, 2750 * ms},
+			accents: []bool{true, false, false, false, true, false, false, false, true, false, false, false},
+		},
+		{
+			songID:  "changing",
+			opts:    Options{SampleRate: testSampleRate},
+			length:  5 * time.Second,
+			times:   []time.Duration{0, 500 * ms, 1000 * ms, 1500 * ms, 2000 * ms, 3000 * ms, 4000 * ms},
+			accents: []bool{true, false, false, false, true, false, false},
+		},
+		{
+			songID:  "open",
+			opts:    Options{SampleRate: testSampleRate, Tempo: playback.TempoOptions{BPM: 60}},
+			length:  4 * time.Second,
+			times:   []time.Duration{0, 1000 * ms, 2000 * ms, 3000 * ms},
+			accents: []bool{true, false, true, false},
+		},
+	}
+
+	for i, e := range exp {
+		samples, err := Render(clickDataStore(), e.songID, e.opts)
+		if err != nil {
+			t.Fatalf("Unexpected error at index %d: %v", i, err)
+		}
+
+		if length := time.Duration(len(samples)) * time.Second / testSampleRate; length != e.length {
+			t.Errorf("Expected click track to last %v, got %v at index %d", e.length, length, i)
+		}
+
+		times, accents := clicks(samples)
+		if len(times) != len(e.times) {
+			t.Fatalf("Expected to get %d clicks, got %d (%v) at index %d", len(e.times), len(times), times, i)
+		}
+
+		for j := range times {
+			if times[j] != e.times[j] {
+				t.Errorf("Expected click %d at %v, got %v at index %d", j, e.times[j], times[j], i)
+			}
+			if accents[j] != e.accents[j] {
+				t.Errorf("Expected click %d to be accented %v, got %v at index %d", j, e.accents[j], accents[j], i)
+			}
+		}
+	}
+}
+
+func TestRender_Cues(t *testing.T) {
+	ds := clickDataStore()
+	ds.Songs["straight"].Sections = []cntl.SongSection{
+		{Name: "intro", Position: "1.1"},
+		{Name: "chorus", Position: "2.1"},
+	}
+
+	cue := make([]float32, testSampleRate/5)
+	for i := range cue {
+		cue[i] = 0.5
+	}
+
+	exp := []struct {
+		countIn uint8
+		cues    []string
+		at      []time.Duration
+	}{
+		{countIn: 0, cues: []string{"chorus"}, at: []time.Duration{0}},
+		{countIn: 1, cues: []string{"intro", "chorus"}, at: []time.Duration{0, 2 * time.Second}},
+		{countIn: 1, cues: []string{"verse"}, at: []time.Duration{}},
+	}
+
+	for i, e := range exp {
+		cues := make(map[string][]float32)
+		for _, name := range e.cues {
+			cues[name] = cue
+		}
+
+		samples, err := Render(ds, "straight", Options{SampleRate: testSampleRate, CountIn: e.countIn, Cues: cues})
+		if err != nil {
+			t.Fatalf("Unexpected error at index %d: %v", i, err)
+		}
+
+		var at []time.Duration
+		for bar := time.Duration(0); bar < time.Duration(len(samples))*time.Second/testSampleRate; bar += 2 * time.Second {
+			// between the clicks only the cue can be heard
+			if s := samples[int((bar+100*time.Millisecond).Seconds()*testSampleRate)]; math.Abs(float64(s)-0.5*cueGain) < 1e-6 {
+				at = append(at, bar)
+			}
+		}
+
+		if len(at) != len(e.at) {
+			t.Fatalf("Expected to get %d cues, got %v at index %d", len(e.at), at, i)
+		}
+		for j := range at {
+			if at[j] != e.at[j] {
+				t.Errorf("Expected cue %d at %v, got %v at index %d", j, e.at[j], at[j], i)
+			}
+		}
+	}
+}
+
+func TestRender_Invalid(t *testing.T) {
+	ds := clickDataStore()
+	ds.Songs["empty"] = &cntl.Song{ID: "empty"}
+
+	for i, songID := range []string{"unknown", "empty"} {
+		if _, err := Render(ds, songID, Options{}); err == nil {
+			t.Errorf("Expected to get an error at index %d", i)
+		}
+	}
+}
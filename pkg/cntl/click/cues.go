@@ -0,0 +1,54 @@
+package click
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// cueToneLength defines how long a single tone of a tone cue sounds
+	cueToneLength = 120 * time.Millisecond
+	// cueToneGap defines the silence between the tones of a tone cue
+	cueToneGap = 40 * time.Millisecond
+)
+
+// toneCueNotes are the frequencies of the tones of the bundled cues. Sections building up rise and sections calming
+// down fall, so they can be told apart without recorded samples.
+var toneCueNotes = map[string][]float64{
+	"intro":     {523.25},
+	"verse":     {659.25, 659.25},
+	"prechorus": {659.25, 783.99},
+	"chorus":    {523.25, 659.25, 783.99},
+	"bridge":    {783.99, 659.25, 523.25},
+	"solo":      {1046.5, 1046.5, 1046.5},
+	"breakdown": {392, 392},
+	"outro":     {783.99, 523.25},
+}
+
+// ToneCues returns the bundled tone cues of the common section names at the given sample rate.
+// No spoken cues are bundled, those need to be recorded and read with LoadCues.
+func ToneCues(sampleRate uint32) map[string][]float32 {
+	cues := make(map[string][]float32, len(toneCueNotes))
+	for name, notes := range toneCueNotes {
+		cues[name] = cueSound(float64(sampleRate), notes)
+	}
+
+	return cues
+}
+
+// cueSound returns a tone for every given frequency, faded in and out so they don't click
+func cueSound(rate float64, frequencies []float64) []float32 {
+	tone := int(cueToneLength.Seconds() * rate)
+	step := tone + int(cueToneGap.Seconds()*rate)
+
+	samples := make([]float32, len(frequencies)*step)
+	for n, frequency := range frequencies {
+		for i := 0; i < tone; i++ {
+			t := float64(i) / rate
+			envelope := math.Sin(math.Pi * float64(i) / float64(tone))
+			samples[n*step+i] = float32(envelope * math.Sin(2*math.Pi*frequency*t))
+		}
+	}
+
+	return samples
+}
@@ -0,0 +1,56 @@
+package click
+
+import (
+	"testing"
+	"time"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+)
+
+func TestToneCues(t *testing.T) {
+	cues := ToneCues(testSampleRate)
+
+	exp := []struct {
+		name  string
+		tones int
+	}{
+		{"intro", 1},
+		{"verse", 2},
+		{"chorus", 3},
+		{"outro", 2},
+	}
+
+	step := (cueToneLength + cueToneGap).Seconds() * testSampleRate
+	for i, e := range exp {
+		cue, ok := cues[e.name]
+		if !ok {
+			t.Errorf("Expected to get a tone cue %q at index %d", e.name, i)
+			continue
+		}
+
+		if len(cue) != e.tones*int(step) {
+			t.Errorf("Expected cue %q to have %d tones at index %d, got %d samples", e.name, e.tones, i, len(cue))
+		}
+	}
+}
+
+func TestRender_ToneCues(t *testing.T) {
+	ds := clickDataStore()
+	ds.Songs["straight"].Sections = []cntl.SongSection{{Name: "Chorus", Position: "2.1"}}
+
+	with, err := Render(ds, "straight", Options{SampleRate: testSampleRate, Cues: ToneCues(testSampleRate)})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	without, err := Render(ds, "straight", Options{SampleRate: testSampleRate})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// section names are matched case insensitively, the cue is played in the first bar, before the chorus
+	at := int((100 * time.Millisecond).Seconds() * testSampleRate)
+	if with[at] == without[at] {
+		t.Errorf("Expected the tone cue of the chorus to be played at the start of the first bar")
+	}
+}
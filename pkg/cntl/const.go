@@ -35,8 +35,47 @@ const (
 	EaseBounceInOut   EaseFunc = "InOutBounce"
 )
 
-// RenderFrames defines the smallest render unit of a bar. Has to be multiplier of 4.
-const RenderFrames uint8 = 64
+// preset parameter types
+const (
+	PresetParamColor  DMXPresetParamType = "color"
+	PresetParamDimmer DMXPresetParamType = "dimmer"
+	PresetParamGroup  DMXPresetParamType = "group"
+)
+
+// timecode rates, 29.97 is drop frame
+const (
+	TimecodeRate24   TimecodeRate = "24"
+	TimecodeRate25   TimecodeRate = "25"
+	TimecodeRate2997 TimecodeRate = "29.97"
+	TimecodeRate30   TimecodeRate = "30"
+)
+
+// MIDI message types of a MIDIMapping
+const (
+	MIDIMessageNote          MIDIMessageType = "note"
+	MIDIMessageControlChange MIDIMessageType = "controlChange"
+	MIDIMessageProgramChange MIDIMessageType = "programChange"
+)
+
+// MIDIMapping actions, flashes last while a note is held and the master fader follows the value of the message
+const (
+	MIDIActionScene    MIDIMappingAction = "scene"
+	MIDIActionPreset   MIDIMappingAction = "preset"
+	MIDIActionFlash    MIDIMappingAction = "flash"
+	MIDIActionMaster   MIDIMappingAction = "master"
+	MIDIActionNextSong MIDIMappingAction = "nextSong"
+	MIDIActionBlackout MIDIMappingAction = "blackout"
+)
+
+// RenderFrames defines the smallest render unit of a bar. It is divisible by all straight note values
+// up to 64th notes as well as by the triplet note values 3, 6, 12, 24 and 48, and allows dotted notes down to 32nd notes.
+const RenderFrames uint8 = 192
+
+// Song format versions, songs without a format version were stored on a bar grid of LegacyRenderFrames frames
+const (
+	SongFormatVersion  uint8 = 1
+	LegacyRenderFrames uint8 = 64
+)
 
 // Logger fields
 const (
@@ -12,6 +12,8 @@ type DataStore struct {
 	DMXDeviceTypes    map[string]*DMXDeviceType
 	DMXDeviceGroups   map[string]*DMXDeviceGroup
 	DMXColorVariables map[string]*DMXColorVariable
+	DMXPalettes       map[string]*DMXPalette
+	MIDIMappings      map[string]*MIDIMapping
 }
 
 // NewStore creates a new DataStore instance
@@ -27,5 +29,7 @@ func NewStore() *DataStore {
 		DMXDeviceTypes:    make(map[string]*DMXDeviceType),
 		DMXDeviceGroups:   make(map[string]*DMXDeviceGroup),
 		DMXColorVariables: make(map[string]*DMXColorVariable),
+		DMXPalettes:       make(map[string]*DMXPalette),
+		MIDIMappings:      make(map[string]*MIDIMapping),
 	}
 }
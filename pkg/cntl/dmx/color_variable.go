@@ -0,0 +1,33 @@
+package dmx
+
+import "github.com/StageAutoControl/controller/pkg/cntl"
+
+// OverrideColorVariables returns a copy of the given DataStore where the color variables are replaced by the given
+// overrides of the same name. Overrides without a global counterpart are added.
+func OverrideColorVariables(ds *cntl.DataStore, overrides []cntl.DMXColorVariable) *cntl.DataStore {
+	if len(overrides) == 0 {
+		return ds
+	}
+
+	o := *ds
+	o.DMXColorVariables = make(map[string]*cntl.DMXColorVariable, len(ds.DMXColorVariables))
+	for id, c := range ds.DMXColorVariables {
+		o.DMXColorVariables[id] = c
+	}
+
+	for i := range overrides {
+		c := &overrides[i]
+		key := c.Name
+
+		for id, global := range ds.DMXColorVariables {
+			if global.Name == c.Name {
+				key = id
+				break
+			}
+		}
+
+		o.DMXColorVariables[key] = c
+	}
+
+	return &o
+}
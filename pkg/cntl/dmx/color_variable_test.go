@@ -0,0 +1,58 @@
+package dmx
+
+import (
+	"testing"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+
+	"github.com/StageAutoControl/controller/pkg/internal/fixtures"
+)
+
+func TestOverrideColorVariables(t *testing.T) {
+	ds := fixtures.DataStore()
+	songDS := OverrideColorVariables(ds, []cntl.DMXColorVariable{
+		{Name: "Red255", Green: fixtures.Value255},
+		{Name: "Primary", Blue: fixtures.Value127},
+	})
+	nestedDS := OverrideColorVariables(songDS, []cntl.DMXColorVariable{
+		{Name: "Red255", White: fixtures.Value31},
+	})
+
+	exp := []struct {
+		ds    *cntl.DataStore
+		name  string
+		color *cntl.DMXColorVariable
+	}{
+		{ds, "Red255", &cntl.DMXColorVariable{Red: fixtures.Value255}},
+		{songDS, "Red255", &cntl.DMXColorVariable{Green: fixtures.Value255}},
+		{songDS, "Primary", &cntl.DMXColorVariable{Blue: fixtures.Value127}},
+		{songDS, "Blue255", &cntl.DMXColorVariable{Blue: fixtures.Value255}},
+		{nestedDS, "Red255", &cntl.DMXColorVariable{White: fixtures.Value31}},
+		{ds, "Primary", nil},
+	}
+
+	for i, e := range exp {
+		c := getColorVar(e.ds, e.name)
+		if e.color == nil {
+			if c != nil {
+				t.Errorf("Expected to find no color %q at index %d, got %+v", e.name, i, c)
+			}
+			continue
+		}
+
+		if c == nil {
+			t.Fatalf("Expected to find color %q at index %d", e.name, i)
+		}
+
+		if !c.Red.Equals(e.color.Red) && (c.Red != nil || e.color.Red != nil) ||
+			!c.Green.Equals(e.color.Green) && (c.Green != nil || e.color.Green != nil) ||
+			!c.Blue.Equals(e.color.Blue) && (c.Blue != nil || e.color.Blue != nil) ||
+			!c.White.Equals(e.color.White) && (c.White != nil || e.color.White != nil) {
+			t.Errorf("Expected color %q to be %+v, got %+v at index %d", e.name, e.color, c, i)
+		}
+	}
+
+	if len(nestedDS.DMXColorVariables) != len(ds.DMXColorVariables)+1 {
+		t.Errorf("Expected overrides to replace existing colors, got %d colors", len(nestedDS.DMXColorVariables))
+	}
+}
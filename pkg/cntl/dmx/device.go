@@ -6,7 +6,8 @@ import (
 	"github.com/StageAutoControl/controller/pkg/cntl"
 )
 
-func getDeviceChannel(ds *cntl.DataStore, d *cntl.DMXDevice, c cntl.DMXChannel, led uint16) (cntl.DMXChannel, error) {
+// GetDeviceChannel returns the absolute DMX channel of the given channel type and LED on the given device
+func GetDeviceChannel(ds *cntl.DataStore, d *cntl.DMXDevice, c cntl.DMXChannel, led uint16) (cntl.DMXChannel, error) {
 	dt, ok := ds.DMXDeviceTypes[d.TypeID]
 	if !ok {
 		return 0, fmt.Errorf("given DeviceType %q on device %q is unknown", d.TypeID, d.ID)
@@ -63,6 +64,9 @@ func getDeviceChannel(ds *cntl.DataStore, d *cntl.DMXDevice, c cntl.DMXChannel,
 		if !dt.Moving {
 			return 0, ErrDeviceIsNotMoving
 		}
+		if !dt.TiltFineEnabled {
+			return 0, ErrDeviceHasDisabledTiltFineChannel
+		}
 		channel = dt.TiltFineChannel
 
 	case ChannelPan:
@@ -75,6 +79,9 @@ func getDeviceChannel(ds *cntl.DataStore, d *cntl.DMXDevice, c cntl.DMXChannel,
 		if !dt.Moving {
 			return 0, ErrDeviceIsNotMoving
 		}
+		if !dt.PanFineEnabled {
+			return 0, ErrDeviceHasDisabledPanFineChannel
+		}
 		channel = dt.PanFineChannel
 
 	case ChannelPanTiltSpeed:
@@ -112,6 +119,26 @@ func ResolveDeviceSelector(ds *cntl.DataStore, sel *cntl.DMXDeviceSelector) ([]*
 	return []*cntl.DMXDevice{}, ErrDeviceSelectorMustHaveTagsOrID
 }
 
+// ResolveDeviceGroup returns all DMXDevices of the given DMXDeviceGroup
+func ResolveDeviceGroup(ds *cntl.DataStore, groupID string) ([]*cntl.DMXDevice, error) {
+	g, ok := ds.DMXDeviceGroups[groupID]
+	if !ok {
+		return []*cntl.DMXDevice{}, fmt.Errorf("failed to find DMXDeviceGroup %q", groupID)
+	}
+
+	var dd []*cntl.DMXDevice
+	for _, sel := range g.Devices {
+		d, err := ResolveDeviceSelector(ds, &sel)
+		if err != nil {
+			return []*cntl.DMXDevice{}, err
+		}
+
+		dd = append(dd, d...)
+	}
+
+	return dd, nil
+}
+
 // ResolveDevicesByTags returns all DMXDevices that match *all* of the given tags
 func ResolveDevicesByTags(ds *cntl.DataStore, tags []cntl.Tag) (dd []*cntl.DMXDevice) {
 	var matches [][]*cntl.DMXDevice
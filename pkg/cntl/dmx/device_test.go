@@ -94,7 +94,7 @@ func TestGetDeviceChannel(t *testing.T) {
 	}
 
 	for i, e := range exp {
-		res, err := getDeviceChannel(ds, e.d, e.c, e.led)
+		res, err := GetDeviceChannel(ds, e.d, e.c, e.led)
 		if e.err != nil && (err == nil || err.Error() != e.err.Error()) {
 			t.Fatalf("Expected to get error %v, got %v", e.err, err)
 		}
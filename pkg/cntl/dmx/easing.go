@@ -8,10 +8,11 @@ import (
 	"github.com/creasty/go-easing"
 )
 
-type easingFunc easing.EaseFunc
+// EasingFunc maps a progress between 0 and 1 to an eased progress
+type EasingFunc easing.EaseFunc
 
-// getEasingFunc returns the easingFunc for a given cntl.EaseFunc function name
-func getEasingFunc(easingFunc cntl.EaseFunc) (easingFunc, error) {
+// GetEasingFunc returns the EasingFunc for a given cntl.EaseFunc function name
+func GetEasingFunc(easingFunc cntl.EaseFunc) (EasingFunc, error) {
 	switch easingFunc {
 	case cntl.EaseLinear:
 		return easing.Linear, nil
@@ -23,7 +23,7 @@ func MergeAtOffset(cmds []cntl.DMXCommands, cs []cntl.DMXCommands, offset int) [
 }
 
 // MergeWithFrameChange merges two given DMXCommand slices, respecting that the second one is not in the right renderFrames setting
-// Like, cs is in 16th and RenderFrames ist 64th we have to add spacing while merging
+// Like, cs is in 16th and RenderFrames are 192 per bar, we have to add spacing while merging
 func MergeWithFrameChange(cmds []cntl.DMXCommands, cs []cntl.DMXCommands, paramsNoteValue uint8) []cntl.DMXCommands {
 	spacing := int(cntl.RenderFrames / paramsNoteValue)
 	for sourceIndex, cmd := range cs {
@@ -42,7 +42,7 @@ func TestMergeWithFrameChange(t *testing.T) {
 		cmds, cs, e     []cntl.DMXCommands
 	}{
 		{
-			paramsNoteValue: 16,
+			paramsNoteValue: 48,
 			cmds: []cntl.DMXCommands{
 				{{Universe: 0, Channel: 255, Value: cntl.DMXValue{Value: 12}}},
 				{{Universe: 45, Channel: 200, Value: cntl.DMXValue{Value: 15}}},
@@ -63,7 +63,7 @@ func TestMergeWithFrameChange(t *testing.T) {
 			},
 		},
 		{
-			paramsNoteValue: 16,
+			paramsNoteValue: 48,
 			cmds: []cntl.DMXCommands{
 				{{Universe: 0, Channel: 255, Value: cntl.DMXValue{Value: 12}}},
 				{{Universe: 45, Channel: 200, Value: cntl.DMXValue{Value: 15}}},
@@ -84,7 +84,7 @@ func TestMergeWithFrameChange(t *testing.T) {
 			},
 		},
 		{
-			paramsNoteValue: 16,
+			paramsNoteValue: 48,
 			cmds:            []cntl.DMXCommands{},
 			cs: []cntl.DMXCommands{
 				{{Universe: 10, Channel: 15, Value: cntl.DMXValue{Value: 1}}},
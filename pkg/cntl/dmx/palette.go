@@ -0,0 +1,100 @@
+package dmx
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+)
+
+func hasPalette(p cntl.DMXParams) bool {
+	return p.Palette != nil && *p.Palette != ""
+}
+
+// resolvePalette sets the values of the palette referenced by the given params for the given device,
+// skipping the channels the device doesn't have, as a palette may be shared by different device types
+func resolvePalette(ds *cntl.DataStore, p *cntl.DMXParams, d *cntl.DMXDevice) error {
+	if !hasPalette(*p) {
+		return nil
+	}
+
+	if p.Pan != nil || p.Tilt != nil || p.Strobe != nil || p.Mode != nil {
+		return ErrDeviceParamsPaletteMustBeExclusive
+	}
+
+	palette, err := getPalette(ds, *p.Palette, d)
+	if err != nil {
+		return err
+	}
+	if palette == nil {
+		return fmt.Errorf("failed to find palette with the name %q for device %q", *p.Palette, d.ID)
+	}
+
+	dt, ok := ds.DMXDeviceTypes[d.TypeID]
+	if !ok {
+		return fmt.Errorf("given DeviceType %q on device %q is unknown", d.TypeID, d.ID)
+	}
+
+	if dt.Moving {
+		p.Pan = palette.Pan
+		p.Tilt = palette.Tilt
+	}
+	if dt.StrobeEnabled {
+		p.Strobe = palette.Strobe
+	}
+	if dt.ModeEnabled {
+		p.Mode = palette.Mode
+	}
+
+	return nil
+}
+
+// getPalette returns the palette with the given name that matches the given device most specifically,
+// meaning a palette of the device itself wins over a palette of one of its groups, which wins over a global one.
+// If a device is in several groups with a palette of that name, the palette of the group with the lowest ID wins,
+// other ambiguities are resolved by the lowest palette ID.
+func getPalette(ds *cntl.DataStore, name string, d *cntl.DMXDevice) (*cntl.DMXPalette, error) {
+	ids := make([]string, 0, len(ds.DMXPalettes))
+	for id := range ds.DMXPalettes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var group, global *cntl.DMXPalette
+	for _, id := range ids {
+		p := ds.DMXPalettes[id]
+		if p.Name != name {
+			continue
+		}
+
+		switch {
+		case p.Device != nil:
+			if *p.Device == d.ID {
+				return p, nil
+			}
+
+		case p.Group != nil:
+			if group != nil && *group.Group <= *p.Group {
+				continue
+			}
+
+			dd, err := ResolveDeviceGroup(ds, *p.Group)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve group of palette %q: %v", p.ID, err)
+			}
+
+			if has(dd, d) {
+				group = p
+			}
+
+		case global == nil:
+			global = p
+		}
+	}
+
+	if group != nil {
+		return group, nil
+	}
+
+	return global, nil
+}
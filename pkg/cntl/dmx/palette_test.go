@@ -0,0 +1,143 @@
+package dmx
+
+import (
+	"testing"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+
+	"github.com/StageAutoControl/controller/pkg/internal/fixtures"
+)
+
+func TestRenderParams_Palette(t *testing.T) {
+	ds := fixtures.DataStore()
+	bar := ds.DMXDevices["35cae00a-0b17-11e7-8bca-bbf30c56f20e"]
+	par := ds.DMXDevices["s429fc37c-0b17-11e7-8b94-c3b6519355d3"]
+
+	// a device without strobe and mode channels only gets the values of the palette it has channels for
+	ds.DMXDeviceTypes["dimmer"] = &cntl.DMXDeviceType{ID: "dimmer", DimmerEnabled: true, DimmerChannel: 4, LEDs: []cntl.LED{{Red: 0, Green: 1, Blue: 2, White: 3}}}
+	dimmer := &cntl.DMXDevice{ID: "dimmer", TypeID: "dimmer", Universe: 3}
+	ds.DMXDevices[dimmer.ID] = dimmer
+
+	exp := []struct {
+		dd  []*cntl.DMXDevice
+		p   cntl.DMXParams
+		c   cntl.DMXCommands
+		err error
+	}{
+		{
+			dd: []*cntl.DMXDevice{bar, par},
+			p:  cntl.DMXParams{Palette: fixtures.StrPtr("Drums strobe")},
+			c: cntl.DMXCommands{
+				{Universe: 1, Channel: 224, Value: *fixtures.Value127},
+				{Universe: 1, Channel: 222, Value: *fixtures.Value31},
+				{Universe: 2, Channel: 14, Value: *fixtures.Value255},
+			},
+		},
+		{
+			dd: []*cntl.DMXDevice{dimmer, par},
+			p:  cntl.DMXParams{Palette: fixtures.StrPtr("Drums strobe"), Dimmer: fixtures.Value255},
+			c: cntl.DMXCommands{
+				{Universe: 3, Channel: 4, Value: *fixtures.Value255},
+				{Universe: 2, Channel: 14, Value: *fixtures.Value255},
+				{Universe: 2, Channel: 13, Value: *fixtures.Value255},
+			},
+		},
+		{
+			dd:  []*cntl.DMXDevice{bar},
+			p:   cntl.DMXParams{Palette: fixtures.StrPtr("Drums strobe"), Strobe: fixtures.Value0},
+			err: ErrDeviceParamsPaletteMustBeExclusive,
+		},
+	}
+
+	for i, e := range exp {
+		c, err := RenderParams(ds, e.dd, e.p)
+		if err != e.err {
+			t.Fatalf("Expected to get error %v, got %v at index %d", e.err, err, i)
+		}
+
+		if !c.Equals(e.c) {
+			t.Errorf("Expected to get %+v, got %+v at index %d", e.c, c, i)
+		}
+	}
+}
+
+func TestGetPalette(t *testing.T) {
+	ds := fixtures.DataStore()
+	exp := []struct {
+		name string
+		d    *cntl.DMXDevice
+		id   string
+	}{
+		{"Drums strobe", ds.DMXDevices["35cae00a-0b17-11e7-8bca-bbf30c56f20e"], "f1c2a6c4-6b0e-4c55-9f43-8a2c0b1fd8e7"},
+		{"Drums strobe", ds.DMXDevices["6f7bca8a-0b17-11e7-b604-a356da737e54"], "8b0e6c1a-2f6e-4a2b-b1b0-6f0a8e4c2d11"},
+		{"Unknown", ds.DMXDevices["35cae00a-0b17-11e7-8bca-bbf30c56f20e"], ""},
+	}
+
+	for i, e := range exp {
+		p, err := getPalette(ds, e.name, e.d)
+		if err != nil {
+			t.Fatalf("Unexpected error at index %d: %v", i, err)
+		}
+
+		if e.id == "" && p != nil {
+			t.Errorf("Expected to find no palette at index %d, got %q", i, p.ID)
+		}
+		if e.id != "" && (p == nil || p.ID != e.id) {
+			t.Errorf("Expected to find palette %q at index %d, got %+v", e.id, i, p)
+		}
+	}
+}
+
+// paletteDataStore returns a moving head on a drum riser, which is also part of the stage group
+func paletteDataStore() *cntl.DataStore {
+	ds := cntl.NewStore()
+	ds.DMXDeviceTypes["moving-head"] = &cntl.DMXDeviceType{
+		ID:            "moving-head",
+		DimmerEnabled: true,
+		DimmerChannel: 0,
+		Moving:        true,
+		PanChannel:    1,
+		TiltChannel:   2,
+		LEDs:          []cntl.LED{{Red: 3, Green: 4, Blue: 5, White: 6}},
+	}
+	ds.DMXDevices["head"] = &cntl.DMXDevice{ID: "head", TypeID: "moving-head", Universe: 4, StartChannel: 10}
+	ds.DMXDeviceGroups["riser"] = &cntl.DMXDeviceGroup{ID: "riser", Devices: []cntl.DMXDeviceSelector{{ID: "head"}}}
+	ds.DMXDeviceGroups["stage"] = &cntl.DMXDeviceGroup{ID: "stage", Devices: []cntl.DMXDeviceSelector{{ID: "head"}}}
+	ds.DMXPalettes["b-riser"] = &cntl.DMXPalette{ID: "b-riser", Name: "Drum riser", Group: fixtures.StrPtr("riser"), Pan: fixtures.Value63, Tilt: fixtures.Value200}
+	ds.DMXPalettes["a-stage"] = &cntl.DMXPalette{ID: "a-stage", Name: "Drum riser", Group: fixtures.StrPtr("stage"), Pan: fixtures.Value255, Tilt: fixtures.Value0}
+
+	return ds
+}
+
+func TestRenderParams_PalettePosition(t *testing.T) {
+	ds := paletteDataStore()
+
+	c, err := RenderParams(ds, []*cntl.DMXDevice{ds.DMXDevices["head"]}, cntl.DMXParams{Palette: fixtures.StrPtr("Drum riser")})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	exp := cntl.DMXCommands{
+		{Universe: 4, Channel: 12, Value: *fixtures.Value200},
+		{Universe: 4, Channel: 11, Value: *fixtures.Value63},
+	}
+	if !c.Equals(exp) {
+		t.Errorf("Expected to get %+v, got %+v", exp, c)
+	}
+}
+
+func TestGetPalette_AmbiguousGroups(t *testing.T) {
+	ds := paletteDataStore()
+
+	// the palette of the group with the lowest ID wins, no matter the order of the palettes
+	for i := 0; i < 20; i++ {
+		p, err := getPalette(ds, "Drum riser", ds.DMXDevices["head"])
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if p == nil || p.ID != "b-riser" {
+			t.Fatalf("Expected to get palette b-riser of the riser group at run %d, got %+v", i, p)
+		}
+	}
+}
@@ -38,28 +38,28 @@ func checkDeviceParams(dp *cntl.DMXDeviceParams) error {
 	return nil
 }
 
+// paramsSubstitution replaces references within the given params, e.g. to the parameters of a preset
+type paramsSubstitution func(cntl.DMXParams) (cntl.DMXParams, error)
+
 // RenderDeviceParams renders the given DMXDeviceParams to an array of DMXCommands to be sent to a DMX device
 func RenderDeviceParams(ds *cntl.DataStore, dp *cntl.DMXDeviceParams) ([]cntl.DMXCommands, error) {
+	return renderDeviceParams(ds, dp, nil)
+}
+
+// renderDeviceParams renders the given DMXDeviceParams, substituting the params of its animation or transition if given
+func renderDeviceParams(ds *cntl.DataStore, dp *cntl.DMXDeviceParams, substitute paramsSubstitution) ([]cntl.DMXCommands, error) {
 	if err := checkDeviceParams(dp); err != nil {
 		return []cntl.DMXCommands{}, err
 	}
 
 	var dd []*cntl.DMXDevice
 	if dp.Group != nil {
-
-		g, ok := ds.DMXDeviceGroups[*dp.Group]
-		if !ok {
-			return []cntl.DMXCommands{}, fmt.Errorf("failed to find DMXDeviceGroup %q", *dp.Group)
+		d, err := ResolveDeviceGroup(ds, *dp.Group)
+		if err != nil {
+			return []cntl.DMXCommands{}, err
 		}
 
-		for _, sel := range g.Devices {
-			d, err := ResolveDeviceSelector(ds, &sel)
-			if err != nil {
-				return []cntl.DMXCommands{}, err
-			}
-
-			dd = append(dd, d...)
-		}
+		dd = append(dd, d...)
 	}
 
 	if dp.Device != nil {
@@ -81,13 +81,27 @@ func RenderDeviceParams(ds *cntl.DataStore, dp *cntl.DMXDeviceParams) ([]cntl.DM
 			return []cntl.DMXCommands{}, fmt.Errorf("failed to find DMXAnimation %q", *dp.Animation)
 		}
 
+		if substitute != nil {
+			var err error
+			if a, err = substituteAnimation(a, substitute); err != nil {
+				return []cntl.DMXCommands{}, err
+			}
+		}
+
 		return RenderAnimation(ds, dd, a)
 	}
 
 	if dp.Transition != nil {
 		t, ok := ds.DMXTransitions[*dp.Transition]
 		if !ok {
-			return []cntl.DMXCommands{}, fmt.Errorf("failed to find DMXTransition %q", *dp.Animation)
+			return []cntl.DMXCommands{}, fmt.Errorf("failed to find DMXTransition %q", *dp.Transition)
+		}
+
+		if substitute != nil {
+			var err error
+			if t, err = substituteTransition(t, substitute); err != nil {
+				return []cntl.DMXCommands{}, err
+			}
 		}
 
 		return RenderTransition(ds, dd, t)
@@ -113,12 +127,41 @@ func RenderDeviceParams(ds *cntl.DataStore, dp *cntl.DMXDeviceParams) ([]cntl.DM
 
 // RenderParams renders the given DMXParams to an array of DMXCommands to be sent to a DMX device
 func RenderParams(ds *cntl.DataStore, dd []*cntl.DMXDevice, p cntl.DMXParams) (cmds cntl.DMXCommands, err error) {
-	var channels cntl.DMXCommands
+	if p.DimmerVar != nil {
+		return cntl.DMXCommands{}, ErrDeviceParamsDimmerVarOutsidePreset
+	}
 
 	if err := resolveColorVar(ds, &p); err != nil {
 		return cntl.DMXCommands{}, err
 	}
 
+	// for each device in the resolved selectors and each channel set the correct LED value
+	for _, d := range dd {
+		dp := p
+		if err := resolvePalette(ds, &dp, d); err != nil {
+			return cntl.DMXCommands{}, err
+		}
+
+		for _, c := range renderChannels(dp) {
+			for _, led := range resolveLEDs(ds, dp, d) {
+				ch, err := GetDeviceChannel(ds, d, c.Channel, led)
+				if err != nil {
+					return cntl.DMXCommands{}, err
+				}
+				cmds = append(cmds, cntl.DMXCommand{
+					Universe: d.Universe,
+					Channel:  ch,
+					Value:    c.Value,
+				})
+			}
+		}
+	}
+
+	return
+}
+
+// renderChannels returns a command for every channel type that is set in the given params
+func renderChannels(p cntl.DMXParams) (channels cntl.DMXCommands) {
 	if p.Red != nil {
 		channels = append(channels, cntl.DMXCommand{
 			Channel: ChannelRed,
@@ -174,23 +217,6 @@ func RenderParams(ds *cntl.DataStore, dd []*cntl.DMXDevice, p cntl.DMXParams) (c
 		})
 	}
 
-	// for each device in the resolved selectors and each channel set the correct LED value
-	for _, d := range dd {
-		for _, c := range channels {
-			for _, led := range resolveLEDs(ds, p, d) {
-				ch, err := getDeviceChannel(ds, d, c.Channel, led)
-				if err != nil {
-					return cntl.DMXCommands{}, err
-				}
-				cmds = append(cmds, cntl.DMXCommand{
-					Universe: d.Universe,
-					Channel:  ch,
-					Value:    c.Value,
-				})
-			}
-		}
-	}
-
 	return
 }
 
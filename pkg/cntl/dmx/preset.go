@@ -1,16 +1,36 @@
 package dmx
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/StageAutoControl/controller/pkg/cntl"
 )
 
+// presetParamPrefix marks a reference to a preset parameter within the device params of a preset
+const presetParamPrefix = "$"
+
 // RenderPreset renders a preset and returns an array of commands for every frame
-func RenderPreset(ds *cntl.DataStore, p *cntl.DMXPreset) ([]cntl.DMXCommands, error) {
+func RenderPreset(ds *cntl.DataStore, p *cntl.DMXPreset, args cntl.DMXPresetArgs) ([]cntl.DMXCommands, error) {
+	values, err := ResolvePresetArgs(p, args)
+	if err != nil {
+		return []cntl.DMXCommands{}, err
+	}
+
 	var cmds []cntl.DMXCommands
 	for _, dp := range p.DeviceParams {
-		dpcs, err := RenderDeviceParams(ds, &dp)
+		dp, err := substitutePresetArgs(p, dp, values)
+		if err != nil {
+			return []cntl.DMXCommands{}, fmt.Errorf("failed to handle preset %q: %v", p.ID, err)
+		}
+
+		substitute := func(param cntl.DMXParams) (cntl.DMXParams, error) {
+			return substituteParams(p, param, values)
+		}
+
+		dpcs, err := renderDeviceParams(ds, &dp, substitute)
 		if err != nil {
 			return []cntl.DMXCommands{}, fmt.Errorf("failed to handle preset %q: %v", p.ID, err)
 		}
@@ -20,3 +40,241 @@ func RenderPreset(ds *cntl.DataStore, p *cntl.DMXPreset) ([]cntl.DMXCommands, er
 
 	return cmds, nil
 }
+
+// ResolvePresetArgs validates the given arguments against the parameters of the given preset
+// and returns the value of every parameter, falling back to its default.
+func ResolvePresetArgs(p *cntl.DMXPreset, args cntl.DMXPresetArgs) (cntl.DMXPresetArgs, error) {
+	values := make(cntl.DMXPresetArgs)
+
+	for name := range args {
+		if getPresetParam(p, name) == nil {
+			return nil, fmt.Errorf("preset %q has no parameter %q", p.ID, name)
+		}
+	}
+
+	for _, param := range p.Params {
+		value, ok := args[param.Name]
+		if !ok {
+			if param.Default == nil {
+				return nil, fmt.Errorf("missing required argument %q for preset %q", param.Name, p.ID)
+			}
+
+			value = *param.Default
+		}
+
+		if param.Type == cntl.PresetParamDimmer {
+			if _, err := parseDimmerArg(value); err != nil {
+				return nil, fmt.Errorf("invalid argument %q for preset %q: %v", param.Name, p.ID, err)
+			}
+		}
+
+		values[param.Name] = value
+	}
+
+	return values, nil
+}
+
+func getPresetParam(p *cntl.DMXPreset, name string) *cntl.DMXPresetParam {
+	for i := range p.Params {
+		if p.Params[i].Name == name {
+			return &p.Params[i]
+		}
+	}
+
+	return nil
+}
+
+func parseDimmerArg(value string) (*cntl.DMXValue, error) {
+	v, err := strconv.ParseUint(value, 10, 8)
+	if err != nil {
+		return nil, fmt.Errorf("dimmer needs to be a value between 0 and 255, got %q", value)
+	}
+
+	return &cntl.DMXValue{Value: uint8(v)}, nil
+}
+
+// resolvePresetRef returns the argument value when the given reference points to a preset parameter of the given type
+func resolvePresetRef(p *cntl.DMXPreset, ref *string, t cntl.DMXPresetParamType, values cntl.DMXPresetArgs) (*string, error) {
+	if ref == nil || !strings.HasPrefix(*ref, presetParamPrefix) {
+		return ref, nil
+	}
+
+	name := strings.TrimPrefix(*ref, presetParamPrefix)
+	param := getPresetParam(p, name)
+	if param == nil {
+		return nil, fmt.Errorf("reference to unknown parameter %q", name)
+	}
+
+	if param.Type != t {
+		return nil, fmt.Errorf("parameter %q is of type %q but used as %q", name, param.Type, t)
+	}
+
+	value := values[name]
+	return &value, nil
+}
+
+// substitutePresetArgs returns a copy of the given device params with all parameter references replaced by their values
+func substitutePresetArgs(p *cntl.DMXPreset, dp cntl.DMXDeviceParams, values cntl.DMXPresetArgs) (cntl.DMXDeviceParams, error) {
+	var err error
+	if dp.Group, err = resolvePresetRef(p, dp.Group, cntl.PresetParamGroup, values); err != nil {
+		return dp, err
+	}
+
+	if dp.Params == nil {
+		return dp, nil
+	}
+
+	params := make([]cntl.DMXParams, len(dp.Params))
+	for i, param := range dp.Params {
+		if params[i], err = substituteParams(p, param, values); err != nil {
+			return dp, err
+		}
+	}
+	dp.Params = params
+
+	return dp, nil
+}
+
+// substituteParams returns the given params with the color and dimmer references replaced by their values
+func substituteParams(p *cntl.DMXPreset, param cntl.DMXParams, values cntl.DMXPresetArgs) (cntl.DMXParams, error) {
+	var err error
+	if param.ColorVar, err = resolvePresetRef(p, param.ColorVar, cntl.PresetParamColor, values); err != nil {
+		return param, err
+	}
+
+	if param.DimmerVar == nil {
+		return param, nil
+	}
+
+	if param.Dimmer != nil {
+		return param, ErrDeviceParamsDimmerVarMustBeExclusive
+	}
+
+	value, err := resolvePresetRef(p, dimmerRef(param.DimmerVar), cntl.PresetParamDimmer, values)
+	if err != nil {
+		return param, err
+	}
+
+	if param.Dimmer, err = parseDimmerArg(*value); err != nil {
+		return param, err
+	}
+	param.DimmerVar = nil
+
+	return param, nil
+}
+
+// dimmerRef returns the given dimmer var as reference, as it always refers to a preset parameter and the $ is optional
+func dimmerRef(dimmerVar *string) *string {
+	ref := presetParamPrefix + strings.TrimPrefix(*dimmerVar, presetParamPrefix)
+	return &ref
+}
+
+// substituteAnimation returns a copy of the given animation with all its params substituted
+func substituteAnimation(a *cntl.DMXAnimation, substitute paramsSubstitution) (*cntl.DMXAnimation, error) {
+	c := *a
+	c.Frames = make([]cntl.DMXAnimationFrame, len(a.Frames))
+	for i, f := range a.Frames {
+		var err error
+		if f.Params, err = substitute(f.Params); err != nil {
+			return nil, fmt.Errorf("failed to substitute frame %d of animation %q: %v", i, a.ID, err)
+		}
+		c.Frames[i] = f
+	}
+
+	return &c, nil
+}
+
+// substituteTransition returns a copy of the given transition with all its params substituted
+func substituteTransition(t *cntl.DMXTransition, substitute paramsSubstitution) (*cntl.DMXTransition, error) {
+	c := *t
+	c.Params = make([]cntl.DMXTransitionParams, len(t.Params))
+	for i, tp := range t.Params {
+		var err error
+		if tp.From, err = substitute(tp.From); err != nil {
+			return nil, fmt.Errorf("failed to substitute param %d of transition %q: %v", i, t.ID, err)
+		}
+		if tp.To, err = substitute(tp.To); err != nil {
+			return nil, fmt.Errorf("failed to substitute param %d of transition %q: %v", i, t.ID, err)
+		}
+		c.Params[i] = tp
+	}
+
+	return &c, nil
+}
+
+// ValidatePreset checks the parameters of the given preset, their defaults and every reference to them,
+// including the references within the animations and transitions of the given data store the preset uses
+func ValidatePreset(ds *cntl.DataStore, p *cntl.DMXPreset) error {
+	names := make(map[string]bool)
+	for _, param := range p.Params {
+		if param.Name == "" {
+			return errors.New("preset parameters need to have a name")
+		}
+
+		if names[param.Name] {
+			return fmt.Errorf("preset parameter %q is declared twice", param.Name)
+		}
+		names[param.Name] = true
+
+		switch param.Type {
+		case cntl.PresetParamColor, cntl.PresetParamDimmer, cntl.PresetParamGroup:
+		default:
+			return fmt.Errorf("preset parameter %q has unknown type %q", param.Name, param.Type)
+		}
+
+		if param.Default != nil && param.Type == cntl.PresetParamDimmer {
+			if _, err := parseDimmerArg(*param.Default); err != nil {
+				return fmt.Errorf("invalid default of preset parameter %q: %v", param.Name, err)
+			}
+		}
+	}
+
+	for i, dp := range p.DeviceParams {
+		if err := validatePresetRefs(ds, p, dp); err != nil {
+			return fmt.Errorf("device params %d: %v", i, err)
+		}
+	}
+
+	return nil
+}
+
+// validatePresetRefs checks that all references of the given device params point to declared parameters of the right type
+func validatePresetRefs(ds *cntl.DataStore, p *cntl.DMXPreset, dp cntl.DMXDeviceParams) error {
+	if _, err := resolvePresetRef(p, dp.Group, cntl.PresetParamGroup, nil); err != nil {
+		return err
+	}
+
+	params := dp.Params
+	if dp.Animation != nil {
+		if a, ok := ds.DMXAnimations[*dp.Animation]; ok {
+			for _, f := range a.Frames {
+				params = append(params, f.Params)
+			}
+		}
+	}
+	if dp.Transition != nil {
+		if t, ok := ds.DMXTransitions[*dp.Transition]; ok {
+			for _, tp := range t.Params {
+				params = append(params, tp.From, tp.To)
+			}
+		}
+	}
+
+	for _, param := range params {
+		if _, err := resolvePresetRef(p, param.ColorVar, cntl.PresetParamColor, nil); err != nil {
+			return err
+		}
+
+		if param.DimmerVar == nil {
+			continue
+		}
+		if param.Dimmer != nil {
+			return ErrDeviceParamsDimmerVarMustBeExclusive
+		}
+		if _, err := resolvePresetRef(p, dimmerRef(param.DimmerVar), cntl.PresetParamDimmer, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
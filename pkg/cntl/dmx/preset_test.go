@@ -45,7 +45,7 @@ func TestRenderPreset(t *testing.T) {
 	}
 
 	for i, e := range exp {
-		c, err := RenderPreset(ds, e.p)
+		c, err := RenderPreset(ds, e.p, nil)
 		if e.err != nil && (err == nil || err.Error() != e.err.Error()) {
 			t.Fatalf("Expected to get error %v, got %v at index %d", e.err, err, i)
 		}
@@ -67,3 +67,174 @@ func TestRenderPreset(t *testing.T) {
 		}
 	}
 }
+
+func TestRenderPreset_Args(t *testing.T) {
+	ds := fixtures.DataStore()
+	p := &cntl.DMXPreset{
+		ID: "parametrised",
+		Params: []cntl.DMXPresetParam{
+			{Name: "color", Type: cntl.PresetParamColor, Default: fixtures.StrPtr("Red255")},
+			{Name: "level", Type: cntl.PresetParamDimmer, Default: fixtures.StrPtr("127")},
+			{Name: "group", Type: cntl.PresetParamGroup},
+		},
+		DeviceParams: []cntl.DMXDeviceParams{
+			{
+				Group: fixtures.StrPtr("$group"),
+				Params: []cntl.DMXParams{
+					{ColorVar: fixtures.StrPtr("$color"), DimmerVar: fixtures.StrPtr("$level")},
+				},
+			},
+		},
+	}
+
+	exp := []struct {
+		args cntl.DMXPresetArgs
+		c    cntl.DMXCommands
+		err  bool
+	}{
+		{
+			args: cntl.DMXPresetArgs{"group": "cb58bc10-0b16-11e7-b45a-7bee591b0adb"},
+			c: cntl.DMXCommands{
+				{Universe: 1, Channel: 222, Value: *fixtures.Value255},
+				{Universe: 1, Channel: 223, Value: *fixtures.Value127},
+			},
+		},
+		{
+			args: cntl.DMXPresetArgs{"group": "cb58bc10-0b16-11e7-b45a-7bee591b0adb", "color": "Blue255", "level": "31"},
+			c: cntl.DMXCommands{
+				{Universe: 1, Channel: 224, Value: *fixtures.Value255},
+				{Universe: 1, Channel: 223, Value: *fixtures.Value31},
+			},
+		},
+		{args: cntl.DMXPresetArgs{}, err: true},
+		{args: cntl.DMXPresetArgs{"group": "cb58bc10-0b16-11e7-b45a-7bee591b0adb", "unknown": "1"}, err: true},
+		{args: cntl.DMXPresetArgs{"group": "cb58bc10-0b16-11e7-b45a-7bee591b0adb", "level": "256"}, err: true},
+	}
+
+	for i, e := range exp {
+		c, err := RenderPreset(ds, p, e.args)
+		if e.err {
+			if err == nil {
+				t.Errorf("Expected to get an error at index %d", i)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("Unexpected error at index %d: %v", i, err)
+		}
+
+		if len(c) != 1 || !c[0].Equals(e.c) {
+			t.Errorf("Expected to get %+v, got %+v at index %d", e.c, c, i)
+		}
+	}
+}
+
+func TestRenderPreset_ArgsInAnimation(t *testing.T) {
+	ds := fixtures.DataStore()
+	ds.DMXAnimations["dimmed"] = &cntl.DMXAnimation{
+		ID: "dimmed",
+		Frames: []cntl.DMXAnimationFrame{
+			{At: 0, Params: cntl.DMXParams{DimmerVar: fixtures.StrPtr("level")}},
+			{At: 1, Params: cntl.DMXParams{ColorVar: fixtures.StrPtr("$color")}},
+		},
+	}
+
+	p := &cntl.DMXPreset{
+		ID: "parametrised",
+		Params: []cntl.DMXPresetParam{
+			{Name: "color", Type: cntl.PresetParamColor, Default: fixtures.StrPtr("Red255")},
+			{Name: "level", Type: cntl.PresetParamDimmer, Default: fixtures.StrPtr("127")},
+		},
+		DeviceParams: []cntl.DMXDeviceParams{
+			{Device: fixtures.StrPtr("35cae00a-0b17-11e7-8bca-bbf30c56f20e"), Animation: fixtures.StrPtr("dimmed")},
+		},
+	}
+
+	c, err := RenderPreset(ds, p, cntl.DMXPresetArgs{"level": "31"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	exp := []cntl.DMXCommands{
+		{{Universe: 1, Channel: 223, Value: *fixtures.Value31}},
+		{{Universe: 1, Channel: 222, Value: *fixtures.Value255}},
+	}
+
+	if len(c) != len(exp) {
+		t.Fatalf("Expected to get %d frames, got %d", len(exp), len(c))
+	}
+	for i := range exp {
+		if !c[i].Equals(exp[i]) {
+			t.Errorf("Expected to get %+v, got %+v at index %d", exp[i], c[i], i)
+		}
+	}
+
+	if ds.DMXAnimations["dimmed"].Frames[0].Params.DimmerVar == nil {
+		t.Errorf("Expected the animation to be left untouched")
+	}
+}
+
+func TestValidatePreset(t *testing.T) {
+	ds := fixtures.DataStore()
+	ds.DMXTransitions["fade"] = &cntl.DMXTransition{
+		ID:     "fade",
+		Params: []cntl.DMXTransitionParams{{From: cntl.DMXParams{ColorVar: fixtures.StrPtr("$level")}}},
+	}
+
+	params := []cntl.DMXPresetParam{
+		{Name: "color", Type: cntl.PresetParamColor},
+		{Name: "level", Type: cntl.PresetParamDimmer, Default: fixtures.StrPtr("127")},
+		{Name: "group", Type: cntl.PresetParamGroup},
+	}
+
+	exp := []struct {
+		params []cntl.DMXPresetParam
+		dp     cntl.DMXDeviceParams
+		err    bool
+	}{
+		{
+			params: params,
+			dp:     cntl.DMXDeviceParams{Group: fixtures.StrPtr("$group"), Params: []cntl.DMXParams{{ColorVar: fixtures.StrPtr("$color"), DimmerVar: fixtures.StrPtr("level")}}},
+		},
+		{
+			params: []cntl.DMXPresetParam{{Name: "level", Type: cntl.PresetParamDimmer, Default: fixtures.StrPtr("abc")}},
+			err:    true,
+		},
+		{
+			params: []cntl.DMXPresetParam{{Name: "level", Type: cntl.PresetParamDimmer}, {Name: "level", Type: cntl.PresetParamDimmer}},
+			err:    true,
+		},
+		{
+			params: []cntl.DMXPresetParam{{Name: "level", Type: "fog"}},
+			err:    true,
+		},
+		{
+			params: params,
+			dp:     cntl.DMXDeviceParams{Group: fixtures.StrPtr("$groups")},
+			err:    true,
+		},
+		{
+			params: params,
+			dp:     cntl.DMXDeviceParams{Params: []cntl.DMXParams{{DimmerVar: fixtures.StrPtr("color")}}},
+			err:    true,
+		},
+		{
+			params: params,
+			dp:     cntl.DMXDeviceParams{Group: fixtures.StrPtr("$group"), Transition: fixtures.StrPtr("fade")},
+			err:    true,
+		},
+	}
+
+	for i, e := range exp {
+		p := &cntl.DMXPreset{ID: "preset", Params: e.params, DeviceParams: []cntl.DMXDeviceParams{e.dp}}
+
+		err := ValidatePreset(ds, p)
+		if e.err && err == nil {
+			t.Errorf("Expected to get an error at index %d", i)
+		}
+		if !e.err && err != nil {
+			t.Errorf("Unexpected error at index %d: %v", i, err)
+		}
+	}
+}
@@ -15,6 +15,10 @@ func StreamlineScenes(ds *cntl.DataStore, s *cntl.Song) (map[uint64][]*cntl.DMXS
 			return map[uint64][]*cntl.DMXScene{}, fmt.Errorf("cannot find DMXScene %q", sp.ID)
 		}
 
+		if err := validateNoteValue(sc); err != nil {
+			return map[uint64][]*cntl.DMXScene{}, err
+		}
+
 		l := CalcSceneLength(sc)
 		at := uint64(sp.At)
 
@@ -31,6 +35,14 @@ func StreamlineScenes(ds *cntl.DataStore, s *cntl.Song) (map[uint64][]*cntl.DMXS
 	return scs, nil
 }
 
+func validateNoteValue(sc *cntl.DMXScene) error {
+	if !cntl.ValidNoteValue(sc.NoteValue) {
+		return fmt.Errorf("DMXScene %q has note value %d, which does not divide the %d render frames of a bar", sc.ID, sc.NoteValue, cntl.RenderFrames)
+	}
+
+	return nil
+}
+
 // CalcSceneLength calculates the length of a given scene in render frames
 func CalcSceneLength(sc *cntl.DMXScene) uint64 {
 	return uint64(sc.NoteCount * uint16(cntl.RenderFrames/sc.NoteValue))
@@ -40,14 +52,40 @@ func CalcSceneLength(sc *cntl.DMXScene) uint64 {
 // The first array dimension contains the render frames, the second dimension contains all
 // dmx commands for a render frame.
 func RenderScene(ds *cntl.DataStore, sc *cntl.DMXScene) ([]cntl.DMXCommands, error) {
+	return renderScene(ds, sc, []string{})
+}
+
+// renderScene renders the given scene, path contains the IDs of all scenes the given one is nested in
+func renderScene(ds *cntl.DataStore, sc *cntl.DMXScene, path []string) ([]cntl.DMXCommands, error) {
+	for _, id := range path {
+		if id == sc.ID {
+			return []cntl.DMXCommands{}, fmt.Errorf("DMXScene %q is nested within itself via %v", sc.ID, path)
+		}
+	}
+	path = append(append([]string{}, path...), sc.ID)
+
+	if err := validateNoteValue(sc); err != nil {
+		return []cntl.DMXCommands{}, err
+	}
+
 	sceneLength := uint16(CalcSceneLength(sc))
 	cmds := make([]cntl.DMXCommands, sceneLength)
 
 	for i, ss := range sc.SubScenes {
 		var scs []cntl.DMXCommands
 
-		if len(ss.DeviceParams) > 0 && ss.Preset != nil {
-			return []cntl.DMXCommands{}, fmt.Errorf("SubScene %d of scene %q cannot have both params and a preset", i, sc.ID)
+		contents := 0
+		if len(ss.DeviceParams) > 0 {
+			contents++
+		}
+		if ss.Preset != nil {
+			contents++
+		}
+		if ss.Scene != nil {
+			contents++
+		}
+		if contents > 1 {
+			return []cntl.DMXCommands{}, fmt.Errorf("SubScene %d of scene %q can only have one of params, a preset or a scene", i, sc.ID)
 		}
 
 		if ss.Preset != nil {
@@ -56,7 +94,7 @@ func RenderScene(ds *cntl.DataStore, sc *cntl.DMXScene) ([]cntl.DMXCommands, err
 				return []cntl.DMXCommands{}, fmt.Errorf("cannot find DMXPreset %q", *ss.Preset)
 			}
 
-			pcs, err := RenderPreset(ds, p)
+			pcs, err := RenderPreset(ds, p, ss.PresetArgs)
 			if err != nil {
 				return []cntl.DMXCommands{}, err
 			}
@@ -64,6 +102,15 @@ func RenderScene(ds *cntl.DataStore, sc *cntl.DMXScene) ([]cntl.DMXCommands, err
 			scs = MergeWithFrameChange(scs, pcs, sc.NoteValue)
 		}
 
+		if ss.Scene != nil {
+			ncs, err := renderNestedScene(ds, ss.Scene, path)
+			if err != nil {
+				return []cntl.DMXCommands{}, fmt.Errorf("failed to render scene %q: %v", sc.ID, err)
+			}
+
+			scs = Merge(scs, ncs)
+		}
+
 		for _, dp := range ss.DeviceParams {
 			dcs, err := RenderDeviceParams(ds, &dp)
 
@@ -82,3 +129,25 @@ func RenderScene(ds *cntl.DataStore, sc *cntl.DMXScene) ([]cntl.DMXCommands, err
 
 	return cmds, nil
 }
+
+// renderNestedScene renders the scene referenced by given DMXSceneRef, applying its note value and offset
+func renderNestedScene(ds *cntl.DataStore, ref *cntl.DMXSceneRef, path []string) ([]cntl.DMXCommands, error) {
+	sc, ok := ds.DMXScenes[ref.ID]
+	if !ok {
+		return []cntl.DMXCommands{}, fmt.Errorf("cannot find DMXScene %q", ref.ID)
+	}
+
+	if ref.NoteValue != 0 && ref.NoteValue != sc.NoteValue {
+		scaled := *sc
+		scaled.NoteValue = ref.NoteValue
+		sc = &scaled
+	}
+
+	ncs, err := renderScene(ds, sc, path)
+	if err != nil {
+		return []cntl.DMXCommands{}, err
+	}
+
+	offset := int(ref.Offset) * int(cntl.RenderFrames/sc.NoteValue)
+	return MergeAtOffset([]cntl.DMXCommands{}, ncs, offset), nil
+}
@@ -19,17 +19,17 @@ func TestStreamlineScenes(t *testing.T) {
 			s: ds.Songs["3c1065c8-0b14-11e7-96eb-5b134621c411"],
 			m: map[uint64][]*cntl.DMXScene{
 				0:    {ds.DMXScenes["492cef2e-0b14-11e7-be89-c3fa25f9cabb"]},
-				64:   {ds.DMXScenes["492cef2e-0b14-11e7-be89-c3fa25f9cabb"]},
-				128:  {ds.DMXScenes["492cef2e-0b14-11e7-be89-c3fa25f9cabb"]},
 				192:  {ds.DMXScenes["492cef2e-0b14-11e7-be89-c3fa25f9cabb"]},
-				512:  {ds.DMXScenes["a44f8dee-0b14-11e7-b5b9-bf1015384192"]},
-				544:  {ds.DMXScenes["a44f8dee-0b14-11e7-b5b9-bf1015384192"]},
-				576:  {ds.DMXScenes["a44f8dee-0b14-11e7-b5b9-bf1015384192"]},
-				608:  {ds.DMXScenes["a44f8dee-0b14-11e7-b5b9-bf1015384192"]},
-				1408: {ds.DMXScenes["99b86a5e-0e7a-11e7-a01a-5b5fbdeba3d6"]},
-				1536: {ds.DMXScenes["99b86a5e-0e7a-11e7-a01a-5b5fbdeba3d6"]},
-				1664: {ds.DMXScenes["99b86a5e-0e7a-11e7-a01a-5b5fbdeba3d6"]},
-				1920: {ds.DMXScenes["b82f4750-0e7a-11e7-9522-0f9d6d69958a"]},
+				384:  {ds.DMXScenes["492cef2e-0b14-11e7-be89-c3fa25f9cabb"]},
+				576:  {ds.DMXScenes["492cef2e-0b14-11e7-be89-c3fa25f9cabb"]},
+				1536: {ds.DMXScenes["a44f8dee-0b14-11e7-b5b9-bf1015384192"]},
+				1632: {ds.DMXScenes["a44f8dee-0b14-11e7-b5b9-bf1015384192"]},
+				1728: {ds.DMXScenes["a44f8dee-0b14-11e7-b5b9-bf1015384192"]},
+				1824: {ds.DMXScenes["a44f8dee-0b14-11e7-b5b9-bf1015384192"]},
+				4224: {ds.DMXScenes["99b86a5e-0e7a-11e7-a01a-5b5fbdeba3d6"]},
+				4608: {ds.DMXScenes["99b86a5e-0e7a-11e7-a01a-5b5fbdeba3d6"]},
+				4992: {ds.DMXScenes["99b86a5e-0e7a-11e7-a01a-5b5fbdeba3d6"]},
+				5760: {ds.DMXScenes["b82f4750-0e7a-11e7-9522-0f9d6d69958a"]},
 			},
 		},
 	}
@@ -64,11 +64,13 @@ func TestCalcSceneLength(t *testing.T) {
 		sc     *cntl.DMXScene
 		length uint64
 	}{
-		{&cntl.DMXScene{NoteCount: 3, NoteValue: 4}, 48},
-		{&cntl.DMXScene{NoteCount: 12, NoteValue: 8}, 96},
-		{&cntl.DMXScene{NoteCount: 11, NoteValue: 4}, 176},
-		{&cntl.DMXScene{NoteCount: 4, NoteValue: 4}, 64},
-		{&cntl.DMXScene{NoteCount: 9, NoteValue: 8}, 72},
+		{&cntl.DMXScene{NoteCount: 3, NoteValue: 4}, 144},
+		{&cntl.DMXScene{NoteCount: 12, NoteValue: 8}, 288},
+		{&cntl.DMXScene{NoteCount: 11, NoteValue: 4}, 528},
+		{&cntl.DMXScene{NoteCount: 4, NoteValue: 4}, 192},
+		{&cntl.DMXScene{NoteCount: 9, NoteValue: 8}, 216},
+		{&cntl.DMXScene{NoteCount: 12, NoteValue: 12}, 192},
+		{&cntl.DMXScene{NoteCount: 3, NoteValue: 6}, 96},
 	}
 
 	for i, e := range exp {
@@ -87,31 +89,29 @@ func TestRenderScene(t *testing.T) {
 	}{
 		{
 			s: ds.DMXScenes["492cef2e-0b14-11e7-be89-c3fa25f9cabb"],
-			c: repeat(4, []cntl.DMXCommands{
+			c: repeat(4, append([]cntl.DMXCommands{
 				{{Universe: 1, Channel: 222, Value: *fixtures.Value255}},
-				{}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {},
-			}),
+			}, make([]cntl.DMXCommands, 47)...)),
 			err: nil,
 		},
 		{
 			s: ds.DMXScenes["a44f8dee-0b14-11e7-b5b9-bf1015384192"],
-			c: repeat(2, []cntl.DMXCommands{
+			c: repeat(2, append([]cntl.DMXCommands{
 				{{Universe: 1, Channel: 224, Value: *fixtures.Value255}},
-				{}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {},
-			}),
+			}, make([]cntl.DMXCommands, 47)...)),
 			err: nil,
 		},
 		{
 			s: ds.DMXScenes["99b86a5e-0e7a-11e7-a01a-5b5fbdeba3d6"],
 			c: repeat(4, []cntl.DMXCommands{
 				{{Universe: 1, Channel: 228, Value: *fixtures.Value31}},
-				{}, {}, {}, {}, {}, {}, {},
+				{}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {},
 				{{Universe: 1, Channel: 228, Value: *fixtures.Value63}},
-				{}, {}, {}, {}, {}, {}, {},
+				{}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {},
 				{{Universe: 1, Channel: 228, Value: *fixtures.Value127}},
-				{}, {}, {}, {}, {}, {}, {},
+				{}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {},
 				{{Universe: 1, Channel: 228, Value: *fixtures.Value255}},
-				{}, {}, {}, {}, {}, {}, {},
+				{}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {}, {},
 			}),
 			err: nil,
 		},
@@ -143,3 +143,109 @@ func TestRenderScene(t *testing.T) {
 		}
 	}
 }
+
+func TestRenderScene_Nested(t *testing.T) {
+	ds := fixtures.DataStore()
+	red := cntl.DMXCommand{Universe: 1, Channel: 222, Value: *fixtures.Value255}
+
+	exp := []struct {
+		ref    cntl.DMXSceneRef
+		frames []int
+	}{
+		{cntl.DMXSceneRef{ID: "492cef2e-0b14-11e7-be89-c3fa25f9cabb"}, []int{0, 48, 96, 144}},
+		{cntl.DMXSceneRef{ID: "492cef2e-0b14-11e7-be89-c3fa25f9cabb", NoteValue: 8}, []int{0, 24, 48, 72}},
+		{cntl.DMXSceneRef{ID: "492cef2e-0b14-11e7-be89-c3fa25f9cabb", NoteValue: 8, Offset: 1}, []int{24, 48, 72, 96}},
+	}
+
+	for i, e := range exp {
+		ref := e.ref
+		sc := &cntl.DMXScene{
+			ID:        "nested",
+			NoteCount: 4,
+			NoteValue: 4,
+			SubScenes: []cntl.DMXSubScene{{At: []uint64{0}, Scene: &ref}},
+		}
+
+		c, err := RenderScene(ds, sc)
+		if err != nil {
+			t.Fatalf("Unexpected error at index %d: %v", i, err)
+		}
+
+		for frame := range c {
+			expected := false
+			for _, f := range e.frames {
+				if f == frame {
+					expected = true
+				}
+			}
+
+			if c[frame].Contains(red) != expected {
+				t.Errorf("Expected frame %d to contain the command: %v, got %+v at index %d", frame, expected, c[frame], i)
+			}
+		}
+	}
+}
+
+func TestRenderScene_NestedCycle(t *testing.T) {
+	ds := cntl.NewStore()
+	ds.DMXScenes["a"] = &cntl.DMXScene{
+		ID: "a", NoteCount: 4, NoteValue: 4,
+		SubScenes: []cntl.DMXSubScene{{At: []uint64{0}, Scene: &cntl.DMXSceneRef{ID: "b"}}},
+	}
+	ds.DMXScenes["b"] = &cntl.DMXScene{
+		ID: "b", NoteCount: 4, NoteValue: 4,
+		SubScenes: []cntl.DMXSubScene{{At: []uint64{0}, Scene: &cntl.DMXSceneRef{ID: "a"}}},
+	}
+
+	if _, err := RenderScene(ds, ds.DMXScenes["a"]); err == nil {
+		t.Error("Expected to get an error for nested scene cycle, got nil")
+	}
+}
+
+func TestRenderScene_Triplets(t *testing.T) {
+	ds := fixtures.DataStore()
+	red := cntl.DMXCommand{Universe: 1, Channel: 222, Value: *fixtures.Value255}
+
+	exp := []struct {
+		noteValue uint8
+		frames    []int
+		err       bool
+	}{
+		{noteValue: 12, frames: []int{0, 16, 32}},
+		{noteValue: 6, frames: []int{0, 32, 64}},
+		{noteValue: 10, err: true},
+	}
+
+	for i, e := range exp {
+		sc := &cntl.DMXScene{
+			ID:        "triplets",
+			NoteCount: 3,
+			NoteValue: e.noteValue,
+			SubScenes: []cntl.DMXSubScene{{At: []uint64{0, 1, 2}, Preset: fixtures.StrPtr("0de258e0-0e7b-11e7-afd4-ebf6036983dc")}},
+		}
+
+		c, err := RenderScene(ds, sc)
+		if e.err {
+			if err == nil {
+				t.Errorf("Expected to get an error for note value %d at index %d", e.noteValue, i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error at index %d: %v", i, err)
+		}
+
+		for frame := range c {
+			expected := false
+			for _, f := range e.frames {
+				if f == frame {
+					expected = true
+				}
+			}
+
+			if c[frame].Contains(red) != expected {
+				t.Errorf("Expected frame %d to contain the command: %v, got %+v at index %d", frame, expected, c[frame], i)
+			}
+		}
+	}
+}
@@ -29,8 +29,12 @@ func RenderTransition(ds *cntl.DataStore, dd []*cntl.DMXDevice, t *cntl.DMXTrans
 
 // RenderTransitionParams renders the params of given transition
 func RenderTransitionParams(ds *cntl.DataStore, dd []*cntl.DMXDevice, t *cntl.DMXTransition, p cntl.DMXTransitionParams) ([]cntl.DMXCommands, error) {
+	if hasPalette(p.From) || hasPalette(p.To) {
+		return renderTransitionPaletteParams(ds, dd, t, p)
+	}
+
 	result := make([]cntl.DMXCommands, t.Length)
-	ease, err := getEasingFunc(t.Ease)
+	ease, err := GetEasingFunc(t.Ease)
 	if err != nil {
 		return []cntl.DMXCommands{}, err
 	}
@@ -160,7 +164,34 @@ func RenderTransitionParams(ds *cntl.DataStore, dd []*cntl.DMXDevice, t *cntl.DM
 	return result, nil
 }
 
-func calcTransitionSteps(from, to uint8, steps uint16, easingFunc easingFunc) ([]uint8, error) {
+// renderTransitionPaletteParams renders the transition for each device on its own, as palettes resolve per device
+func renderTransitionPaletteParams(ds *cntl.DataStore, dd []*cntl.DMXDevice, t *cntl.DMXTransition, p cntl.DMXTransitionParams) ([]cntl.DMXCommands, error) {
+	result := make([]cntl.DMXCommands, t.Length)
+
+	for _, d := range dd {
+		dp := p
+		if err := resolvePalette(ds, &dp.From, d); err != nil {
+			return []cntl.DMXCommands{}, err
+		}
+		dp.From.Palette = nil
+
+		if err := resolvePalette(ds, &dp.To, d); err != nil {
+			return []cntl.DMXCommands{}, err
+		}
+		dp.To.Palette = nil
+
+		cmds, err := RenderTransitionParams(ds, []*cntl.DMXDevice{d}, t, dp)
+		if err != nil {
+			return []cntl.DMXCommands{}, err
+		}
+
+		result = Merge(result, cmds)
+	}
+
+	return result, nil
+}
+
+func calcTransitionSteps(from, to uint8, steps uint16, easingFunc EasingFunc) ([]uint8, error) {
 	result := make([]uint8, steps)
 	diff := float64(to) - float64(from)
 	floatFrom := float64(from)
@@ -0,0 +1,32 @@
+package live
+
+import (
+	"errors"
+	"time"
+)
+
+// Live control errors
+var (
+	ErrNotRunning      = errors.New("the midi input is not running")
+	ErrLearnTimeout    = errors.New("no midi message received to learn")
+	ErrOutputDisabled  = errors.New("the ArtNet controller is disabled")
+	ErrPlaybackMissing = errors.New("there is no playback to control")
+)
+
+const (
+	// ProcessName defines the name of the MIDI input process
+	ProcessName      = "midiInput"
+	configStorageKey = "midi_input_process"
+
+	// LearnTimeout defines how long MIDI learn waits for a message by default
+	LearnTimeout = 10 * time.Second
+
+	// pressedValue defines the value from which on a control change is pressed, like a sustain pedal
+	pressedValue = 64
+)
+
+var defaultConfig = `
+{
+  "inputDeviceId": -1
+}
+`
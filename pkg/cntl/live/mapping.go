@@ -0,0 +1,99 @@
+package live
+
+import (
+	"fmt"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+)
+
+// ValidateMapping checks that the given mapping can be triggered and has everything its action needs
+func ValidateMapping(m *cntl.MIDIMapping) error {
+	switch m.Message {
+	case cntl.MIDIMessageNote, cntl.MIDIMessageControlChange, cntl.MIDIMessageProgramChange:
+	default:
+		return fmt.Errorf("MIDI message type %q is unknown", m.Message)
+	}
+
+	if m.Channel > 16 {
+		return fmt.Errorf("MIDI channel must be between 1 and 16, or 0 for all channels, got %d", m.Channel)
+	}
+
+	if m.Data1 > 127 {
+		return fmt.Errorf("MIDI data byte must be at most 127, got %d", m.Data1)
+	}
+
+	switch m.Action {
+	case cntl.MIDIActionScene, cntl.MIDIActionPreset:
+		if m.Target == "" {
+			return fmt.Errorf("action %q needs a target", m.Action)
+		}
+
+	case cntl.MIDIActionFlash:
+		if m.Target == "" {
+			return fmt.Errorf("action %q needs a target", m.Action)
+		}
+		if m.Message == cntl.MIDIMessageProgramChange {
+			return fmt.Errorf("action %q cannot be released by a program change", m.Action)
+		}
+
+	case cntl.MIDIActionMaster, cntl.MIDIActionNextSong, cntl.MIDIActionBlackout:
+
+	default:
+		return fmt.Errorf("action %q is unknown", m.Action)
+	}
+
+	return nil
+}
+
+// match returns whether the given MIDI command triggers the given mapping, the value of the command and whether it
+// presses or releases the mapping. Control changes from pressedValue on press, program changes always press.
+func match(m *cntl.MIDIMapping, cmd cntl.MIDICommand) (value uint8, pressed bool, ok bool) {
+	// system messages have no channel
+	if cmd.Status >= 0xF0 || cmd.Data1 != m.Data1 {
+		return 0, false, false
+	}
+
+	if m.Channel != 0 && cmd.Status&0x0F+1 != m.Channel {
+		return 0, false, false
+	}
+
+	switch {
+	case cmd.Status&0xF0 == 0x90 && m.Message == cntl.MIDIMessageNote:
+		return cmd.Data2, cmd.Data2 > 0, true
+
+	case cmd.Status&0xF0 == 0x80 && m.Message == cntl.MIDIMessageNote:
+		return 0, false, true
+
+	case cmd.Status&0xF0 == 0xB0 && m.Message == cntl.MIDIMessageControlChange:
+		return cmd.Data2, cmd.Data2 >= pressedValue, true
+
+	case cmd.Status&0xF0 == 0xC0 && m.Message == cntl.MIDIMessageProgramChange:
+		return 127, true, true
+	}
+
+	return 0, false, false
+}
+
+// learn returns a mapping triggered by the given MIDI command, note offs cannot be learned
+func learn(cmd cntl.MIDICommand) (cntl.MIDIMapping, bool) {
+	m := cntl.MIDIMapping{Channel: cmd.Status&0x0F + 1, Data1: cmd.Data1}
+
+	switch {
+	case cmd.Status >= 0xF0:
+		return m, false
+
+	case cmd.Status&0xF0 == 0x90 && cmd.Data2 > 0:
+		m.Message = cntl.MIDIMessageNote
+
+	case cmd.Status&0xF0 == 0xB0:
+		m.Message = cntl.MIDIMessageControlChange
+
+	case cmd.Status&0xF0 == 0xC0:
+		m.Message = cntl.MIDIMessageProgramChange
+
+	default:
+		return m, false
+	}
+
+	return m, true
+}
@@ -0,0 +1,65 @@
+package live
+
+import (
+	"testing"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+)
+
+func TestMatch(t *testing.T) {
+	note := &cntl.MIDIMapping{Message: cntl.MIDIMessageNote, Channel: 10, Data1: 36}
+	cc := &cntl.MIDIMapping{Message: cntl.MIDIMessageControlChange, Data1: 7}
+	pc := &cntl.MIDIMapping{Message: cntl.MIDIMessageProgramChange, Channel: 1, Data1: 3}
+
+	exp := []struct {
+		mapping *cntl.MIDIMapping
+		cmd     cntl.MIDICommand
+		value   uint8
+		pressed bool
+		ok      bool
+	}{
+		{mapping: note, cmd: cntl.MIDICommand{Status: 0x99, Data1: 36, Data2: 100}, value: 100, pressed: true, ok: true},
+		{mapping: note, cmd: cntl.MIDICommand{Status: 0x99, Data1: 36, Data2: 0}, value: 0, pressed: false, ok: true},
+		{mapping: note, cmd: cntl.MIDICommand{Status: 0x89, Data1: 36, Data2: 64}, value: 0, pressed: false, ok: true},
+		{mapping: note, cmd: cntl.MIDICommand{Status: 0x90, Data1: 36, Data2: 100}},
+		{mapping: note, cmd: cntl.MIDICommand{Status: 0x99, Data1: 37, Data2: 100}},
+		{mapping: note, cmd: cntl.MIDICommand{Status: 0xB9, Data1: 36, Data2: 100}},
+		{mapping: cc, cmd: cntl.MIDICommand{Status: 0xB0, Data1: 7, Data2: 127}, value: 127, pressed: true, ok: true},
+		{mapping: cc, cmd: cntl.MIDICommand{Status: 0xBF, Data1: 7, Data2: 63}, value: 63, pressed: false, ok: true},
+		{mapping: cc, cmd: cntl.MIDICommand{Status: 0xF8, Data1: 7}},
+		{mapping: pc, cmd: cntl.MIDICommand{Status: 0xC0, Data1: 3}, value: 127, pressed: true, ok: true},
+		{mapping: pc, cmd: cntl.MIDICommand{Status: 0xC1, Data1: 3}},
+	}
+
+	for i, e := range exp {
+		value, pressed, ok := match(e.mapping, e.cmd)
+		if value != e.value || pressed != e.pressed || ok != e.ok {
+			t.Errorf("Expected to get %d, %v, %v, got %d, %v, %v at index %d", e.value, e.pressed, e.ok, value, pressed, ok, i)
+		}
+	}
+}
+
+func TestLearn(t *testing.T) {
+	exp := []struct {
+		cmd     cntl.MIDICommand
+		mapping cntl.MIDIMapping
+		ok      bool
+	}{
+		{cmd: cntl.MIDICommand{Status: 0x99, Data1: 36, Data2: 100}, mapping: cntl.MIDIMapping{Message: cntl.MIDIMessageNote, Channel: 10, Data1: 36}, ok: true},
+		{cmd: cntl.MIDICommand{Status: 0xB0, Data1: 7, Data2: 0}, mapping: cntl.MIDIMapping{Message: cntl.MIDIMessageControlChange, Channel: 1, Data1: 7}, ok: true},
+		{cmd: cntl.MIDICommand{Status: 0xC2, Data1: 5}, mapping: cntl.MIDIMapping{Message: cntl.MIDIMessageProgramChange, Channel: 3, Data1: 5}, ok: true},
+		{cmd: cntl.MIDICommand{Status: 0x99, Data1: 36, Data2: 0}},
+		{cmd: cntl.MIDICommand{Status: 0x80, Data1: 36}},
+		{cmd: cntl.MIDICommand{Status: 0xF8}},
+	}
+
+	for i, e := range exp {
+		mapping, ok := learn(e.cmd)
+		if ok != e.ok {
+			t.Errorf("Expected to learn %v, got %v at index %d", e.ok, ok, i)
+		}
+		if ok && (mapping.Message != e.mapping.Message || mapping.Channel != e.mapping.Channel || mapping.Data1 != e.mapping.Data1) {
+			t.Errorf("Expected to learn %+v, got %+v at index %d", e.mapping, mapping, i)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package live
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"github.com/StageAutoControl/controller/pkg/artnet"
+	"github.com/StageAutoControl/controller/pkg/cntl"
+)
+
+// channel identifies a DMX channel in a universe
+type channel struct {
+	universe, channel uint16
+}
+
+// Output wraps an ArtNet controller and applies the master fader, the blackout and flashes to the intensity channels,
+// which are the dimmer channels of all devices or their LED channels if they have no dimmer.
+// Everything written to it is kept, so the output can be restored when the master fader or blackout change.
+type Output struct {
+	mu         sync.Mutex
+	controller artnet.Controller
+	raw        *artnet.State
+	intensity  map[channel]bool
+
+	master   float64
+	blackout bool
+
+	// flashes holds the channels of every flash by mapping ID, flashed counts the flashes of a channel
+	flashes map[string][]channel
+	flashed map[channel]int
+}
+
+// NewOutput returns a new Output writing to the given controller, with the master fader at full
+func NewOutput(controller artnet.Controller) *Output {
+	return &Output{
+		controller: controller,
+		raw:        artnet.NewState(),
+		intensity:  make(map[channel]bool),
+		master:     1,
+		flashes:    make(map[string][]channel),
+		flashed:    make(map[channel]int),
+	}
+}
+
+// Start the controller
+func (o *Output) Start(ctx context.Context) error {
+	return o.controller.Start(ctx)
+}
+
+// Stop the controller
+func (o *Output) Stop() {
+	o.controller.Stop()
+}
+
+// Write implements the playback.TransportWriter interface
+func (o *Output) Write(cmd cntl.Command) error {
+	values := make([]artnet.ChannelValue, len(cmd.DMXCommands))
+	for i, c := range cmd.DMXCommands {
+		values[i] = artnet.ChannelValue{Universe: uint16(c.Universe), Channel: uint16(c.Channel), Value: c.Value.Uint8()}
+	}
+
+	o.SetDMXChannelValues(values)
+	return nil
+}
+
+// SetDMXChannelValue sets a single channel
+func (o *Output) SetDMXChannelValue(value artnet.ChannelValue) {
+	o.SetDMXChannelValues([]artnet.ChannelValue{value})
+}
+
+// SetDMXChannelValues sets the given channels, adjusting the intensity channels
+func (o *Output) SetDMXChannelValues(values []artnet.ChannelValue) {
+	o.mu.Lock()
+	o.raw.SetChannelValues(values)
+
+	adjusted := make([]artnet.ChannelValue, len(values))
+	for i, v := range values {
+		adjusted[i] = v
+		adjusted[i].Value = o.value(channel{v.Universe, v.Channel}, v.Value)
+	}
+	o.mu.Unlock()
+
+	o.controller.SetDMXChannelValues(adjusted)
+}
+
+// SetMaster sets the master fader from 0 to 1
+func (o *Output) SetMaster(master float64) {
+	o.update(func() {
+		o.master = math.Max(0, math.Min(1, master))
+	})
+}
+
+// Master returns the position of the master fader
+func (o *Output) Master() float64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.master
+}
+
+// SetBlackout turns all intensity channels off, or restores them
+func (o *Output) SetBlackout(blackout bool) {
+	o.update(func() {
+		o.blackout = blackout
+	})
+}
+
+// Blackout returns whether the blackout is active
+func (o *Output) Blackout() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.blackout
+}
+
+// setDevices sets the intensity channels of all devices of the given data store
+func (o *Output) setDevices(ds *cntl.DataStore) {
+	devices := make([]*cntl.DMXDevice, 0, len(ds.DMXDevices))
+	for _, d := range ds.DMXDevices {
+		devices = append(devices, d)
+	}
+
+	o.update(func() {
+		o.intensity = make(map[channel]bool)
+		for _, c := range intensityChannels(ds, devices) {
+			o.intensity[c] = true
+		}
+		for c := range o.flashed {
+			o.intensity[c] = true
+		}
+	})
+}
+
+// flash sets the given channels to full while a flash with the given ID is on
+func (o *Output) flash(id string, channels []channel, on bool) {
+	o.update(func() {
+		for _, c := range o.flashes[id] {
+			if o.flashed[c]--; o.flashed[c] <= 0 {
+				delete(o.flashed, c)
+			}
+		}
+		delete(o.flashes, id)
+
+		if !on {
+			return
+		}
+
+		o.flashes[id] = channels
+		for _, c := range channels {
+			o.flashed[c]++
+			o.intensity[c] = true
+		}
+	})
+}
+
+// update applies the given change and writes all intensity channels again
+func (o *Output) update(change func()) {
+	o.mu.Lock()
+	change()
+
+	values := make([]artnet.ChannelValue, 0, len(o.intensity))
+	for c := range o.intensity {
+		raw := o.raw.GetUniverse(c.universe)[c.channel]
+		values = append(values, artnet.ChannelValue{Universe: c.universe, Channel: c.channel, Value: o.value(c, raw)})
+	}
+	o.mu.Unlock()
+
+	if len(values) > 0 {
+		o.controller.SetDMXChannelValues(values)
+	}
+}
+
+// value returns the value to send for the given channel, needs to be called with the lock held
+func (o *Output) value(c channel, raw uint8) uint8 {
+	if !o.intensity[c] {
+		return raw
+	}
+
+	if o.blackout {
+		return 0
+	}
+
+	if o.flashed[c] > 0 {
+		raw = 255
+	}
+
+	return uint8(math.Round(float64(raw) * o.master))
+}
+
+// intensityChannels returns the dimmer channels of the given devices, or their LED channels if they have no dimmer
+func intensityChannels(ds *cntl.DataStore, devices []*cntl.DMXDevice) []channel {
+	var channels []channel
+	for _, d := range devices {
+		dt, ok := ds.DMXDeviceTypes[d.TypeID]
+		if !ok {
+			continue
+		}
+
+		if dt.DimmerEnabled {
+			channels = append(channels, channel{uint16(d.Universe), uint16(d.StartChannel + dt.DimmerChannel)})
+			continue
+		}
+
+		for _, led := range dt.LEDs {
+			for _, c := range []cntl.DMXChannel{led.Red, led.Green, led.Blue, led.White} {
+				channels = append(channels, channel{uint16(d.Universe), uint16(d.StartChannel + c)})
+			}
+		}
+	}
+
+	return channels
+}
@@ -0,0 +1,112 @@
+package live
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/StageAutoControl/controller/pkg/artnet"
+	"github.com/StageAutoControl/controller/pkg/cntl"
+)
+
+// fakeController keeps the latest value sent for every channel
+type fakeController struct {
+	mu     sync.Mutex
+	values map[channel]uint8
+}
+
+func (c *fakeController) Write(cmd cntl.Command) error { return nil }
+
+func (c *fakeController) SetDMXChannelValue(value artnet.ChannelValue) {
+	c.SetDMXChannelValues([]artnet.ChannelValue{value})
+}
+
+func (c *fakeController) SetDMXChannelValues(values []artnet.ChannelValue) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, v := range values {
+		c.values[channel{v.Universe, v.Channel}] = v.Value
+	}
+}
+
+func (c *fakeController) Start(ctx context.Context) error { return nil }
+
+func (c *fakeController) Stop() {}
+
+func (c *fakeController) value(universe, ch uint16) uint8 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.values[channel{universe, ch}]
+}
+
+// outputDataStore returns a dimmer at channel 1 and an RGB LED bar at channels 10 to 12, which is in the group "bar"
+func outputDataStore() *cntl.DataStore {
+	ds := cntl.NewStore()
+	ds.DMXDeviceTypes["dimmer"] = &cntl.DMXDeviceType{ID: "dimmer", DimmerEnabled: true, DimmerChannel: 1, ModeEnabled: true, ModeChannel: 0}
+	ds.DMXDeviceTypes["rgb"] = &cntl.DMXDeviceType{ID: "rgb", LEDs: []cntl.LED{{Red: 0, Green: 1, Blue: 2, White: 2}}}
+	ds.DMXDevices["par"] = &cntl.DMXDevice{ID: "par", TypeID: "dimmer", StartChannel: 0}
+	ds.DMXDevices["bar"] = &cntl.DMXDevice{ID: "bar", TypeID: "rgb", StartChannel: 10}
+	ds.DMXDeviceGroups["bar"] = &cntl.DMXDeviceGroup{ID: "bar", Devices: []cntl.DMXDeviceSelector{{ID: "bar"}}}
+
+	return ds
+}
+
+func TestOutput(t *testing.T) {
+	c := &fakeController{values: make(map[channel]uint8)}
+	o := NewOutput(c)
+	o.setDevices(outputDataStore())
+
+	o.SetDMXChannelValues([]artnet.ChannelValue{
+		{Channel: 0, Value: 200},
+		{Channel: 1, Value: 200},
+		{Channel: 10, Value: 100},
+	})
+
+	bar := intensityChannels(outputDataStore(), []*cntl.DMXDevice{outputDataStore().DMXDevices["bar"]})
+
+	exp := []struct {
+		change func()
+		values map[uint16]uint8
+	}{
+		{
+			change: func() {},
+			values: map[uint16]uint8{0: 200, 1: 200, 10: 100, 11: 0},
+		},
+		{
+			change: func() { o.SetMaster(0.5) },
+			values: map[uint16]uint8{0: 200, 1: 100, 10: 50, 11: 0},
+		},
+		{
+			change: func() { o.flash("flash", bar, true) },
+			values: map[uint16]uint8{0: 200, 1: 100, 10: 128, 11: 128},
+		},
+		{
+			change: func() { o.SetBlackout(true) },
+			values: map[uint16]uint8{0: 200, 1: 0, 10: 0, 11: 0},
+		},
+		{
+			change: func() { o.SetDMXChannelValue(artnet.ChannelValue{Channel: 1, Value: 255}) },
+			values: map[uint16]uint8{0: 200, 1: 0, 10: 0, 11: 0},
+		},
+		{
+			change: func() { o.SetBlackout(false) },
+			values: map[uint16]uint8{0: 200, 1: 128, 10: 128, 11: 128},
+		},
+		{
+			change: func() { o.flash("flash", bar, false); o.SetMaster(2) },
+			values: map[uint16]uint8{0: 200, 1: 255, 10: 100, 11: 0},
+		},
+	}
+
+	for i, e := range exp {
+		e.change()
+
+		for ch, value := range e.values {
+			if v := c.value(0, ch); v != value {
+				t.Errorf("Expected channel %d to be %d, got %d at index %d", ch, value, v, i)
+			}
+		}
+	}
+}
@@ -0,0 +1,360 @@
+// Package live controls the rig by incoming MIDI messages while the server is up, e.g. from a control surface.
+package live
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/dmx"
+	"github.com/StageAutoControl/controller/pkg/cntl/playback"
+	"github.com/StageAutoControl/controller/pkg/cntl/transport"
+	"github.com/StageAutoControl/controller/pkg/disk"
+	"github.com/StageAutoControl/controller/pkg/internal/logging"
+)
+
+type storage interface {
+	Read(key string, value interface{}) error
+	Write(key string, value interface{}) error
+}
+
+type loader interface {
+	Load() (*cntl.DataStore, error)
+}
+
+// Playback is the playback the next song action is sent to
+type Playback interface {
+	Next() error
+}
+
+// Config stores which MIDI input device the mappings listen to
+type Config struct {
+	InputDeviceID int8 `json:"inputDeviceId"`
+}
+
+// Process listens to a MIDI input and triggers the actions of all MIDI mappings
+type Process struct {
+	logger   logging.Logger
+	loader   loader
+	storage  storage
+	output   *Output
+	playback Playback
+
+	mu       sync.Mutex
+	ctx      context.Context
+	cancel   context.CancelFunc
+	deviceID int8
+	ds       *cntl.DataStore
+	mappings []*cntl.MIDIMapping
+	reload   bool
+	learners []chan cntl.MIDIMapping
+}
+
+// NewProcess returns a new MIDI input process, the output and playback are optional
+func NewProcess(loader loader, storage storage, output *Output, playback Playback) *Process {
+	return &Process{
+		loader:   loader,
+		storage:  storage,
+		output:   output,
+		playback: playback,
+	}
+}
+
+// EnsureDefaultConfig ensures that the default configuration exists in given storage
+func EnsureDefaultConfig(storage storage) error {
+	config := &Config{}
+	if err := storage.Read(configStorageKey, config); err != nil {
+		if err != disk.ErrNotExists {
+			return fmt.Errorf("failed to find midi input config: %v", err)
+		}
+
+		if err := json.Unmarshal([]byte(defaultConfig), config); err != nil {
+			return fmt.Errorf("failed to decode the default config: %v", err)
+		}
+
+		if err := storage.Write(configStorageKey, config); err != nil {
+			return fmt.Errorf("failed to write the default config to storage: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// SetLogger sets the logger for the process
+func (p *Process) SetLogger(logger logging.Logger) {
+	p.logger = logger
+}
+
+// Start opens the configured MIDI input and listens to it until the process is stopped
+func (p *Process) Start(ctx context.Context) error {
+	config := &Config{}
+	if err := p.storage.Read(configStorageKey, config); err != nil {
+		return fmt.Errorf("failed to find midi input config: %v", err)
+	}
+
+	// the playback opens its MIDI inputs while a song is played, a device cannot be opened by both
+	if u, ok := p.playback.(playback.MIDIInputUser); ok {
+		for _, id := range u.MIDIInputs() {
+			if id == config.InputDeviceID {
+				return fmt.Errorf("cannot use midi input device %d, it is already used by the playback", id)
+			}
+		}
+	}
+
+	if err := p.load(); err != nil {
+		return err
+	}
+
+	in, err := transport.NewMIDIInput(p.logger, config.InputDeviceID)
+	if err != nil {
+		return fmt.Errorf("failed to open midi input: %v", err)
+	}
+
+	p.mu.Lock()
+	p.ctx, p.cancel = context.WithCancel(ctx)
+	p.deviceID = config.InputDeviceID
+	p.mu.Unlock()
+
+	go p.listen(p.ctx, in)
+	return nil
+}
+
+// Stop the process, i.e. stop listening to the MIDI input
+func (p *Process) Stop() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.cancel = nil
+
+	return nil
+}
+
+// MIDIInputs returns the MIDI input device the process listens to while it is running
+func (p *Process) MIDIInputs() []int8 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cancel == nil {
+		return nil
+	}
+
+	return []int8{p.deviceID}
+}
+
+// Blocking returns true if calling Start() is a blocking operation and the process is stopped after start returned
+func (p *Process) Blocking() bool {
+	return false
+}
+
+// Reload loads the mappings and the data they refer to again before the next MIDI message is handled
+func (p *Process) Reload() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.reload = true
+}
+
+// Learn returns the trigger of the next note on, control change or program change, which does not trigger any mapping.
+// It waits until the given context is done.
+func (p *Process) Learn(ctx context.Context) (cntl.MIDIMapping, error) {
+	l := make(chan cntl.MIDIMapping, 1)
+
+	p.mu.Lock()
+	if p.cancel == nil {
+		p.mu.Unlock()
+		return cntl.MIDIMapping{}, ErrNotRunning
+	}
+	p.learners = append(p.learners, l)
+	p.mu.Unlock()
+
+	select {
+	case m := <-l:
+		return m, nil
+
+	case <-ctx.Done():
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		for i, learner := range p.learners {
+			if learner == l {
+				p.learners = append(p.learners[:i], p.learners[i+1:]...)
+				break
+			}
+		}
+		return cntl.MIDIMapping{}, ErrLearnTimeout
+	}
+}
+
+func (p *Process) load() error {
+	ds, err := p.loader.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load data from disk: %v", err)
+	}
+
+	mappings := make([]*cntl.MIDIMapping, 0, len(ds.MIDIMappings))
+	for _, m := range ds.MIDIMappings {
+		if err := ValidateMapping(m); err != nil {
+			p.logger.Warnf("Ignoring MIDI mapping %q: %v", m.Name, err)
+			continue
+		}
+		mappings = append(mappings, m)
+	}
+	sort.Slice(mappings, func(i, j int) bool { return mappings[i].ID < mappings[j].ID })
+
+	if p.output != nil {
+		p.output.setDevices(ds)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.ds = ds
+	p.mappings = mappings
+	p.reload = false
+	return nil
+}
+
+func (p *Process) listen(ctx context.Context, in playback.MIDIInput) {
+	for cmd := range in.Listen(ctx) {
+		if err := p.handle(cmd); err != nil {
+			p.logger.Warnf("failed to handle MIDI message %#v: %v", cmd, err)
+		}
+	}
+}
+
+func (p *Process) handle(cmd cntl.MIDICommand) error {
+	p.mu.Lock()
+	if m, ok := learn(cmd); ok && len(p.learners) > 0 {
+		for _, l := range p.learners {
+			l <- m
+		}
+		p.learners = nil
+		p.mu.Unlock()
+		return nil
+	}
+	reload := p.reload
+	p.mu.Unlock()
+
+	if reload {
+		if err := p.load(); err != nil {
+			return err
+		}
+	}
+
+	p.mu.Lock()
+	ds, mappings := p.ds, p.mappings
+	p.mu.Unlock()
+
+	for _, m := range mappings {
+		value, pressed, ok := match(m, cmd)
+		if !ok {
+			continue
+		}
+
+		if err := p.trigger(ds, m, value, pressed); err != nil {
+			return fmt.Errorf("failed to trigger MIDI mapping %q: %v", m.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *Process) trigger(ds *cntl.DataStore, m *cntl.MIDIMapping, value uint8, pressed bool) error {
+	if m.Action == cntl.MIDIActionNextSong {
+		if !pressed {
+			return nil
+		}
+		if p.playback == nil {
+			return ErrPlaybackMissing
+		}
+
+		return p.playback.Next()
+	}
+
+	if p.output == nil {
+		return ErrOutputDisabled
+	}
+
+	switch m.Action {
+	case cntl.MIDIActionScene:
+		if !pressed {
+			return nil
+		}
+
+		scene, ok := ds.DMXScenes[m.Target]
+		if !ok {
+			return fmt.Errorf("failed to find scene with id %s", m.Target)
+		}
+
+		dmxCommands, err := dmx.RenderScene(ds, scene)
+		if err != nil {
+			return fmt.Errorf("failed to render scene %s: %v", m.Target, err)
+		}
+
+		p.play(m.BarParams, dmxCommands)
+
+	case cntl.MIDIActionPreset:
+		if !pressed {
+			return nil
+		}
+
+		preset, ok := ds.DMXPresets[m.Target]
+		if !ok {
+			return fmt.Errorf("failed to find preset with id %s", m.Target)
+		}
+
+		dmxCommands, err := dmx.RenderPreset(ds, preset, m.PresetArgs)
+		if err != nil {
+			return fmt.Errorf("failed to render preset %s: %v", m.Target, err)
+		}
+
+		p.play(m.BarParams, dmxCommands)
+
+	case cntl.MIDIActionFlash:
+		devices, err := dmx.ResolveDeviceGroup(ds, m.Target)
+		if err != nil {
+			return err
+		}
+
+		p.output.flash(m.ID, intensityChannels(ds, devices), pressed)
+
+	case cntl.MIDIActionMaster:
+		p.output.SetMaster(float64(value) / 127)
+
+	case cntl.MIDIActionBlackout:
+		// a control change holds the blackout while it is pressed, notes and program changes toggle it
+		if m.Message == cntl.MIDIMessageControlChange {
+			p.output.SetBlackout(pressed)
+		} else if pressed {
+			p.output.SetBlackout(!p.output.Blackout())
+		}
+	}
+
+	return nil
+}
+
+// play plays the given DMX commands once in the background
+func (p *Process) play(bp cntl.BarParams, dmxCommands []cntl.DMXCommands) {
+	if len(dmxCommands) == 0 {
+		return
+	}
+
+	playback.DefaultBarParams(&bp)
+
+	p.mu.Lock()
+	ctx := p.ctx
+	p.mu.Unlock()
+
+	commands := playback.ToPlayable(bp, dmxCommands)
+	go func() {
+		if err := playback.Play(ctx, p.logger, []playback.TransportWriter{p.output}, commands); err != nil && err != playback.ErrCancelled {
+			p.logger.Errorf("failed to play: %v", err)
+		}
+	}()
+}
@@ -0,0 +1,219 @@
+package live
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/StageAutoControl/controller/pkg/artnet"
+	"github.com/StageAutoControl/controller/pkg/cntl"
+)
+
+type fakeLoader struct {
+	ds *cntl.DataStore
+}
+
+func (l *fakeLoader) Load() (*cntl.DataStore, error) {
+	return l.ds, nil
+}
+
+type fakePlayback struct {
+	next int
+}
+
+func (p *fakePlayback) Next() error {
+	p.next++
+	return nil
+}
+
+type inputPlayback struct {
+	fakePlayback
+	inputs []int8
+}
+
+func (p *inputPlayback) MIDIInputs() []int8 {
+	return p.inputs
+}
+
+type fakeStorage struct {
+	config Config
+}
+
+func (s *fakeStorage) Read(key string, value interface{}) error {
+	*value.(*Config) = s.config
+	return nil
+}
+
+func (s *fakeStorage) Write(key string, value interface{}) error {
+	return nil
+}
+
+func processDataStore() *cntl.DataStore {
+	ds := outputDataStore()
+	ds.MIDIMappings = map[string]*cntl.MIDIMapping{
+		"master":   {ID: "master", Message: cntl.MIDIMessageControlChange, Channel: 1, Data1: 7, Action: cntl.MIDIActionMaster},
+		"blackout": {ID: "blackout", Message: cntl.MIDIMessageNote, Channel: 1, Data1: 60, Action: cntl.MIDIActionBlackout},
+		"next":     {ID: "next", Message: cntl.MIDIMessageProgramChange, Data1: 1, Action: cntl.MIDIActionNextSong},
+		"flash":    {ID: "flash", Message: cntl.MIDIMessageNote, Channel: 1, Data1: 61, Action: cntl.MIDIActionFlash, Target: "bar"},
+		"invalid":  {ID: "invalid", Message: cntl.MIDIMessageNote, Channel: 1, Data1: 60, Action: "fog"},
+	}
+
+	return ds
+}
+
+func newTestProcess(ds *cntl.DataStore) (*Process, *fakeController, *fakePlayback) {
+	c := &fakeController{values: make(map[channel]uint8)}
+	pb := &fakePlayback{}
+	p := NewProcess(&fakeLoader{ds}, nil, NewOutput(c), pb)
+	p.SetLogger(logrus.NewEntry(logrus.New()))
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+
+	return p, c, pb
+}
+
+func TestProcess_Handle(t *testing.T) {
+	p, c, pb := newTestProcess(processDataStore())
+	defer p.Stop()
+
+	if err := p.load(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	p.output.SetDMXChannelValue(artnet.ChannelValue{Channel: 1, Value: 200})
+
+	exp := []struct {
+		cmd      cntl.MIDICommand
+		master   float64
+		blackout bool
+		next     int
+		bar      uint8
+	}{
+		{cmd: cntl.MIDICommand{Status: 0xB0, Data1: 7, Data2: 127}, master: 1},
+		{cmd: cntl.MIDICommand{Status: 0xB1, Data1: 7, Data2: 0}, master: 1},
+		{cmd: cntl.MIDICommand{Status: 0xB0, Data1: 7, Data2: 0}, master: 0},
+		{cmd: cntl.MIDICommand{Status: 0xB0, Data1: 7, Data2: 127}, master: 1},
+		{cmd: cntl.MIDICommand{Status: 0x90, Data1: 60, Data2: 100}, master: 1, blackout: true},
+		{cmd: cntl.MIDICommand{Status: 0x80, Data1: 60}, master: 1, blackout: true},
+		{cmd: cntl.MIDICommand{Status: 0x90, Data1: 60, Data2: 100}, master: 1},
+		{cmd: cntl.MIDICommand{Status: 0xC5, Data1: 1}, master: 1, next: 1},
+		{cmd: cntl.MIDICommand{Status: 0x90, Data1: 61, Data2: 100}, master: 1, next: 1, bar: 255},
+		{cmd: cntl.MIDICommand{Status: 0x90, Data1: 61, Data2: 0}, master: 1, next: 1},
+	}
+
+	for i, e := range exp {
+		if err := p.handle(e.cmd); err != nil {
+			t.Fatalf("Unexpected error at index %d: %v", i, err)
+		}
+
+		if p.output.Master() != e.master {
+			t.Errorf("Expected master to be %v, got %v at index %d", e.master, p.output.Master(), i)
+		}
+		if p.output.Blackout() != e.blackout {
+			t.Errorf("Expected blackout to be %v, got %v at index %d", e.blackout, p.output.Blackout(), i)
+		}
+		if pb.next != e.next {
+			t.Errorf("Expected %d next songs, got %d at index %d", e.next, pb.next, i)
+		}
+		if v := c.value(0, 10); v != e.bar {
+			t.Errorf("Expected the flashed bar to be %d, got %d at index %d", e.bar, v, i)
+		}
+	}
+}
+
+func TestProcess_Learn(t *testing.T) {
+	p, _, pb := newTestProcess(processDataStore())
+	defer p.Stop()
+
+	if err := p.load(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	learned := make(chan cntl.MIDIMapping)
+	go func() {
+		m, err := p.Learn(context.Background())
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		learned <- m
+	}()
+
+	// wait for the learner to be registered
+	for {
+		p.mu.Lock()
+		registered := len(p.learners) > 0
+		p.mu.Unlock()
+
+		if registered {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// note offs are not learned
+	for _, cmd := range []cntl.MIDICommand{{Status: 0x80, Data1: 60}, {Status: 0xC0, Data1: 1}} {
+		if err := p.handle(cmd); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if m := <-learned; m.Message != cntl.MIDIMessageProgramChange || m.Channel != 1 || m.Data1 != 1 {
+		t.Errorf("Expected to learn program change 1 on channel 1, got %+v", m)
+	}
+	if pb.next != 0 {
+		t.Errorf("Expected the learned message not to trigger the next song, got %d", pb.next)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if _, err := p.Learn(ctx); err != ErrLearnTimeout {
+		t.Errorf("Expected to get ErrLearnTimeout, got %v", err)
+	}
+
+	p.Stop()
+	if _, err := p.Learn(context.Background()); err != ErrNotRunning {
+		t.Errorf("Expected to get ErrNotRunning, got %v", err)
+	}
+}
+
+func TestProcess_Reload(t *testing.T) {
+	ds := processDataStore()
+	p, _, pb := newTestProcess(ds)
+	defer p.Stop()
+
+	if err := p.load(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	next := cntl.MIDICommand{Status: 0xC0, Data1: 2}
+	ds.MIDIMappings["next"] = &cntl.MIDIMapping{ID: "next", Message: cntl.MIDIMessageProgramChange, Data1: 2, Action: cntl.MIDIActionNextSong}
+
+	if err := p.handle(next); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if pb.next != 0 {
+		t.Errorf("Expected the changed mapping not to be used before reloading, got %d next songs", pb.next)
+	}
+
+	p.Reload()
+	if err := p.handle(next); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if pb.next != 1 {
+		t.Errorf("Expected the changed mapping to be used after reloading, got %d next songs", pb.next)
+	}
+}
+
+func TestProcess_Start_InputUsedByPlayback(t *testing.T) {
+	pb := &inputPlayback{inputs: []int8{2}}
+	p := NewProcess(&fakeLoader{processDataStore()}, &fakeStorage{Config{InputDeviceID: 2}}, nil, pb)
+	p.SetLogger(logrus.NewEntry(logrus.New()))
+
+	if err := p.Start(context.Background()); err == nil {
+		t.Error("Expected to get an error for a midi input used by the playback")
+	}
+
+	if inputs := p.MIDIInputs(); len(inputs) != 0 {
+		t.Errorf("Expected to use no midi inputs when not running, got %v", inputs)
+	}
+}
@@ -0,0 +1,111 @@
+package midi
+
+import "github.com/StageAutoControl/controller/pkg/cntl"
+
+// MIDI system real time and common messages used for clock synchronisation
+const (
+	StatusClockTick           uint8 = 0xF8
+	StatusStart               uint8 = 0xFA
+	StatusContinue            uint8 = 0xFB
+	StatusStop                uint8 = 0xFC
+	StatusSongPositionPointer uint8 = 0xF2
+)
+
+// TicksPerQuarter defines how many MIDI clock ticks are sent per quarter note
+const TicksPerQuarter = 24
+
+// FramesPerTick defines how many render frames are played per MIDI clock tick
+const FramesPerTick = uint64(cntl.RenderFrames) / 4 / TicksPerQuarter
+
+// framesPerSixteenth defines how many render frames a song position pointer step of a sixteenth note has
+const framesPerSixteenth = uint64(cntl.RenderFrames) / 16
+
+// SongPositionToFrame converts the data bytes of a song position pointer to a frame
+func SongPositionToFrame(lsb, msb uint8) uint64 {
+	return (uint64(msb&0x7F)<<7 | uint64(lsb&0x7F)) * framesPerSixteenth
+}
+
+// FrameToSongPosition converts a frame to the data bytes of a song position pointer, rounding down to the sixteenth
+func FrameToSongPosition(frame uint64) (lsb, msb uint8) {
+	sixteenths := frame / framesPerSixteenth
+	return uint8(sixteenths & 0x7F), uint8(sixteenths >> 7 & 0x7F)
+}
+
+// Clock generates the MIDI clock ticks and, if enabled, the transport messages for the played frames.
+// Frames of bar 0, e.g. a count-in, are not part of a song, only clock ticks are generated for them.
+// Paused commands stop the clock and move its song position without ticks, the next played frame continues it.
+type Clock struct {
+	transport bool
+	running   bool
+	// position is the frame the receiving device continues at
+	position uint64
+}
+
+// NewClock returns a new Clock, generating start, stop, continue and song position pointer messages if transport is true
+func NewClock(transport bool) *Clock {
+	return &Clock{transport: transport}
+}
+
+// Messages returns the messages to send before the MIDI commands of the given frame
+func (c *Clock) Messages(cmd cntl.Command) []cntl.MIDICommand {
+	if cmd.Paused {
+		return c.pause(cmd)
+	}
+
+	var msgs []cntl.MIDICommand
+
+	if c.transport && cmd.Bar > 0 {
+		switch {
+		case !c.running && cmd.Frame == 0:
+			msgs = append(msgs, cntl.MIDICommand{Status: StatusStart})
+
+		case !c.running && cmd.Frame == c.position:
+			msgs = append(msgs, cntl.MIDICommand{Status: StatusContinue})
+
+		case !c.running:
+			msgs = append(msgs, songPosition(cmd.Frame), cntl.MIDICommand{Status: StatusContinue})
+
+		case cmd.Frame != c.position:
+			msgs = append(msgs, cntl.MIDICommand{Status: StatusStop}, songPosition(cmd.Frame), cntl.MIDICommand{Status: StatusContinue})
+		}
+
+		c.running = true
+		c.position = cmd.Frame + 1
+	}
+
+	if cmd.Frame%FramesPerTick == 0 {
+		msgs = append(msgs, cntl.MIDICommand{Status: StatusClockTick})
+	}
+
+	return msgs
+}
+
+// pause returns the messages to stop the clock and move it to the frame of the given paused command
+func (c *Clock) pause(cmd cntl.Command) []cntl.MIDICommand {
+	if !c.transport || cmd.Bar == 0 {
+		return nil
+	}
+
+	msgs := c.Stop()
+	if cmd.Frame != c.position {
+		msgs = append(msgs, songPosition(cmd.Frame))
+		c.position = cmd.Frame
+	}
+
+	return msgs
+}
+
+// Stop returns the messages to send when the playback stopped
+func (c *Clock) Stop() []cntl.MIDICommand {
+	if !c.running {
+		return nil
+	}
+
+	c.running = false
+	return []cntl.MIDICommand{{Status: StatusStop}}
+}
+
+func songPosition(frame uint64) cntl.MIDICommand {
+	lsb, msb := FrameToSongPosition(frame)
+	return cntl.MIDICommand{Status: StatusSongPositionPointer, Data1: lsb, Data2: msb}
+}
@@ -29,3 +29,150 @@ func TestStreamlineMidiCommands(t *testing.T) {
 		t.Errorf("Expected map to have length 2 at index 200, got %d", len(mcs[0]))
 	}
 }
+
+func TestSongPosition(t *testing.T) {
+	exp := []struct {
+		lsb, msb uint8
+		frame    uint64
+	}{
+		{0, 0, 0},
+		{1, 0, 12},
+		{16, 0, 192},
+		{0, 1, 1536},
+		{0x7F, 0x7F, 16383 * 12},
+	}
+
+	for i, e := range exp {
+		if frame := SongPositionToFrame(e.lsb, e.msb); frame != e.frame {
+			t.Errorf("Expected to get frame %d at index %d, got %d", e.frame, i, frame)
+		}
+
+		if lsb, msb := FrameToSongPosition(e.frame + 5); lsb != e.lsb || msb != e.msb {
+			t.Errorf("Expected to get song position %d/%d at index %d, got %d/%d", e.lsb, e.msb, i, lsb, msb)
+		}
+	}
+}
+
+func TestClock_Messages(t *testing.T) {
+	tick := cntl.MIDICommand{Status: StatusClockTick}
+	start := cntl.MIDICommand{Status: StatusStart}
+	stop := cntl.MIDICommand{Status: StatusStop}
+	cont := cntl.MIDICommand{Status: StatusContinue}
+	bar2 := cntl.MIDICommand{Status: StatusSongPositionPointer, Data1: 16}
+
+	c := NewClock(true)
+	exp := []struct {
+		cmd  cntl.Command
+		msgs []cntl.MIDICommand
+	}{
+		{cntl.Command{FrameState: cntl.FrameState{Frame: 0}}, []cntl.MIDICommand{tick}},
+		{cntl.Command{FrameState: cntl.FrameState{Frame: 1}}, nil},
+		{cntl.Command{FrameState: cntl.FrameState{Frame: 0, Bar: 1}}, []cntl.MIDICommand{start, tick}},
+		{cntl.Command{FrameState: cntl.FrameState{Frame: 1, Bar: 1}}, nil},
+		{cntl.Command{FrameState: cntl.FrameState{Frame: 2, Bar: 1}}, []cntl.MIDICommand{tick}},
+		{cntl.Command{FrameState: cntl.FrameState{Frame: 192, Bar: 2}}, []cntl.MIDICommand{stop, bar2, cont, tick}},
+		{cntl.Command{FrameState: cntl.FrameState{Frame: 193, Bar: 2}}, nil},
+	}
+
+	for i, e := range exp {
+		msgs := c.Messages(e.cmd)
+		if len(msgs) != len(e.msgs) {
+			t.Errorf("Expected to get messages %+v at index %d, got %+v", e.msgs, i, msgs)
+			continue
+		}
+
+		for j := range msgs {
+			if msgs[j] != e.msgs[j] {
+				t.Errorf("Expected to get messages %+v at index %d, got %+v", e.msgs, i, msgs)
+				break
+			}
+		}
+	}
+
+	if msgs := c.Stop(); len(msgs) != 1 || msgs[0] != stop {
+		t.Errorf("Expected to get a stop message, got %+v", msgs)
+	}
+	if msgs := c.Stop(); len(msgs) != 0 {
+		t.Errorf("Expected to get no messages when stopped, got %+v", msgs)
+	}
+
+	c.Messages(cntl.Command{FrameState: cntl.FrameState{Frame: 192, Bar: 2}})
+	if msgs := c.Messages(cntl.Command{FrameState: cntl.FrameState{Frame: 193, Bar: 2}}); len(msgs) != 0 {
+		t.Errorf("Expected to get no messages after continuing, got %+v", msgs)
+	}
+
+	if msgs := NewClock(false).Messages(cntl.Command{FrameState: cntl.FrameState{Frame: 0, Bar: 1}}); len(msgs) != 1 || msgs[0] != tick {
+		t.Errorf("Expected to get only a tick without transport, got %+v", msgs)
+	}
+}
+
+func TestClock_Messages_Paused(t *testing.T) {
+	tick := cntl.MIDICommand{Status: StatusClockTick}
+	start := cntl.MIDICommand{Status: StatusStart}
+	stop := cntl.MIDICommand{Status: StatusStop}
+	cont := cntl.MIDICommand{Status: StatusContinue}
+	bar2 := cntl.MIDICommand{Status: StatusSongPositionPointer, Data1: 16}
+
+	c := NewClock(true)
+	exp := []struct {
+		cmd  cntl.Command
+		msgs []cntl.MIDICommand
+	}{
+		{cntl.Command{FrameState: cntl.FrameState{Frame: 0, Bar: 1}}, []cntl.MIDICommand{start, tick}},
+		{cntl.Command{FrameState: cntl.FrameState{Frame: 1, Bar: 1}}, nil},
+		{cntl.Command{FrameState: cntl.FrameState{Frame: 2, Bar: 1}, Paused: true}, []cntl.MIDICommand{stop}},
+		{cntl.Command{FrameState: cntl.FrameState{Frame: 2, Bar: 1}}, []cntl.MIDICommand{cont, tick}},
+		{cntl.Command{FrameState: cntl.FrameState{Frame: 3, Bar: 1}, Paused: true}, []cntl.MIDICommand{stop}},
+		{cntl.Command{FrameState: cntl.FrameState{Frame: 192, Bar: 2}, Paused: true}, []cntl.MIDICommand{bar2}},
+		{cntl.Command{FrameState: cntl.FrameState{Frame: 192, Bar: 2}}, []cntl.MIDICommand{cont, tick}},
+		{cntl.Command{FrameState: cntl.FrameState{Frame: 193, Bar: 2}}, nil},
+	}
+
+	for i, e := range exp {
+		msgs := c.Messages(e.cmd)
+		if len(msgs) != len(e.msgs) {
+			t.Errorf("Expected to get messages %+v at index %d, got %+v", e.msgs, i, msgs)
+			continue
+		}
+
+		for j := range msgs {
+			if msgs[j] != e.msgs[j] {
+				t.Errorf("Expected to get messages %+v at index %d, got %+v", e.msgs, i, msgs)
+				break
+			}
+		}
+	}
+}
+
+func TestMTCDecoder(t *testing.T) {
+	// 01:02:03:04 at 25 fps, sent as quarter frames
+	quarterFrames := []uint8{0x04, 0x10, 0x23, 0x30, 0x42, 0x50, 0x61, 0x72}
+
+	var d MTCDecoder
+	for i, data := range quarterFrames {
+		tc, ok := d.QuarterFrame(data)
+		if i < len(quarterFrames)-1 {
+			if ok {
+				t.Errorf("Expected to get no timecode before the last quarter frame at index %d, got %v", i, tc)
+			}
+			continue
+		}
+
+		if !ok || tc.String() != "01:02:03:06" || tc.Rate != cntl.TimecodeRate25 {
+			t.Errorf("Expected to get timecode 01:02:03:06 at 25 fps, got %v at %s", tc, tc.Rate)
+		}
+	}
+
+	if _, ok := d.QuarterFrame(0x72); ok {
+		t.Error("Expected to get no timecode from an incomplete sequence")
+	}
+
+	tc, ok := d.FullFrame([]byte{0xF0, 0x7F, 0x7F, 0x01, 0x01, 0x61, 0x02, 0x03, 0x04, 0xF7})
+	if !ok || tc.String() != "01:02:03:04" || tc.Rate != cntl.TimecodeRate30 {
+		t.Errorf("Expected to get timecode 01:02:03:04 at 30 fps, got %v at %s", tc, tc.Rate)
+	}
+
+	if _, ok := d.FullFrame([]byte{0xF0, 0x7E, 0x7F, 0x01, 0x01, 0x61, 0x02, 0x03, 0x04, 0xF7}); ok {
+		t.Error("Expected to get no timecode from another system exclusive message")
+	}
+}
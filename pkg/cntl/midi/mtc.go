@@ -0,0 +1,61 @@
+package midi
+
+import (
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/timecode"
+)
+
+// StatusQuarterFrame is the status of a MIDI timecode quarter frame message
+const StatusQuarterFrame uint8 = 0xF1
+
+// mtcRates maps the rate bits of MIDI timecode to timecode rates
+var mtcRates = []cntl.TimecodeRate{cntl.TimecodeRate24, cntl.TimecodeRate25, cntl.TimecodeRate2997, cntl.TimecodeRate30}
+
+// MTCDecoder assembles timecodes from MIDI timecode quarter frame and full frame messages
+type MTCDecoder struct {
+	pieces   [8]uint8
+	received uint8
+}
+
+// QuarterFrame decodes the data byte of a quarter frame message and returns the timecode once all eight pieces
+// are received. As the pieces are sent over two frames, the returned timecode is compensated by two frames.
+func (d *MTCDecoder) QuarterFrame(data uint8) (timecode.Timecode, bool) {
+	piece := data >> 4 & 0x07
+	d.pieces[piece] = data & 0x0F
+	d.received |= 1 << piece
+
+	// the timecode is complete with the last piece when running forward
+	if piece != 7 || d.received != 0xFF {
+		return timecode.Timecode{}, false
+	}
+	d.received = 0
+
+	p := d.pieces
+	tc := timecode.Timecode{
+		Frames:  p[0] | p[1]&0x01<<4,
+		Seconds: p[2] | p[3]&0x03<<4,
+		Minutes: p[4] | p[5]&0x03<<4,
+		Hours:   p[6] | p[7]&0x01<<4,
+		Rate:    mtcRates[p[7]>>1&0x03],
+	}
+
+	return tc.Add(2), true
+}
+
+// FullFrame decodes a full frame system exclusive message, F0 7F <device> 01 01 hh mm ss ff F7
+func (d *MTCDecoder) FullFrame(msg []byte) (timecode.Timecode, bool) {
+	if len(msg) < 10 || msg[0] != 0xF0 || msg[1] != 0x7F || msg[3] != 0x01 || msg[4] != 0x01 || msg[9] != 0xF7 {
+		return timecode.Timecode{}, false
+	}
+
+	// a full frame message interrupts the quarter frames, e.g. when the master locates
+	d.received = 0
+
+	return timecode.Timecode{
+		Hours:   msg[5] & 0x1F,
+		Minutes: msg[6],
+		Seconds: msg[7],
+		Frames:  msg[8],
+		Rate:    mtcRates[msg[5]>>5&0x03],
+	}, true
+}
@@ -0,0 +1,7 @@
+package cntl
+
+// ValidNoteValue returns whether the given note value can be rendered on the RenderFrames grid.
+// Triplets are expressed by their count per bar, e.g. 12 for eighth triplets.
+func ValidNoteValue(v uint8) bool {
+	return v != 0 && RenderFrames%v == 0
+}
@@ -1,17 +1,58 @@
 package playback
 
-import "errors"
+import (
+	"errors"
+	"time"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+)
 
 // Player errors
 var (
-	ErrCancelled                = errors.New("playback cancelled")
-	ErrNoSongIDOrSetListIDGiven = errors.New("no songID or setListID given")
+	ErrCancelled                  = errors.New("playback cancelled")
+	ErrNoSongIDOrSetListIDGiven   = errors.New("no songID or setListID given")
+	ErrNotPlaying                 = errors.New("no song is playing")
+	ErrStartBarAndMarkerGiven     = errors.New("either a start bar or a start marker can be given, not both")
+	ErrTempoMultiplierAndBPMGiven = errors.New("either a tempo multiplier or a BPM can be given, not both")
+	ErrMIDIClockInputClosed       = errors.New("midi clock input closed")
+	ErrTimecodeInputClosed        = errors.New("timecode input closed")
+	ErrSongHasNoTimecode          = errors.New("song has no timecode to chase")
+	ErrStartOptionsWhileChasing   = errors.New("a song cannot be started at a bar, marker or with a count-in while chasing timecode")
 )
 
 const (
 	// ProcessName defines the name of the playback process
 	ProcessName      = "playback"
 	paramsStorageKey = "playback_process"
+
+	// pausePollInterval defines how often a paused playback checks whether it is resumed
+	pausePollInterval = 10 * time.Millisecond
+
+	// minLateness defines how late a frame must at least be played to be reported
+	minLateness = 5 * time.Millisecond
+
+	// chaseTolerance defines how far the played position may differ from an incoming timecode before locating to it
+	chaseTolerance = 80 * time.Millisecond
+
+	// timecodeFreewheel defines how long playback continues when the incoming timecode drops out
+	timecodeFreewheel = time.Second
+
+	// writerQueueSize defines how many frames can be queued for a writer before playback waits for it
+	writerQueueSize = 4 * int(cntl.RenderFrames)
+
+	// minSpeed is the lowest speed in BPM a song is played with when its tempo is adjusted
+	minSpeed = 1
+
+	// tapTimeout defines after how long without a tap the next tap starts counting a new tempo
+	tapTimeout = 2 * time.Second
+
+	// maxTaps defines how many of the latest taps are averaged
+	maxTaps = 8
+
+	// defaultSpeed, defaultNoteCount and defaultNoteValue are used to play entities once, if no bar params are given
+	defaultSpeed     = 140
+	defaultNoteCount = 4
+	defaultNoteValue = 4
 )
 
 var defaultConfig = `
@@ -31,7 +72,29 @@ var defaultConfig = `
     },
     "midi": {
       "enabled": false,
-      "outputDeviceId": 0
+      "outputDeviceId": 0,
+      "clock": false,
+      "transport": false
+    }
+  },
+  "controls": {
+    "midi": {
+      "enabled": false,
+      "inputDeviceId": -1
+    }
+  },
+  "sync": {
+    "midiClockInput": {
+      "enabled": false,
+      "inputDeviceId": -1
+    },
+    "mtcInput": {
+      "enabled": false,
+      "inputDeviceId": -1
+    },
+    "ltcInput": {
+      "enabled": false,
+      "file": ""
     }
   }
 }
@@ -0,0 +1,77 @@
+package playback
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/song"
+)
+
+// Count-in clicks are sent as General MIDI percussion on channel 10, so no note off is required
+const (
+	clickStatus         uint8 = 0x99
+	clickNoteAccent     uint8 = 76
+	clickNote           uint8 = 77
+	clickVelocityAccent uint8 = 127
+	clickVelocity       uint8 = 100
+)
+
+// CountIn renders the given amount of bars to be played before starting the given commands at the start frame.
+// The count-in uses the adjusted tempo and time signature at the start frame and carries the channel state at that frame,
+// so the rig is already in the correct state while counting in. Count-in frames are in bar 0 as they are not part of the song.
+func CountIn(commands []cntl.Command, start uint64, opts StartOptions) ([]cntl.Command, error) {
+	var bc *cntl.BarChange
+	for i := int(start); i >= 0; i-- {
+		if i < len(commands) && commands[i].BarChange != nil {
+			bc = commands[i].BarChange
+			break
+		}
+	}
+	if bc == nil {
+		return nil, fmt.Errorf("cannot find a bar change before frame %d", start)
+	}
+
+	factor, err := opts.Tempo.factor(commands)
+	if err != nil {
+		return nil, err
+	}
+
+	tp := tempo{factor: factor}
+	if err := tp.seek(commands, int(start)); err != nil {
+		return nil, err
+	}
+	tp.next()
+
+	params := bc.BarParams
+	params.Speed = uint16(math.Round(tp.bpm()))
+	barChange := &cntl.BarChange{BarParams: params}
+
+	noteLength := song.CalcNoteLength(barChange)
+	cs := make([]cntl.Command, uint64(opts.CountIn)*song.CalcBarLength(barChange))
+	for i := range cs {
+		cs[i].Frame = uint64(i)
+		cs[i].Note = uint8(uint64(i)/noteLength%uint64(params.NoteCount)) + 1
+		cs[i].DMXCommands = cntl.DMXCommands{}
+		cs[i].MIDICommands = cntl.MIDICommands{}
+
+		if opts.Click && uint64(i)%noteLength == 0 {
+			cs[i].MIDICommands = append(cs[i].MIDICommands, clickCommand(cs[i].Note == 1))
+		}
+	}
+
+	if len(cs) > 0 {
+		cs[0].BarChange = barChange
+		cs[0].DMXCommands = ChannelState(commands, int(start))
+	}
+
+	return cs, nil
+}
+
+func clickCommand(accent bool) cntl.MIDICommand {
+	if accent {
+		return cntl.MIDICommand{Status: clickStatus, Data1: clickNoteAccent, Data2: clickVelocityAccent}
+	}
+
+	return cntl.MIDICommand{Status: clickStatus, Data1: clickNote, Data2: clickVelocity}
+}
@@ -0,0 +1,132 @@
+package playback
+
+import (
+	"testing"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+)
+
+func TestNavigation_StartFrame(t *testing.T) {
+	s, commands := navigationSong(t)
+	nav := NewNavigation(s, commands)
+
+	exp := []struct {
+		opts  StartOptions
+		frame uint64
+		err   bool
+	}{
+		{StartOptions{}, 0, false},
+		{StartOptions{Bar: 2}, 192, false},
+		{StartOptions{Marker: "outro"}, 384, false},
+		{StartOptions{Marker: "solo"}, 192, false},
+		{StartOptions{Bar: 9}, 0, true},
+		{StartOptions{Marker: "unknown"}, 0, true},
+		{StartOptions{Bar: 2, Marker: "outro"}, 0, true},
+	}
+
+	for i, e := range exp {
+		frame, err := nav.startFrame(e.opts)
+		if (err != nil) != e.err {
+			t.Errorf("Expected to get error %t at index %d, got %v", e.err, i, err)
+		}
+		if frame != e.frame {
+			t.Errorf("Expected to get frame %d at index %d, got %d", e.frame, i, frame)
+		}
+	}
+}
+
+func TestNavigation_StartFrame_TimeSignature(t *testing.T) {
+	s, commands := signatureSong(t)
+	nav := NewNavigation(s, commands)
+
+	exp := []struct {
+		opts  StartOptions
+		frame uint64
+	}{
+		{StartOptions{Bar: 2}, 192},
+		{StartOptions{Bar: 3}, 384},
+		{StartOptions{Bar: 5}, 720},
+		{StartOptions{Marker: "bridge"}, 552},
+	}
+
+	for i, e := range exp {
+		frame, err := nav.startFrame(e.opts)
+		if err != nil {
+			t.Errorf("Unexpected error at index %d: %v", i, err)
+		}
+		if frame != e.frame {
+			t.Errorf("Expected to get frame %d at index %d, got %d", e.frame, i, frame)
+		}
+	}
+
+	cs, err := CountIn(commands, 720, StartOptions{CountIn: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(cs) != 168 {
+		t.Errorf("Expected to count in one bar of 7/8 with 168 frames, got %d", len(cs))
+	}
+}
+
+func TestCountIn(t *testing.T) {
+	_, commands := navigationSong(t)
+	ch1 := cntl.DMXCommand{Universe: 1, Channel: 1, Value: cntl.DMXValue{Value: 255}}
+	commands[10].DMXCommands = cntl.DMXCommands{ch1}
+
+	cs, err := CountIn(commands, 384, StartOptions{CountIn: 2, Click: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(cs) != 384 {
+		t.Fatalf("Expected to get 384 count-in frames, got %d", len(cs))
+	}
+
+	if cs[0].BarChange == nil || cs[0].BarChange.Speed != 6000 || cs[0].BarChange.NoteCount != 4 {
+		t.Errorf("Expected the count-in to start with the bar change at the start frame, got %+v", cs[0].BarChange)
+	}
+
+	if !cs[0].DMXCommands.Equals(cntl.DMXCommands{ch1}) {
+		t.Errorf("Expected the count-in to carry the channel state, got %+v", cs[0].DMXCommands)
+	}
+
+	exp := []struct {
+		frame int
+		note  uint8
+		click *cntl.MIDICommand
+	}{
+		{0, 1, &cntl.MIDICommand{Status: clickStatus, Data1: clickNoteAccent, Data2: clickVelocityAccent}},
+		{1, 1, nil},
+		{48, 2, &cntl.MIDICommand{Status: clickStatus, Data1: clickNote, Data2: clickVelocity}},
+		{144, 4, &cntl.MIDICommand{Status: clickStatus, Data1: clickNote, Data2: clickVelocity}},
+		{192, 1, &cntl.MIDICommand{Status: clickStatus, Data1: clickNoteAccent, Data2: clickVelocityAccent}},
+	}
+
+	for i, e := range exp {
+		cmd := cs[e.frame]
+		if cmd.Note != e.note {
+			t.Errorf("Expected to get note %d at index %d, got %d", e.note, i, cmd.Note)
+		}
+
+		if e.click == nil {
+			if len(cmd.MIDICommands) != 0 {
+				t.Errorf("Expected to get no click at index %d, got %+v", i, cmd.MIDICommands)
+			}
+			continue
+		}
+
+		if len(cmd.MIDICommands) != 1 || cmd.MIDICommands[0] != *e.click {
+			t.Errorf("Expected to get click %+v at index %d, got %+v", *e.click, i, cmd.MIDICommands)
+		}
+	}
+
+	silent, err := CountIn(commands, 0, StartOptions{CountIn: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i, cmd := range silent {
+		if len(cmd.MIDICommands) != 0 {
+			t.Errorf("Expected to get no clicks without click at index %d, got %+v", i, cmd.MIDICommands)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package playback
+
+import (
+	"context"
+	"time"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/midi"
+)
+
+// frameSource drives the frames of a scheduler by an external clock instead of the tempo of the song
+type frameSource interface {
+	// next blocks until the next frame is due and returns the frame to continue at, or -1 to continue with the next one
+	next(ctx context.Context) (int, error)
+}
+
+// midiClockFollower follows the MIDI clock of an external device, e.g. a DAW.
+// Every clock tick queues midi.FramesPerTick frames, the first one is played on the tick and the others are spread
+// evenly over the interval of the previous ticks. Start, stop, continue and song position pointers are followed as well.
+type midiClockFollower struct {
+	clock    Clock
+	commands <-chan cntl.MIDICommand
+
+	running  bool
+	seek     int
+	lastTick time.Time
+	interval time.Duration
+
+	// queue holds the frames to play, -1 for the next frame or the frame to continue at
+	queue []int
+}
+
+func newMIDIClockFollower(clock Clock, commands <-chan cntl.MIDICommand) *midiClockFollower {
+	return &midiClockFollower{clock: clock, commands: commands, seek: -1}
+}
+
+func (f *midiClockFollower) next(ctx context.Context) (int, error) {
+	for {
+		var due <-chan time.Time
+		switch {
+		case len(f.queue) >= int(midi.FramesPerTick):
+			// the first frame of a tick, or the follower is behind
+			return f.pop(), nil
+
+		case len(f.queue) > 0:
+			due = f.clock.After(f.interval / time.Duration(midi.FramesPerTick))
+
+		case f.commands == nil:
+			return 0, ErrMIDIClockInputClosed
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, ErrCancelled
+
+		case <-due:
+			return f.pop(), nil
+
+		case cmd, ok := <-f.commands:
+			if !ok {
+				f.commands = nil
+				continue
+			}
+
+			f.handle(cmd)
+		}
+	}
+}
+
+func (f *midiClockFollower) handle(cmd cntl.MIDICommand) {
+	switch cmd.Status {
+	case midi.StatusStart:
+		f.running = true
+		f.seek = 0
+
+	case midi.StatusContinue:
+		f.running = true
+
+	case midi.StatusStop:
+		f.running = false
+
+	case midi.StatusSongPositionPointer:
+		f.seek = int(midi.SongPositionToFrame(cmd.Data1, cmd.Data2))
+
+	case midi.StatusClockTick:
+		now := f.clock.Now()
+		if !f.lastTick.IsZero() {
+			f.interval = now.Sub(f.lastTick)
+		}
+		f.lastTick = now
+
+		if !f.running {
+			return
+		}
+
+		f.queue = append(f.queue, f.seek)
+		f.seek = -1
+		for i := uint64(1); i < midi.FramesPerTick; i++ {
+			f.queue = append(f.queue, -1)
+		}
+	}
+}
+
+func (f *midiClockFollower) pop() int {
+	frame := f.queue[0]
+	f.queue = f.queue[1:]
+	return frame
+}
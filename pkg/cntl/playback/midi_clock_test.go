@@ -0,0 +1,80 @@
+package playback
+
+import (
+	"context"
+	"testing"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/midi"
+	"github.com/sirupsen/logrus"
+)
+
+func clockTicks(n int) []cntl.MIDICommand {
+	ticks := make([]cntl.MIDICommand, n)
+	for i := range ticks {
+		ticks[i] = cntl.MIDICommand{Status: midi.StatusClockTick}
+	}
+	return ticks
+}
+
+func frameRange(from, to uint64) []uint64 {
+	var frames []uint64
+	for f := from; f < to; f++ {
+		frames = append(frames, f)
+	}
+	return frames
+}
+
+func TestScheduler_Play_MIDIClock(t *testing.T) {
+	start := cntl.MIDICommand{Status: midi.StatusStart}
+	stop := cntl.MIDICommand{Status: midi.StatusStop}
+	cont := cntl.MIDICommand{Status: midi.StatusContinue}
+	toBar2 := cntl.MIDICommand{Status: midi.StatusSongPositionPointer, Data1: 16}
+
+	// ticks before the start and while stopped are ignored, the tick after the last frame ends the song
+	full := append(append(clockTicks(5), start), clockTicks(289)...)
+
+	spp := append([]cntl.MIDICommand{start}, clockTicks(48)...)
+	spp = append(append(spp, stop), clockTicks(10)...)
+	spp = append(append(spp, toBar2, cont), clockTicks(193)...)
+
+	short := append([]cntl.MIDICommand{start}, clockTicks(10)...)
+
+	exp := []struct {
+		cmds   []cntl.MIDICommand
+		frames []uint64
+		err    error
+	}{
+		{full, frameRange(0, 576), nil},
+		{spp, append(frameRange(0, 96), frameRange(192, 576)...), nil},
+		{short, frameRange(0, 20), ErrMIDIClockInputClosed},
+	}
+
+	for i, e := range exp {
+		clock := &fakeClock{}
+		w := &orderWriter{}
+		in := &fakeMIDIInput{cmds: e.cmds}
+		s := &scheduler{
+			logger:  logrus.New(),
+			clock:   clock,
+			writers: []TransportWriter{w},
+			source:  newMIDIClockFollower(clock, in.Listen(context.Background())),
+		}
+
+		if err := s.play(context.Background(), schedulerCommands(), nil); err != e.err {
+			t.Errorf("Expected to get error %v at index %d, got %v", e.err, i, err)
+		}
+
+		if len(w.frames) != len(e.frames) {
+			t.Errorf("Expected to get %d frames at index %d, got %d", len(e.frames), i, len(w.frames))
+			continue
+		}
+
+		for j, f := range w.frames {
+			if f != e.frames[j] {
+				t.Errorf("Expected to get frame %d at position %d at index %d, got %d", e.frames[j], j, i, f)
+				break
+			}
+		}
+	}
+}
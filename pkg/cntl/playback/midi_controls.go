@@ -0,0 +1,68 @@
+package playback
+
+import (
+	"context"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/internal/logging"
+)
+
+// Matches returns whether the given MIDI command triggers, a note on with velocity 0 is a note off and never triggers
+func (t *MIDITrigger) Matches(cmd cntl.MIDICommand) bool {
+	if t == nil || t.Status != cmd.Status || t.Data1 != cmd.Data1 {
+		return false
+	}
+
+	return cmd.Status&0xF0 != 0x90 || cmd.Data2 > 0
+}
+
+// midiControls controls the navigation of the played song by incoming MIDI commands
+type midiControls struct {
+	logger     logging.Logger
+	config     MIDIControlsConfig
+	navigation func() *Navigation
+	clock      Clock
+}
+
+func (c *midiControls) listen(ctx context.Context, in MIDIInput) {
+	for cmd := range in.Listen(ctx) {
+		if err := c.handle(cmd); err != nil {
+			c.logger.Warnf("failed to handle MIDI control %#v: %v", cmd, err)
+		}
+	}
+}
+
+func (c *midiControls) handle(cmd cntl.MIDICommand) error {
+	nav := c.navigation()
+	if nav == nil {
+		return nil
+	}
+
+	switch {
+	case c.config.Skip.Matches(cmd):
+		return nav.Skip()
+
+	case c.config.Loop.Matches(cmd):
+		return nav.Loop("")
+
+	case c.config.Release.Matches(cmd):
+		nav.Release()
+		return nil
+
+	case c.config.Tap.Matches(cmd):
+		nav.Tap(c.clock.Now(), false)
+		return nil
+
+	case c.config.DownbeatTap.Matches(cmd):
+		nav.Tap(c.clock.Now(), true)
+		return nil
+	}
+
+	for _, m := range c.config.Markers {
+		if m.Matches(cmd) {
+			return nav.JumpTo(m.Marker)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package playback
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/sirupsen/logrus"
+)
+
+type fakeMIDIInput struct {
+	cmds []cntl.MIDICommand
+}
+
+func (i *fakeMIDIInput) Listen(ctx context.Context) <-chan cntl.MIDICommand {
+	c := make(chan cntl.MIDICommand, len(i.cmds))
+	for _, cmd := range i.cmds {
+		c <- cmd
+	}
+	close(c)
+
+	return c
+}
+
+func TestMIDIControls(t *testing.T) {
+	config := MIDIControlsConfig{
+		Skip:    &MIDITrigger{Status: 0x90, Data1: 60},
+		Loop:    &MIDITrigger{Status: 0x90, Data1: 61},
+		Release: &MIDITrigger{Status: 0x90, Data1: 62},
+		Markers: []MarkerTrigger{{MIDITrigger: MIDITrigger{Status: 0xC0, Data1: 3}, Marker: "outro"}},
+	}
+
+	exp := []struct {
+		cmds []cntl.MIDICommand
+		at   uint64
+		to   uint64
+	}{
+		{[]cntl.MIDICommand{{Status: 0x90, Data1: 60, Data2: 100}}, 384, 384},
+		{[]cntl.MIDICommand{{Status: 0x90, Data1: 60, Data2: 100}}, 192, 384},
+		{[]cntl.MIDICommand{{Status: 0x90, Data1: 60, Data2: 0}}, 192, 192},
+		{[]cntl.MIDICommand{{Status: 0x91, Data1: 60, Data2: 100}}, 192, 192},
+		{[]cntl.MIDICommand{{Status: 0xC0, Data1: 3}}, 192, 384},
+		{[]cntl.MIDICommand{{Status: 0x90, Data1: 61, Data2: 100}}, 384, 192},
+		{[]cntl.MIDICommand{{Status: 0x90, Data1: 61, Data2: 100}, {Status: 0x90, Data1: 62, Data2: 100}}, 384, 384},
+	}
+
+	for i, e := range exp {
+		s, commands := navigationSong(t)
+		nav := NewNavigation(s, commands)
+		nav.setFrame(200)
+		c := &midiControls{logger: logrus.New(), config: config, navigation: func() *Navigation { return nav }}
+
+		c.listen(context.Background(), &fakeMIDIInput{cmds: e.cmds})
+
+		if to := nav.next(e.at, true); to != e.to {
+			t.Errorf("Expected to continue at frame %d at index %d, got %d", e.to, i, to)
+		}
+	}
+}
+
+func TestMIDIControls_Tap(t *testing.T) {
+	config := MIDIControlsConfig{
+		Tap:         &MIDITrigger{Status: 0x90, Data1: 63},
+		DownbeatTap: &MIDITrigger{Status: 0x90, Data1: 64},
+	}
+
+	s, commands := navigationSong(t)
+	nav := NewNavigation(s, commands)
+	nav.setFrame(150)
+
+	clock := &fakeClock{}
+	c := &midiControls{logger: logrus.New(), config: config, navigation: func() *Navigation { return nav }, clock: clock}
+
+	tap := cntl.MIDICommand{Status: 0x90, Data1: 63, Data2: 100}
+	downbeat := cntl.MIDICommand{Status: 0x90, Data1: 64, Data2: 100}
+	for _, cmd := range []cntl.MIDICommand{tap, tap, tap, downbeat} {
+		if err := c.handle(cmd); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		<-clock.After(250 * time.Millisecond)
+	}
+
+	if to := nav.next(151, false); to != 192 {
+		t.Errorf("Expected the downbeat to continue at frame 192, got %d", to)
+	}
+
+	tp := tempo{speed: 6000}
+	nav.adjustTempo(&tp)
+	if speed := nav.Position().Speed; speed != 240 {
+		t.Errorf("Expected to get the tapped speed 240, got %v", speed)
+	}
+}
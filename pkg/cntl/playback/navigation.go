@@ -0,0 +1,359 @@
+package playback
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+)
+
+// Navigation changes the position within a song while it is played.
+// Jumps and loops are applied at the next bar boundary so the band can follow, seeks are applied immediately.
+type Navigation struct {
+	mu       sync.Mutex
+	commands []cntl.Command
+	sections []cntl.SongSection
+	markers  map[string]uint64
+	end      uint64
+
+	frame  uint64
+	jump   *uint64
+	seek   *uint64
+	loop   *sectionRange
+	paused bool
+
+	factor, nudge, speed float64
+
+	// taps is the tempo tapped by the operator, which is applied at the next bar start once tapDue is set
+	taps   tapTempo
+	tapped float64
+	tapDue bool
+}
+
+type sectionRange struct {
+	start, end uint64
+}
+
+// Position describes where the playback of a song currently is
+type Position struct {
+	Frame  uint64  `json:"frame"`
+	Bar    uint16  `json:"bar"`
+	Note   uint8   `json:"note"`
+	Paused bool    `json:"paused"`
+	Speed  float64 `json:"speed"`
+}
+
+// NewNavigation returns a Navigation for the given song with resolved positions and its rendered commands
+func NewNavigation(s *cntl.Song, commands []cntl.Command) *Navigation {
+	n := &Navigation{
+		commands: commands,
+		sections: append([]cntl.SongSection{}, s.Sections...),
+		markers:  make(map[string]uint64),
+		end:      uint64(len(commands)),
+	}
+
+	sort.SliceStable(n.sections, func(i, j int) bool { return n.sections[i].At < n.sections[j].At })
+
+	for _, sp := range s.DMXScenes {
+		if sp.Marker != "" {
+			n.markers[sp.Marker] = sp.At
+		}
+	}
+
+	// sections win over scene markers of the same name
+	for _, sec := range n.sections {
+		n.markers[sec.Name] = sec.At
+	}
+
+	return n
+}
+
+// JumpTo jumps to the section or scene marker with the given name
+func (n *Navigation) JumpTo(marker string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	at, ok := n.markers[marker]
+	if !ok {
+		return fmt.Errorf("cannot find marker %q", marker)
+	}
+
+	n.jump = &at
+	return nil
+}
+
+// Skip jumps to the start of the section after the current one
+func (n *Navigation) Skip() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for _, sec := range n.sections {
+		if sec.At > n.frame {
+			at := sec.At
+			n.jump = &at
+			return nil
+		}
+	}
+
+	return fmt.Errorf("there is no section after frame %d", n.frame)
+}
+
+// Loop repeats the section with the given name, or the current one if no name is given, until it is released
+func (n *Navigation) Loop(section string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for i, sec := range n.sections {
+		next := n.end
+		if i+1 < len(n.sections) {
+			next = n.sections[i+1].At
+		}
+
+		current := sec.At <= n.frame && n.frame < next
+		if sec.Name == section || (section == "" && current) {
+			n.loop = &sectionRange{start: sec.At, end: next}
+			return nil
+		}
+	}
+
+	if section == "" {
+		return fmt.Errorf("there is no section at frame %d", n.frame)
+	}
+
+	return fmt.Errorf("cannot find section %q", section)
+}
+
+// Release stops looping, the song continues after the looped section
+func (n *Navigation) Release() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.loop = nil
+}
+
+// Pause holds the playback at the current frame until it is resumed
+func (n *Navigation) Pause() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.paused = true
+}
+
+// Resume continues a paused playback
+func (n *Navigation) Resume() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.paused = false
+}
+
+// Seek continues the playback immediately at the start of the given bar
+func (n *Navigation) Seek(bar uint16) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	at, err := n.barFrame(bar)
+	if err != nil {
+		return err
+	}
+
+	n.seek = &at
+	return nil
+}
+
+// seekFrame continues the playback immediately at the given frame
+func (n *Navigation) seekFrame(frame uint64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.seek = &frame
+}
+
+// barFrame returns the first frame of the given bar
+func (n *Navigation) barFrame(bar uint16) (uint64, error) {
+	for i, cmd := range n.commands {
+		if cmd.Bar == bar {
+			return uint64(i), nil
+		}
+	}
+
+	return 0, fmt.Errorf("cannot find bar %d", bar)
+}
+
+// startFrame returns the frame a song is started at with the given options
+func (n *Navigation) startFrame(opts StartOptions) (uint64, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	switch {
+	case opts.Bar != 0 && opts.Marker != "":
+		return 0, ErrStartBarAndMarkerGiven
+
+	case opts.Bar != 0:
+		return n.barFrame(opts.Bar)
+
+	case opts.Marker != "":
+		at, ok := n.markers[opts.Marker]
+		if !ok {
+			return 0, fmt.Errorf("cannot find marker %q", opts.Marker)
+		}
+		return at, nil
+	}
+
+	return 0, nil
+}
+
+// Position returns the position that is currently played
+func (n *Navigation) Position() Position {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	pos := Position{Frame: n.frame, Paused: n.paused, Speed: n.speed}
+	if n.frame < uint64(len(n.commands)) {
+		pos.Bar = n.commands[n.frame].Bar
+		pos.Note = n.commands[n.frame].Note
+	}
+
+	return pos
+}
+
+// Nudge changes the speed of the whole song by the given BPM, e.g. to follow the band
+func (n *Navigation) Nudge(bpm float64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.nudge += bpm
+}
+
+// Tap adds a tap of the tempo at the given time and returns the averaged tempo in BPM, or 0 on the first tap.
+// The song is played at that tempo from the next bar on. A downbeat tap marks the start of a bar, so the playback
+// continues immediately at the bar start nearest to the tap.
+func (n *Navigation) Tap(at time.Time, downbeat bool) float64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	bpm := n.taps.tap(at)
+	if bpm > 0 {
+		n.tapped = bpm
+	}
+
+	if downbeat {
+		if frame := n.nearestBarStart(n.frame + 1); frame != n.frame+1 {
+			n.seek = &frame
+		}
+	}
+
+	return bpm
+}
+
+// nearestBarStart returns the first frame of the bar the given frame is in, or of the next one if it is nearer
+func (n *Navigation) nearestBarStart(frame uint64) uint64 {
+	if frame >= n.end {
+		return frame
+	}
+
+	bar := n.commands[frame].Bar
+	start := frame
+	for start > 0 && n.commands[start-1].Bar == bar {
+		start--
+	}
+
+	next := frame
+	for next < n.end && n.commands[next].Bar == bar {
+		next++
+	}
+
+	if next < n.end && next-frame < frame-start {
+		return next
+	}
+
+	return start
+}
+
+func (n *Navigation) setTempoFactor(factor float64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.factor = factor
+}
+
+// adjustTempo applies the tempo factor and nudge to the given tempo and remembers the resulting speed
+func (n *Navigation) adjustTempo(tp *tempo) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.tapDue && tp.speed > 0 {
+		// the tapped tempo replaces all other adjustments, the following bar changes keep their relation.
+		// The beats of the current bar are tapped, so they are converted to the quarter notes of the speed
+		n.factor = tp.quarterBPM(n.tapped) / tp.speed
+		n.nudge = 0
+		n.tapped, n.tapDue = 0, false
+	}
+
+	tp.factor = n.factor
+	tp.nudge = n.nudge
+	n.speed = tp.bpm()
+}
+
+func (n *Navigation) isPaused() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.paused
+}
+
+// pausedSeek returns the frame of a seek requested while paused, which is applied without waiting for the resume
+func (n *Navigation) pausedSeek() (uint64, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if !n.paused || n.seek == nil {
+		return 0, false
+	}
+
+	frame := *n.seek
+	n.seek = nil
+	n.tapDue = n.tapped > 0
+	n.frame = frame
+	return frame, true
+}
+
+// next is called before every frame and returns the frame to continue with.
+// Jumps and loops are only applied at a bar boundary, or when the song ended.
+func (n *Navigation) next(frame uint64, barStart bool) uint64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.seek != nil {
+		frame = *n.seek
+		n.seek = nil
+		n.tapDue = n.tapped > 0
+		return frame
+	}
+
+	if !barStart {
+		return frame
+	}
+
+	n.tapDue = n.tapped > 0
+
+	switch {
+	case n.jump != nil:
+		frame = *n.jump
+		n.jump = nil
+
+	case n.loop != nil && frame == n.loop.end:
+		frame = n.loop.start
+	}
+
+	return frame
+}
+
+func (n *Navigation) setFrame(frame uint64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.frame = frame
+}
@@ -0,0 +1,434 @@
+package playback
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/midi"
+	"github.com/StageAutoControl/controller/pkg/cntl/song"
+	"github.com/sirupsen/logrus"
+)
+
+type recordingWriter struct {
+	mu     sync.Mutex
+	frames map[uint64]int
+	onEach func(cmd cntl.Command, count int)
+}
+
+func (w *recordingWriter) Write(cmd cntl.Command) error {
+	w.mu.Lock()
+	w.frames[cmd.Frame]++
+	count := w.frames[cmd.Frame]
+	w.mu.Unlock()
+
+	if w.onEach != nil {
+		w.onEach(cmd, count)
+	}
+
+	return nil
+}
+
+// navigationSong returns three rendered bars of a fast 4/4 song with a section at every bar
+func navigationSong(t testing.TB) (*cntl.Song, []cntl.Command) {
+	return renderSong(t, &cntl.Song{
+		ID:         "navigation",
+		BarChanges: []cntl.BarChange{{At: 0, BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 6000}}},
+		Sections: []cntl.SongSection{
+			{Name: "verse", At: 0},
+			{Name: "chorus", At: 192},
+			{Name: "outro", At: 384},
+		},
+		DMXScenes:    []cntl.DMXScenePosition{{ID: "solo", At: 192, Marker: "solo"}},
+		MIDICommands: []cntl.MIDICommand{{At: 575, Status: 0xB0}},
+	})
+}
+
+// signatureSong returns a rendered song with two bars of 4/4 followed by three bars of 7/8
+func signatureSong(t testing.TB) (*cntl.Song, []cntl.Command) {
+	return renderSong(t, &cntl.Song{
+		ID: "signature",
+		BarChanges: []cntl.BarChange{
+			{At: 0, BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 6000}},
+			{Position: "3.1", BarParams: cntl.BarParams{NoteCount: 7, NoteValue: 8}},
+		},
+		Sections: []cntl.SongSection{
+			{Name: "intro", Position: "1.1"},
+			{Name: "bridge", Position: "4.1"},
+		},
+		MIDICommands: []cntl.MIDICommand{{Position: "5.7.23", Status: 0xB0}},
+	})
+}
+
+// renderSong renders the given song like the player does and returns it with resolved positions and its commands
+func renderSong(t testing.TB, s *cntl.Song) (*cntl.Song, []cntl.Command) {
+	t.Helper()
+
+	ds := cntl.NewStore()
+	ds.Songs[s.ID] = s
+	ds.DMXScenes["solo"] = &cntl.DMXScene{ID: "solo", NoteValue: 4, NoteCount: 4}
+
+	commands, err := song.Render(ds, s.ID)
+	if err != nil {
+		t.Fatalf("failed to render song: %v", err)
+	}
+
+	resolved, err := song.ResolvePositions(s)
+	if err != nil {
+		t.Fatalf("failed to resolve positions: %v", err)
+	}
+
+	return resolved, commands
+}
+
+func TestNavigation_JumpTo(t *testing.T) {
+	s, commands := navigationSong(t)
+	nav := NewNavigation(s, commands)
+	w := &recordingWriter{frames: make(map[uint64]int)}
+
+	if err := nav.JumpTo("outro"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := nav.JumpTo("unknown"); err == nil {
+		t.Error("Expected to get an error for an unknown marker")
+	}
+
+	if err := play(context.Background(), logrus.New(), []TransportWriter{w}, commands, nav); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for frame := uint64(0); frame < uint64(len(commands)); frame++ {
+		played := frame < 192 || frame >= 384
+		if (w.frames[frame] == 1) != played {
+			t.Errorf("Expected frame %d to be played: %v, got %d times", frame, played, w.frames[frame])
+		}
+	}
+}
+
+func TestNavigation_LoopAndRelease(t *testing.T) {
+	s, commands := navigationSong(t)
+	nav := NewNavigation(s, commands)
+
+	w := &recordingWriter{frames: make(map[uint64]int)}
+	w.onEach = func(cmd cntl.Command, count int) {
+		if cmd.Frame == 192 && count == 2 {
+			nav.Release()
+		}
+	}
+
+	if err := nav.Loop("chorus"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := play(context.Background(), logrus.New(), []TransportWriter{w}, commands, nav); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	exp := map[uint64]int{0: 1, 191: 1, 192: 2, 383: 2, 384: 1, 575: 1}
+	for frame, count := range exp {
+		if w.frames[frame] != count {
+			t.Errorf("Expected frame %d to be played %d times, got %d", frame, count, w.frames[frame])
+		}
+	}
+}
+
+func TestNavigation_Skip(t *testing.T) {
+	s, commands := navigationSong(t)
+	nav := NewNavigation(s, commands)
+
+	exp := []struct {
+		frame uint64
+		to    uint64
+		err   bool
+	}{
+		{frame: 0, to: 192},
+		{frame: 200, to: 384},
+		{frame: 400, err: true},
+	}
+
+	for i, e := range exp {
+		nav.setFrame(e.frame)
+		err := nav.Skip()
+		if e.err {
+			if err == nil {
+				t.Errorf("Expected to get an error at index %d", i)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("Unexpected error at index %d: %v", i, err)
+		}
+		if to := nav.next(e.frame, true); to != e.to {
+			t.Errorf("Expected to skip to frame %d at index %d, got %d", e.to, i, to)
+		}
+	}
+}
+
+func TestNavigation_PauseAndSeek(t *testing.T) {
+	s, commands := signatureSong(t)
+	ch1 := cntl.DMXCommand{Universe: 1, Channel: 1, Value: cntl.DMXValue{Value: 255}}
+	ch2 := cntl.DMXCommand{Universe: 1, Channel: 2, Value: cntl.DMXValue{Value: 127}}
+	commands[0].DMXCommands = cntl.DMXCommands{ch1}
+	commands[100].DMXCommands = cntl.DMXCommands{ch2}
+
+	nav := NewNavigation(s, commands)
+	if err := nav.Seek(9); err == nil {
+		t.Error("Expected to get an error when seeking to an unknown bar")
+	}
+
+	var mu sync.Mutex
+	var seeked cntl.Command
+	var seekedPaused bool
+	seekedCh := make(chan struct{})
+
+	w := &recordingWriter{frames: make(map[uint64]int)}
+	w.onEach = func(cmd cntl.Command, count int) {
+		switch {
+		case cmd.Frame == 150:
+			nav.Pause()
+			go func() {
+				time.Sleep(50 * time.Millisecond)
+				if !nav.Position().Paused {
+					t.Error("Expected the navigation to be paused")
+				}
+				// bar 4 is the second bar in 7/8
+				if err := nav.Seek(4); err != nil {
+					t.Error(err)
+				}
+
+				select {
+				case <-seekedCh:
+				case <-time.After(time.Second):
+					t.Error("Expected the seek to be applied while paused")
+				}
+				nav.Resume()
+			}()
+
+		case cmd.Frame == 552 && count == 1:
+			mu.Lock()
+			seeked, seekedPaused = cmd, nav.Position().Paused
+			mu.Unlock()
+			close(seekedCh)
+		}
+	}
+
+	if err := play(context.Background(), logrus.New(), []TransportWriter{w}, commands, nav); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.frames[200] != 0 || w.frames[551] != 0 || w.frames[552] != 2 || w.frames[887] != 1 {
+		t.Errorf("Expected playback to continue at bar 4 after pause, got %+v", w.frames)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !seekedPaused {
+		t.Error("Expected the channel state to be written while paused")
+	}
+	if seeked.Bar != 4 || seeked.Note != 1 {
+		t.Errorf("Expected the channel state to be written at bar 4, got %d.%d", seeked.Bar, seeked.Note)
+	}
+	if !seeked.DMXCommands.Contains(ch1) || !seeked.DMXCommands.Contains(ch2) {
+		t.Errorf("Expected the channel state to be replayed after seeking, got %+v", seeked.DMXCommands)
+	}
+}
+
+// clockWriter records the transport messages of a MIDI clock like the MIDI transport sends them
+type clockWriter struct {
+	mu     sync.Mutex
+	clock  *midi.Clock
+	msgs   []cntl.MIDICommand
+	onEach func(cmd cntl.Command)
+}
+
+func (w *clockWriter) Write(cmd cntl.Command) error {
+	w.mu.Lock()
+	for _, m := range w.clock.Messages(cmd) {
+		if m.Status != midi.StatusClockTick {
+			w.msgs = append(w.msgs, m)
+		}
+	}
+	w.mu.Unlock()
+
+	if w.onEach != nil {
+		w.onEach(cmd)
+	}
+
+	return nil
+}
+
+func (w *clockWriter) Stop() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.msgs = append(w.msgs, w.clock.Stop()...)
+	return nil
+}
+
+func TestNavigation_PauseAndSeek_MIDIClock(t *testing.T) {
+	s, commands := navigationSong(t)
+	nav := NewNavigation(s, commands)
+
+	w := &clockWriter{clock: midi.NewClock(true)}
+	w.onEach = func(cmd cntl.Command) {
+		if cmd.Frame == 100 && !cmd.Paused {
+			nav.Pause()
+			go func() {
+				time.Sleep(20 * time.Millisecond)
+				if err := nav.Seek(3); err != nil {
+					t.Error(err)
+				}
+				time.Sleep(20 * time.Millisecond)
+				nav.Resume()
+			}()
+		}
+	}
+
+	if err := play(context.Background(), logrus.New(), []TransportWriter{w}, commands, nav); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// bar 3 starts at frame 384, which is the 32nd sixteenth note
+	exp := []cntl.MIDICommand{
+		{Status: midi.StatusStart},
+		{Status: midi.StatusStop},
+		{Status: midi.StatusSongPositionPointer, Data1: 32},
+		{Status: midi.StatusContinue},
+		{Status: midi.StatusStop},
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.msgs) != len(exp) {
+		t.Fatalf("Expected to get messages %+v, got %+v", exp, w.msgs)
+	}
+
+	for i, e := range exp {
+		if w.msgs[i] != e {
+			t.Errorf("Expected to get message %+v at index %d, got %+v", e, i, w.msgs[i])
+		}
+	}
+}
+
+func TestNavigation_Nudge(t *testing.T) {
+	s, commands := navigationSong(t)
+	nav := NewNavigation(s, commands)
+	nav.setTempoFactor(0.5)
+	nav.Nudge(10)
+	nav.Nudge(-4)
+
+	if err := play(context.Background(), logrus.New(), []TransportWriter{}, commands, nav); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if speed := nav.Position().Speed; speed != 3006 {
+		t.Errorf("Expected to get speed 3006, got %v", speed)
+	}
+}
+
+func TestNavigation_Tap(t *testing.T) {
+	start := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	exp := []struct {
+		frame    uint64
+		downbeat bool
+		to       uint64
+	}{
+		{frame: 100, to: 101},
+		{frame: 100, downbeat: true, to: 192},
+		{frame: 50, downbeat: true, to: 0},
+		{frame: 191, downbeat: true, to: 192},
+		{frame: 500, downbeat: true, to: 384},
+	}
+
+	for i, e := range exp {
+		s, commands := navigationSong(t)
+		nav := NewNavigation(s, commands)
+		nav.setFrame(e.frame)
+
+		for tap := 0; tap < 4; tap++ {
+			nav.Tap(start.Add(time.Duration(tap)*100*time.Millisecond), e.downbeat && tap == 3)
+		}
+
+		to := nav.next(e.frame+1, e.frame+1 == 192)
+		if to != e.to {
+			t.Errorf("Expected to continue at frame %d at index %d, got %d", e.to, i, to)
+		}
+
+		// without a downbeat the tempo is changed at the next bar
+		tp := tempo{speed: 6000}
+		if !e.downbeat {
+			nav.adjustTempo(&tp)
+			if speed := nav.Position().Speed; speed != 6000 {
+				t.Errorf("Expected to keep speed 6000 within the bar at index %d, got %v", i, speed)
+			}
+			nav.next(192, true)
+		}
+
+		nav.adjustTempo(&tp)
+		if speed := nav.Position().Speed; speed != 600 {
+			t.Errorf("Expected to get the tapped speed 600 at index %d, got %v", i, speed)
+		}
+	}
+}
+
+func TestNavigation_Tap_NoteValue(t *testing.T) {
+	start := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	s, commands := signatureSong(t)
+	nav := NewNavigation(s, commands)
+
+	// bar 3 is the first bar in 7/8, its eighths are tapped at 600 BPM
+	nav.setFrame(400)
+	for tap := 0; tap < 4; tap++ {
+		nav.Tap(start.Add(time.Duration(tap)*100*time.Millisecond), false)
+	}
+	nav.next(552, true)
+
+	tp := tempo{speed: 6000}
+	if err := tp.setBarChange(commands[384].BarChange); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	nav.adjustTempo(&tp)
+	if speed := nav.Position().Speed; speed != 300 {
+		t.Errorf("Expected tapped eighths at 600 BPM to give speed 300, got %v", speed)
+	}
+}
+
+func TestNavigation_NearestBarStart(t *testing.T) {
+	s, commands := signatureSong(t)
+	nav := NewNavigation(s, commands)
+
+	// bars 3 to 5 are in 7/8 and 168 frames long
+	exp := []struct {
+		frame uint64
+		start uint64
+	}{
+		{0, 0},
+		{95, 0},
+		{96, 0},
+		{97, 192},
+		{383, 384},
+		{400, 384},
+		{467, 384},
+		{469, 552},
+		{700, 720},
+		{880, 720},
+		{888, 888},
+	}
+
+	for i, e := range exp {
+		if start := nav.nearestBarStart(e.frame); start != e.start {
+			t.Errorf("Expected to get bar start %d for frame %d at index %d, got %d", e.start, e.frame, i, start)
+		}
+	}
+}
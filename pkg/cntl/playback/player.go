@@ -3,19 +3,29 @@ package playback
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/dmx"
 	"github.com/StageAutoControl/controller/pkg/cntl/song"
 	"github.com/StageAutoControl/controller/pkg/internal/logging"
 )
 
 // Player plays various things from a given data store, for example songs or a whole SetList.
 type Player struct {
+	mu        sync.Mutex
+	nav       *Navigation
 	logger    logging.Logger
 	dataStore *cntl.DataStore
 	writers   []TransportWriter
 	waiters   []Waiter
+	clock     Clock
+	source    frameSource
+
+	// next releases the waiters before the next song, skip ends the song currently played
+	next chan struct{}
+	skip context.CancelFunc
 }
 
 // NewPlayer returns a new Player instance
@@ -25,9 +35,32 @@ func NewPlayer(logger logging.Logger, ds *cntl.DataStore, writers []TransportWri
 		dataStore: ds,
 		writers:   writers,
 		waiters:   waiters,
+		clock:     realClock{},
+		next:      make(chan struct{}, 1),
 	}
 }
 
+// SetClock sets the clock songs are played with, e.g. to play in virtual time
+func (p *Player) SetClock(c Clock) {
+	p.clock = c
+}
+
+// FollowMIDIClock plays songs following the MIDI clock of the given input instead of their own tempo,
+// until the given context is done
+func (p *Player) FollowMIDIClock(ctx context.Context, in MIDIInput) {
+	p.source = newMIDIClockFollower(p.clock, in.Listen(ctx))
+}
+
+// ChaseTimecode plays songs at the position of the timecode of the given input, until the given context is done.
+// Songs need to be positioned in timecode to be played.
+func (p *Player) ChaseTimecode(ctx context.Context, in TimecodeInput) {
+	p.source = newTimecodeChaser(p.clock, in.Listen(ctx))
+}
+
+func (p *Player) scheduler() *scheduler {
+	return &scheduler{logger: p.logger, clock: p.clock, writers: p.writers, source: p.source}
+}
+
 func (p *Player) checkSetList(setList *cntl.SetList) error {
 	for _, songID := range setList.Songs {
 		if _, ok := p.dataStore.Songs[songID]; !ok {
@@ -49,6 +82,7 @@ func (p *Player) PlaySetList(ctx context.Context, setListID string) error {
 		return err
 	}
 
+	ds := dmx.OverrideColorVariables(p.dataStore, setList.ColorVariables)
 	for _, songID := range setList.Songs {
 		select {
 		case <-ctx.Done():
@@ -57,7 +91,7 @@ func (p *Player) PlaySetList(ctx context.Context, setListID string) error {
 		default:
 		}
 
-		if err := p.PlaySong(ctx, songID); err != nil {
+		if err := p.playSong(ctx, ds, songID, StartOptions{}); err != nil {
 			return err
 		}
 	}
@@ -91,72 +125,164 @@ func (p *Player) wait(ctx context.Context) error {
 		return ErrCancelled
 	case <-done:
 		return nil
+	case <-p.next:
+		p.logger.Info("Next song requested")
+		return nil
+	}
+}
+
+// Next ends the song currently played and starts the next one of the set list without waiting for the waiters
+func (p *Player) Next() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.skip != nil {
+		p.skip()
+	}
+
+	select {
+	case p.next <- struct{}{}:
+	default:
 	}
 }
 
 // PlaySong plays a full song
 func (p *Player) PlaySong(ctx context.Context, songID string) error {
-	commands, err := song.Render(p.dataStore, songID)
+	return p.playSong(ctx, p.dataStore, songID, StartOptions{})
+}
+
+// PlaySongFrom plays a song with the given start options, e.g. at a given bar or with an adjusted tempo
+func (p *Player) PlaySongFrom(ctx context.Context, songID string, opts StartOptions) error {
+	return p.playSong(ctx, p.dataStore, songID, opts)
+}
+
+func (p *Player) playSong(ctx context.Context, ds *cntl.DataStore, songID string, opts StartOptions) error {
+	commands, err := song.Render(ds, songID)
 	if err != nil {
 		return err
 	}
 
-	s, ok := p.dataStore.Songs[songID]
+	s, ok := ds.Songs[songID]
 	if !ok {
 		return fmt.Errorf("failed to find song %v", songID)
 	}
 
-	p.logger.Infof("Playing song %v", s.Name)
+	resolved, err := song.ResolvePositions(s)
+	if err != nil {
+		return err
+	}
+
+	nav := NewNavigation(resolved, commands)
+	start, err := nav.startFrame(opts)
+	if err != nil {
+		return err
+	}
+
+	factor, err := opts.Tempo.factor(commands)
+	if err != nil {
+		return err
+	}
+	nav.setTempoFactor(factor)
+
+	if c, ok := p.source.(*timecodeChaser); ok {
+		if start > 0 || opts.CountIn > 0 {
+			return ErrStartOptionsWhileChasing
+		}
+
+		if err := c.load(resolved, commands, factor); err != nil {
+			return err
+		}
+	}
+
+	var countInCommands []cntl.Command
+	if opts.CountIn > 0 {
+		if countInCommands, err = CountIn(commands, start, opts); err != nil {
+			return err
+		}
+	}
 
 	p.logger.Infof("Waiting for waiters before playing song %v", s.Name)
 	if err := p.wait(ctx); err != nil {
 		return err
 	}
 
+	songCtx, skip := context.WithCancel(ctx)
+	defer skip()
+
+	p.setSkip(skip)
+	defer p.setSkip(nil)
+
+	sch := p.scheduler()
+	if len(countInCommands) > 0 {
+		p.logger.Infof("Counting in %d bars before playing song %v", opts.CountIn, s.Name)
+		sch.continued = true
+		if err := sch.play(songCtx, countInCommands, nil); err != nil {
+			return p.skipped(ctx, err)
+		}
+		sch.continued = false
+	}
+
+	if start > 0 {
+		nav.seekFrame(start)
+	}
+
+	p.setNavigation(nav)
+	defer p.setNavigation(nil)
+
 	p.logger.Infof("Playing song %v", s.Name)
-	return Play(ctx, p.logger, p.writers, commands)
+	return p.skipped(ctx, sch.play(songCtx, commands, nav))
 }
 
-// CalcRenderSpeed calculates the render speed of a BarChange to a time.Duration
-func CalcRenderSpeed(bc *cntl.BarChange) time.Duration {
-	return time.Minute / time.Duration(bc.Speed*uint16(bc.NoteValue)/4) / time.Duration(cntl.RenderFrames/bc.NoteValue)
+// skipped returns nil instead of the given error if the song was ended by Next rather than by cancelling the given context
+func (p *Player) skipped(ctx context.Context, err error) error {
+	if err == ErrCancelled && ctx.Err() == nil {
+		p.logger.Info("Song skipped")
+		return nil
+	}
+
+	return err
 }
 
-// Play plays a given slice of commands and send it to the given writers
-func Play(ctx context.Context, logger logging.Logger, writers []TransportWriter, commands []cntl.Command) error {
-	l := len(commands)
-	t := time.NewTicker(1 * time.Nanosecond)
-	done := ctx.Done()
+func (p *Player) setSkip(skip context.CancelFunc) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-	var i int
-	var cmd cntl.Command
-	for {
-		select {
-		case <-done:
-			return ErrCancelled
+	p.skip = skip
+}
 
-		case <-t.C:
-			if i >= l {
-				t.Stop()
-				return nil
-			}
+// Navigation returns the navigation of the song currently played, or nil if none is played
+func (p *Player) Navigation() *Navigation {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-			cmd = commands[i]
-			if cmd.BarChange != nil {
-				t.Stop()
-				t = time.NewTicker(CalcRenderSpeed(cmd.BarChange))
-			}
+	return p.nav
+}
 
-			for _, w := range writers {
-				go func(w TransportWriter) {
-					if err := w.Write(cmd); err != nil {
-						logger.Error(err)
-					}
-				}(w)
-			}
+func (p *Player) setNavigation(nav *Navigation) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-			i++
-		}
+	p.nav = nav
+}
+
+// CalcRenderSpeed calculates the render speed of a BarChange to a time.Duration.
+// The speed is given in quarter notes per minute, so a bar of four quarters consists of RenderFrames frames.
+func CalcRenderSpeed(bc *cntl.BarChange) time.Duration {
+	return 4 * time.Minute / (time.Duration(bc.Speed) * time.Duration(cntl.RenderFrames))
+}
+
+// DefaultBarParams sets the speed and time signature that are not given to the ones entities are played once with
+func DefaultBarParams(bp *cntl.BarParams) {
+	if bp.Speed == 0 {
+		bp.Speed = defaultSpeed
+	}
+
+	if bp.NoteCount == 0 {
+		bp.NoteCount = defaultNoteCount
+	}
+
+	if bp.NoteValue == 0 {
+		bp.NoteValue = defaultNoteValue
 	}
 }
 
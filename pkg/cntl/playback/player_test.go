@@ -1,9 +1,13 @@
 package playback
 
 import (
+	"context"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/sirupsen/logrus"
+
 	"github.com/StageAutoControl/controller/pkg/cntl"
 )
 
@@ -12,13 +16,14 @@ func TestCalcRenderSpeed(t *testing.T) {
 		bc    cntl.BarChange
 		speed time.Duration
 	}{
-		{cntl.BarChange{BarParams: cntl.BarParams{Speed: 120, NoteValue: 4}}, time.Minute / 1920},
-		{cntl.BarChange{BarParams: cntl.BarParams{Speed: 120, NoteValue: 8}}, time.Minute / 1920},
-		{cntl.BarChange{BarParams: cntl.BarParams{Speed: 120, NoteValue: 16}}, time.Minute / 1920},
-		{cntl.BarChange{BarParams: cntl.BarParams{Speed: 120, NoteValue: 32}}, time.Minute / 1920},
+		{cntl.BarChange{BarParams: cntl.BarParams{Speed: 120, NoteValue: 4}}, time.Minute / 5760},
+		{cntl.BarChange{BarParams: cntl.BarParams{Speed: 120, NoteValue: 8}}, time.Minute / 5760},
+		{cntl.BarChange{BarParams: cntl.BarParams{Speed: 120, NoteValue: 16}}, time.Minute / 5760},
+		{cntl.BarChange{BarParams: cntl.BarParams{Speed: 120, NoteValue: 32}}, time.Minute / 5760},
 
-		{cntl.BarChange{BarParams: cntl.BarParams{Speed: 60, NoteValue: 4}}, time.Minute / 960},
-		{cntl.BarChange{BarParams: cntl.BarParams{Speed: 60, NoteValue: 8}}, time.Minute / 960},
+		{cntl.BarChange{BarParams: cntl.BarParams{Speed: 60, NoteValue: 4}}, time.Minute / 2880},
+		{cntl.BarChange{BarParams: cntl.BarParams{Speed: 60, NoteValue: 8}}, time.Minute / 2880},
+		{cntl.BarChange{BarParams: cntl.BarParams{Speed: 60, NoteValue: 12}}, time.Minute / 2880},
 	}
 
 	for i, e := range exp {
@@ -28,3 +33,97 @@ func TestCalcRenderSpeed(t *testing.T) {
 		}
 	}
 }
+
+func TestDefaultBarParams(t *testing.T) {
+	exp := []struct {
+		bp, exp cntl.BarParams
+	}{
+		{cntl.BarParams{}, cntl.BarParams{Speed: 140, NoteCount: 4, NoteValue: 4}},
+		{cntl.BarParams{Speed: 90}, cntl.BarParams{Speed: 90, NoteCount: 4, NoteValue: 4}},
+		{cntl.BarParams{NoteCount: 7, NoteValue: 8}, cntl.BarParams{Speed: 140, NoteCount: 7, NoteValue: 8}},
+	}
+
+	for i, e := range exp {
+		DefaultBarParams(&e.bp)
+		if e.bp != e.exp {
+			t.Errorf("Expected to get bar params %+v at index %d, got %+v", e.exp, i, e.bp)
+		}
+	}
+}
+
+// blockingWaiter never fires, so only Next starts a song
+type blockingWaiter struct{}
+
+func (blockingWaiter) Wait(done chan struct{}, cancel chan struct{}) error {
+	<-cancel
+	return nil
+}
+
+type songWriter struct {
+	mu     sync.Mutex
+	song   uint8
+	frames map[uint8]int
+	onEach func(song uint8, frames int)
+}
+
+// Write counts the frames of every song, identified by the MIDI command at its first frame
+func (w *songWriter) Write(cmd cntl.Command) error {
+	w.mu.Lock()
+	for _, mc := range cmd.MIDICommands {
+		if mc.Data1 > 0 {
+			w.song = mc.Data1
+		}
+	}
+	w.frames[w.song]++
+	song, frames := w.song, w.frames[w.song]
+	w.mu.Unlock()
+
+	if w.onEach != nil {
+		w.onEach(song, frames)
+	}
+
+	return nil
+}
+
+func TestPlayer_Next(t *testing.T) {
+	ds := cntl.NewStore()
+	ds.SetLists["set"] = &cntl.SetList{ID: "set", Songs: []string{"first", "second"}}
+	for i, id := range ds.SetLists["set"].Songs {
+		ds.Songs[id] = &cntl.Song{
+			ID:         id,
+			Name:       id,
+			BarChanges: []cntl.BarChange{{At: 0, BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 120}}},
+			MIDICommands: []cntl.MIDICommand{
+				{At: 0, Status: 0xB0, Data1: uint8(i + 1)},
+				{At: 1535, Status: 0xB0},
+			},
+		}
+	}
+
+	// the songs are longer than the queue of the writer, so the first one is still played when it is skipped
+	w := &songWriter{frames: make(map[uint8]int)}
+	p := NewPlayer(logrus.NewEntry(logrus.New()), ds, []TransportWriter{w}, []Waiter{blockingWaiter{}})
+	p.SetClock(&fakeClock{})
+	w.onEach = func(song uint8, frames int) {
+		if song == 1 && frames == 10 {
+			p.Next()
+		}
+	}
+
+	// the first song is waited for as well
+	p.Next()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := p.PlaySetList(ctx, "set"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if w.frames[1] >= 1536 {
+		t.Errorf("Expected the first song to be skipped, but %d frames were played", w.frames[1])
+	}
+	if w.frames[2] != 1536 {
+		t.Errorf("Expected to play all 1536 frames of the second song, got %d", w.frames[2])
+	}
+}
@@ -3,6 +3,8 @@ package playback
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/StageAutoControl/controller/pkg/artnet"
 	"github.com/StageAutoControl/controller/pkg/cntl/transport"
@@ -18,9 +20,14 @@ type Process struct {
 	storage    storage
 	params     Params
 	controller artnet.Controller
-	player     *Player
-	cancel     context.CancelFunc
 	visualizer *visualizer.Server
+	inputUser  MIDIInputUser
+
+	// mu guards the player, cancel func and opened MIDI inputs, which are set by Start and read while the song is played
+	mu     sync.Mutex
+	player *Player
+	cancel context.CancelFunc
+	inputs []int8
 }
 
 // NewProcess returns a new playback process instance
@@ -43,6 +50,19 @@ func (p *Process) GetParams() Params {
 	return p.params
 }
 
+// SetMIDIInputUser sets the process whose MIDI inputs the playback must not open as well, e.g. the live MIDI mappings
+func (p *Process) SetMIDIInputUser(u MIDIInputUser) {
+	p.inputUser = u
+}
+
+// MIDIInputs returns the MIDI input devices the playback opened while it is running
+func (p *Process) MIDIInputs() []int8 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]int8{}, p.inputs...)
+}
+
 // SetLogger sets the logger for the process
 func (p *Process) SetLogger(logger logging.Logger) {
 	p.logger = logger
@@ -60,19 +80,58 @@ func (p *Process) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to find playback config: %v", err)
 	}
 
+	if err := validateSync(config); err != nil {
+		return err
+	}
+
+	var inUse []int8
+	if p.inputUser != nil {
+		inUse = p.inputUser.MIDIInputs()
+	}
+	if err := validateMIDIInputs(config, inUse); err != nil {
+		return err
+	}
+
 	cfg, err := p.parseConfig(config)
 	if err != nil {
 		return err
 	}
-	p.player = NewPlayer(p.logger, ds, cfg.writers, cfg.waiters)
-	ctx, p.cancel = context.WithCancel(ctx)
+	player := NewPlayer(p.logger, ds, cfg.writers, cfg.waiters)
+	ctx, cancel := context.WithCancel(ctx)
+
+	p.mu.Lock()
+	p.player, p.cancel = player, cancel
+	for _, in := range midiInputs(config) {
+		p.inputs = append(p.inputs, in.deviceID)
+	}
+	p.mu.Unlock()
+
+	if cfg.midiClockInput != nil {
+		player.FollowMIDIClock(ctx, cfg.midiClockInput)
+	}
+
+	if cfg.mtcInput != nil {
+		player.ChaseTimecode(ctx, cfg.mtcInput)
+	}
+
+	if cfg.ltcInput != nil {
+		player.ChaseTimecode(ctx, cfg.ltcInput)
+	}
+
+	if cfg.midiInput != nil {
+		controlsCtx, cancelControls := context.WithCancel(ctx)
+		defer cancelControls()
+
+		controls := &midiControls{logger: p.logger, config: config.Controls.MIDI, navigation: player.Navigation, clock: player.clock}
+		go controls.listen(controlsCtx, cfg.midiInput)
+	}
 
 	if p.params.SetList.ID != "" {
-		if err := p.player.PlaySetList(ctx, p.params.SetList.ID); err != nil && err != ErrCancelled {
+		if err := player.PlaySetList(ctx, p.params.SetList.ID); err != nil && err != ErrCancelled {
 			return fmt.Errorf("failed to start setlist playbaack: %v", err)
 		}
 	} else if p.params.Song.ID != "" {
-		if err := p.player.PlaySong(ctx, p.params.Song.ID); err != nil && err != ErrCancelled {
+		if err := player.PlaySongFrom(ctx, p.params.Song.ID, p.params.Song.Start); err != nil && err != ErrCancelled {
 			return fmt.Errorf("failed to start song playback: %v", err)
 		}
 	} else {
@@ -84,6 +143,68 @@ func (p *Process) Start(ctx context.Context) error {
 	return nil
 }
 
+// validateSync checks that at most one sync source drives the playback, as they would fight over the played frame
+func validateSync(config *Config) error {
+	var enabled []string
+	if config.Sync.MIDIClockInput.Enabled {
+		enabled = append(enabled, "midiClockInput")
+	}
+	if config.Sync.MTCInput.Enabled {
+		enabled = append(enabled, "mtcInput")
+	}
+	if config.Sync.LTCInput.Enabled {
+		enabled = append(enabled, "ltcInput")
+	}
+
+	if len(enabled) > 1 {
+		return fmt.Errorf("only one sync source can be enabled, got %s", strings.Join(enabled, " and "))
+	}
+
+	return nil
+}
+
+type midiInput struct {
+	name     string
+	deviceID int8
+}
+
+// midiInputs returns the MIDI input devices the given config opens, the MTC input is read from a MIDI device as well
+func midiInputs(config *Config) []midiInput {
+	var inputs []midiInput
+	if config.Controls.MIDI.Enabled {
+		inputs = append(inputs, midiInput{"controls", config.Controls.MIDI.InputDeviceID})
+	}
+	if config.Sync.MIDIClockInput.Enabled {
+		inputs = append(inputs, midiInput{"midiClockInput", config.Sync.MIDIClockInput.InputDeviceID})
+	}
+	if config.Sync.MTCInput.Enabled {
+		inputs = append(inputs, midiInput{"mtcInput", config.Sync.MTCInput.InputDeviceID})
+	}
+
+	return inputs
+}
+
+// validateMIDIInputs checks that no MIDI input device is opened twice, neither by the playback nor with the given
+// devices already used by another process. The device IDs are compared as configured, -1 being the default device.
+func validateMIDIInputs(config *Config, inUse []int8) error {
+	inputs := midiInputs(config)
+	for i, in := range inputs {
+		for _, other := range inputs[:i] {
+			if in.deviceID == other.deviceID {
+				return fmt.Errorf("%s and %s cannot both use midi input device %d", other.name, in.name, in.deviceID)
+			}
+		}
+
+		for _, id := range inUse {
+			if in.deviceID == id {
+				return fmt.Errorf("%s cannot use midi input device %d, it is already used by the live midi input", in.name, in.deviceID)
+			}
+		}
+	}
+
+	return nil
+}
+
 func (p *Process) parseConfig(config *Config) (*parsedConfig, error) {
 	cfg := &parsedConfig{
 		waiters: []Waiter{},
@@ -105,6 +226,10 @@ func (p *Process) parseConfig(config *Config) (*parsedConfig, error) {
 			return nil, fmt.Errorf("failed to create midi transport writer: %v", err)
 		}
 
+		if config.TransportWriters.MIDI.Clock {
+			mw.SendClock(config.TransportWriters.MIDI.Transport)
+		}
+
 		cfg.writers = append(cfg.writers, mw)
 	}
 
@@ -112,6 +237,42 @@ func (p *Process) parseConfig(config *Config) (*parsedConfig, error) {
 		cfg.writers = append(cfg.writers, p.visualizer)
 	}
 
+	if config.Controls.MIDI.Enabled {
+		in, err := transport.NewMIDIInput(p.logger, config.Controls.MIDI.InputDeviceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open midi controls input: %v", err)
+		}
+
+		cfg.midiInput = in
+	}
+
+	if config.Sync.MIDIClockInput.Enabled {
+		in, err := transport.NewMIDIInput(p.logger, config.Sync.MIDIClockInput.InputDeviceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open midi clock input: %v", err)
+		}
+
+		cfg.midiClockInput = in
+	}
+
+	if config.Sync.MTCInput.Enabled {
+		in, err := transport.NewMTCInput(p.logger, config.Sync.MTCInput.InputDeviceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open mtc input: %v", err)
+		}
+
+		cfg.mtcInput = in
+	}
+
+	if config.Sync.LTCInput.Enabled {
+		in, err := transport.OpenLTCInput(p.logger, config.Sync.LTCInput.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open ltc input: %v", err)
+		}
+
+		cfg.ltcInput = in
+	}
+
 	if config.Waiters.Audio.Enabled {
 		cfg.waiters = append(cfg.waiters, waiter.NewAudio(p.logger, config.Waiters.Audio.Threshold))
 	}
@@ -119,12 +280,46 @@ func (p *Process) parseConfig(config *Config) (*parsedConfig, error) {
 	return cfg, nil
 }
 
+// Navigation returns the navigation of the song that is currently played
+func (p *Process) Navigation() (*Navigation, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.player == nil {
+		return nil, ErrNotPlaying
+	}
+
+	nav := p.player.Navigation()
+	if nav == nil {
+		return nil, ErrNotPlaying
+	}
+
+	return nav, nil
+}
+
+// Next ends the song currently played and starts the next one of the set list without waiting for the waiters
+func (p *Process) Next() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.player == nil {
+		return ErrNotPlaying
+	}
+
+	p.player.Next()
+	return nil
+}
+
 // Stop the process, i.e. cancel the playback context
 func (p *Process) Stop() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	if p.cancel != nil {
 		p.cancel()
 	}
 	p.player = nil
+	p.inputs = nil
 
 	return nil
 }
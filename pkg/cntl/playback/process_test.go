@@ -0,0 +1,84 @@
+package playback
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateSync(t *testing.T) {
+	exp := []struct {
+		midiClock, mtc, ltc bool
+		names               []string
+	}{
+		{},
+		{midiClock: true},
+		{mtc: true},
+		{ltc: true},
+		{midiClock: true, mtc: true, names: []string{"midiClockInput", "mtcInput"}},
+		{mtc: true, ltc: true, names: []string{"mtcInput", "ltcInput"}},
+		{midiClock: true, ltc: true, names: []string{"midiClockInput", "ltcInput"}},
+		{midiClock: true, mtc: true, ltc: true, names: []string{"midiClockInput", "mtcInput", "ltcInput"}},
+	}
+
+	for i, e := range exp {
+		config := &Config{}
+		config.Sync.MIDIClockInput.Enabled = e.midiClock
+		config.Sync.MTCInput.Enabled = e.mtc
+		config.Sync.LTCInput.Enabled = e.ltc
+
+		err := validateSync(config)
+		if len(e.names) == 0 {
+			if err != nil {
+				t.Errorf("Expected to get no error at index %d, got %v", i, err)
+			}
+			continue
+		}
+
+		if err == nil {
+			t.Errorf("Expected to get an error at index %d", i)
+			continue
+		}
+
+		for _, name := range e.names {
+			if !strings.Contains(err.Error(), name) {
+				t.Errorf("Expected error at index %d to name %q, got %v", i, name, err)
+			}
+		}
+	}
+}
+
+func TestValidateMIDIInputs(t *testing.T) {
+	exp := []struct {
+		controls, midiClock, mtc int8
+		inUse                    []int8
+		err                      bool
+	}{
+		{controls: 1, midiClock: 2, mtc: 3},
+		{controls: 1, midiClock: 2, mtc: 3, inUse: []int8{4}},
+		{controls: 1, midiClock: 1, mtc: 3, err: true},
+		{controls: 1, midiClock: 2, mtc: 2, err: true},
+		{controls: -1, midiClock: 2, mtc: 3, inUse: []int8{-1}, err: true},
+		{controls: 1, midiClock: 2, mtc: 3, inUse: []int8{3}, err: true},
+	}
+
+	for i, e := range exp {
+		config := &Config{}
+		config.Controls.MIDI.Enabled = true
+		config.Controls.MIDI.InputDeviceID = e.controls
+		config.Sync.MIDIClockInput.Enabled = true
+		config.Sync.MIDIClockInput.InputDeviceID = e.midiClock
+		config.Sync.MTCInput.Enabled = true
+		config.Sync.MTCInput.InputDeviceID = e.mtc
+
+		if err := validateMIDIInputs(config, e.inUse); (err != nil) != e.err {
+			t.Errorf("Expected to get an error %t at index %d, got %v", e.err, i, err)
+		}
+	}
+
+	// disabled inputs are not opened
+	config := &Config{}
+	config.Controls.MIDI.InputDeviceID = 1
+	if err := validateMIDIInputs(config, []int8{1}); err != nil {
+		t.Errorf("Expected to get no error for a disabled input, got %v", err)
+	}
+}
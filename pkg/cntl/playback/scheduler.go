@@ -0,0 +1,262 @@
+package playback
+
+import (
+	"context"
+	"time"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/internal/logging"
+)
+
+// realClock is the Clock of the system
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// writerQueue delivers commands to a TransportWriter in the order they were pushed
+type writerQueue struct {
+	logger   logging.Logger
+	writer   TransportWriter
+	commands chan cntl.Command
+	done     chan struct{}
+}
+
+func newWriterQueue(logger logging.Logger, w TransportWriter) *writerQueue {
+	q := &writerQueue{
+		logger:   logger,
+		writer:   w,
+		commands: make(chan cntl.Command, writerQueueSize),
+		done:     make(chan struct{}),
+	}
+
+	go q.run()
+	return q
+}
+
+func (q *writerQueue) run() {
+	defer close(q.done)
+
+	for cmd := range q.commands {
+		if err := q.writer.Write(cmd); err != nil {
+			q.logger.Error(err)
+		}
+	}
+}
+
+func (q *writerQueue) push(cmd cntl.Command) {
+	select {
+	case q.commands <- cmd:
+	default:
+		q.logger.Warnf("Writer %T cannot keep up, waiting before queueing frame %d", q.writer, cmd.Frame)
+		q.commands <- cmd
+	}
+}
+
+// close waits until all queued commands are written and tells the writer if the playback stopped
+func (q *writerQueue) close(stop bool) {
+	close(q.commands)
+	<-q.done
+
+	if !stop {
+		return
+	}
+
+	if s, ok := q.writer.(TransportStopper); ok {
+		if err := s.Stop(); err != nil {
+			q.logger.Error(err)
+		}
+	}
+}
+
+// scheduler plays commands at the due time of every frame, which is computed from the start of the song and its
+// tempo map instead of the time the previous frame was played, so the timing doesn't drift over long songs.
+type scheduler struct {
+	logger  logging.Logger
+	clock   Clock
+	writers []TransportWriter
+	// source drives the frames instead of the tempo of the song if given
+	source frameSource
+
+	// continued is set while commands are played that the song continues after, e.g. a count-in,
+	// so the writers are not stopped and the late frames are reported once the song ends
+	continued bool
+
+	// late counts the frames that were played more than a frame, and at least minLateness, after they were due,
+	// lateRun is set while consecutive frames are late, so only the first one of them is logged
+	late    uint64
+	lateRun bool
+}
+
+// Play plays a given slice of commands and send it to the given writers
+func Play(ctx context.Context, logger logging.Logger, writers []TransportWriter, commands []cntl.Command) error {
+	return play(ctx, logger, writers, commands, nil)
+}
+
+func play(ctx context.Context, logger logging.Logger, writers []TransportWriter, commands []cntl.Command, nav *Navigation) error {
+	s := &scheduler{logger: logger, clock: realClock{}, writers: writers}
+	return s.play(ctx, commands, nav)
+}
+
+// isBarStart returns whether the command at the given index starts a new bar
+func isBarStart(commands []cntl.Command, i int) bool {
+	return i >= len(commands) || (i > 0 && commands[i].Bar != commands[i-1].Bar)
+}
+
+// wait blocks until the given time or until the context is done
+func (s *scheduler) wait(ctx context.Context, until time.Time) error {
+	select {
+	case <-ctx.Done():
+		return ErrCancelled
+	default:
+	}
+
+	d := until.Sub(s.clock.Now())
+	if d <= 0 {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ErrCancelled
+	case <-s.clock.After(d):
+		return nil
+	}
+}
+
+func (s *scheduler) play(ctx context.Context, commands []cntl.Command, nav *Navigation) (err error) {
+	queues := make([]*writerQueue, len(s.writers))
+	for i, w := range s.writers {
+		queues[i] = newWriterQueue(s.logger, w)
+	}
+
+	// wait for pending writes, so no writer is called after play returned
+	defer func() {
+		stop := !s.continued || err != nil
+		for _, q := range queues {
+			q.close(stop)
+		}
+
+		if stop && s.late > 0 {
+			s.logger.Warnf("%d frames were played late", s.late)
+		}
+	}()
+
+	l := len(commands)
+	start := s.clock.Now()
+	states := newChannelStates(commands)
+
+	var i int
+	var due time.Duration
+	var tp tempo
+	var paused bool
+	for {
+		next := i
+		if s.source != nil {
+			seek, err := s.source.next(ctx)
+			if err != nil {
+				return err
+			}
+			if seek >= 0 {
+				next = seek
+			}
+		} else if err := s.wait(ctx, start.Add(due)); err != nil {
+			return err
+		}
+
+		if nav != nil {
+			if nav.isPaused() {
+				// the writers are told about the pause, e.g. to stop a MIDI clock
+				if !paused && i < l {
+					cmd := cntl.Command{FrameState: commands[i].FrameState, Paused: true}
+					for _, q := range queues {
+						q.push(cmd)
+					}
+				}
+				paused = true
+
+				// a seek is shown immediately, so the rig is at the new position before the playback is resumed
+				if at, ok := nav.pausedSeek(); ok {
+					i = int(at)
+					if err := tp.seek(commands, i); err != nil {
+						return err
+					}
+
+					if i < l {
+						cmd := cntl.Command{FrameState: commands[i].FrameState, DMXCommands: states.at(i), Paused: true}
+						for _, q := range queues {
+							q.push(cmd)
+						}
+					}
+				}
+
+				// an external clock keeps running while paused, otherwise the paused time is not part of the song
+				if s.source == nil {
+					pausedAt := s.clock.Now()
+					if err := s.wait(ctx, pausedAt.Add(pausePollInterval)); err != nil {
+						return err
+					}
+					start = start.Add(s.clock.Now().Sub(pausedAt))
+				}
+				continue
+			}
+			paused = false
+
+			next = int(nav.next(uint64(next), isBarStart(commands, next)))
+		}
+
+		var state cntl.DMXCommands
+		if next != i {
+			i = next
+			if err := tp.seek(commands, i); err != nil {
+				return err
+			}
+			state = states.at(i)
+		}
+
+		if i >= l {
+			return nil
+		}
+
+		cmd := commands[i]
+		if len(state) > 0 {
+			cmd.DMXCommands = append(state, cmd.DMXCommands...)
+		}
+		if cmd.BarChange != nil {
+			if err := tp.setBarChange(cmd.BarChange); err != nil {
+				return err
+			}
+		}
+
+		if nav != nil {
+			nav.adjustTempo(&tp)
+		}
+		interval := tp.next()
+
+		if late := s.clock.Now().Sub(start.Add(due)); s.source == nil && late > interval && late > minLateness {
+			if !s.lateRun {
+				s.logger.Warnf("Frame %d is played %v late", cmd.Frame, late)
+			}
+			s.late++
+			s.lateRun = true
+		} else {
+			s.lateRun = false
+		}
+
+		for _, q := range queues {
+			q.push(cmd)
+		}
+
+		if nav != nil {
+			nav.setFrame(uint64(i))
+		}
+
+		due += interval
+		i++
+	}
+}
@@ -0,0 +1,185 @@
+package playback
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+// fakeClock runs in virtual time, waiting advances the clock immediately
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+
+	// the wait with the number lateAt returns lateBy too late, as if the system was busy
+	waits  int
+	lateAt int
+	lateBy time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.waits++
+	c.now = c.now.Add(d)
+	if c.waits == c.lateAt {
+		c.now = c.now.Add(c.lateBy)
+	}
+
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+type orderWriter struct {
+	mu     sync.Mutex
+	frames []uint64
+}
+
+func (w *orderWriter) Write(cmd cntl.Command) error {
+	// give the scheduler a chance to queue the next frames before this one is written
+	time.Sleep(10 * time.Microsecond)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.frames = append(w.frames, cmd.Frame)
+	return nil
+}
+
+// schedulerCommands returns a bar at 120 BPM followed by two bars at 60 BPM
+func schedulerCommands() []cntl.Command {
+	commands := make([]cntl.Command, 3*int(cntl.RenderFrames))
+	for i := range commands {
+		commands[i].Frame = uint64(i)
+		commands[i].Bar = uint16(i/int(cntl.RenderFrames) + 1)
+	}
+	commands[0].BarChange = &cntl.BarChange{BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 120}}
+	commands[192].BarChange = &cntl.BarChange{BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 60}}
+
+	return commands
+}
+
+func TestScheduler_Play(t *testing.T) {
+	fast := CalcRenderSpeed(&cntl.BarChange{BarParams: cntl.BarParams{Speed: 120}})
+	slow := CalcRenderSpeed(&cntl.BarChange{BarParams: cntl.BarParams{Speed: 60}})
+	length := 192*fast + 384*slow
+
+	exp := []struct {
+		lateAt   int
+		lateBy   time.Duration
+		late     uint64
+		warnings int
+	}{
+		{0, 0, 0, 0},
+		{100, 50 * time.Millisecond, 4, 2},
+		{300, 100 * time.Millisecond, 4, 2},
+	}
+
+	for i, e := range exp {
+		start := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+		clock := &fakeClock{now: start, lateAt: e.lateAt, lateBy: e.lateBy}
+		w1, w2 := &orderWriter{}, &orderWriter{}
+		logger, hook := test.NewNullLogger()
+		s := &scheduler{logger: logger, clock: clock, writers: []TransportWriter{w1, w2}}
+
+		commands := schedulerCommands()
+		if err := s.play(context.Background(), commands, nil); err != nil {
+			t.Fatalf("Unexpected error at index %d: %v", i, err)
+		}
+
+		if d := clock.Now().Sub(start); d != length {
+			t.Errorf("Expected the song to take %v at index %d, got %v", length, i, d)
+		}
+
+		if s.late != e.late {
+			t.Errorf("Expected to get %d late frames at index %d, got %d", e.late, i, s.late)
+		}
+
+		// the first late frame of a run and the number of late frames at the end are logged
+		if len(hook.AllEntries()) != e.warnings {
+			t.Errorf("Expected to get %d warnings at index %d, got %d", e.warnings, i, len(hook.AllEntries()))
+		}
+
+		for _, w := range []*orderWriter{w1, w2} {
+			if len(w.frames) != len(commands) {
+				t.Fatalf("Expected to get %d frames at index %d, got %d", len(commands), i, len(w.frames))
+			}
+			for frame, f := range w.frames {
+				if f != uint64(frame) {
+					t.Errorf("Expected to get frame %d in order at index %d, got %d", frame, i, f)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestScheduler_Play_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	s := &scheduler{logger: logrus.New(), clock: &fakeClock{}, writers: []TransportWriter{&orderWriter{}}}
+	if err := s.play(ctx, schedulerCommands(), nil); err != ErrCancelled {
+		t.Errorf("Expected to get error %v, got %v", ErrCancelled, err)
+	}
+}
+
+type stoppingWriter struct {
+	orderWriter
+	stopped int
+}
+
+func (w *stoppingWriter) Stop() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.stopped++
+	return nil
+}
+
+func TestScheduler_Play_Stop(t *testing.T) {
+	w := &stoppingWriter{}
+	s := &scheduler{logger: logrus.New(), clock: &fakeClock{}, writers: []TransportWriter{w}}
+	if err := s.play(context.Background(), schedulerCommands(), nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if w.stopped != 1 || len(w.frames) != 576 {
+		t.Errorf("Expected the writer to be stopped once after all frames, got %d stops after %d frames", w.stopped, len(w.frames))
+	}
+}
+
+func TestScheduler_Play_Continued(t *testing.T) {
+	w := &stoppingWriter{}
+	s := &scheduler{logger: logrus.New(), clock: &fakeClock{}, writers: []TransportWriter{w}, continued: true}
+	if err := s.play(context.Background(), schedulerCommands(), nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if w.stopped != 0 {
+		t.Errorf("Expected the writer not to be stopped after a count-in, got %d stops", w.stopped)
+	}
+
+	s.continued = false
+	if err := s.play(context.Background(), schedulerCommands(), nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if w.stopped != 1 || len(w.frames) != 1152 {
+		t.Errorf("Expected the writer to be stopped once at the end of the song, got %d stops after %d frames", w.stopped, len(w.frames))
+	}
+}
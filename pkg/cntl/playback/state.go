@@ -0,0 +1,103 @@
+package playback
+
+import (
+	"sort"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+)
+
+type channelKey struct {
+	universe cntl.DMXUniverse
+	channel  cntl.DMXChannel
+}
+
+// channelValues collects the last value of every DMX channel in the order the channels were first written
+type channelValues struct {
+	index map[channelKey]int
+	state cntl.DMXCommands
+}
+
+func newChannelValues(state cntl.DMXCommands) *channelValues {
+	v := &channelValues{
+		index: make(map[channelKey]int, len(state)),
+		state: append(cntl.DMXCommands{}, state...),
+	}
+
+	for i, c := range v.state {
+		v.index[channelKey{c.Universe, c.Channel}] = i
+	}
+
+	return v
+}
+
+func (v *channelValues) apply(commands []cntl.Command) {
+	for _, cmd := range commands {
+		for _, c := range cmd.DMXCommands {
+			key := channelKey{c.Universe, c.Channel}
+			if i, ok := v.index[key]; ok {
+				v.state[i] = c
+				continue
+			}
+
+			v.index[key] = len(v.state)
+			v.state = append(v.state, c)
+		}
+	}
+}
+
+// ChannelState returns the last value of every DMX channel that was written before the given frame,
+// so the rig can be brought into the state it would have when playing up to that frame.
+func ChannelState(commands []cntl.Command, frame int) cntl.DMXCommands {
+	if frame > len(commands) {
+		frame = len(commands)
+	}
+
+	v := newChannelValues(nil)
+	v.apply(commands[:frame])
+
+	return v.state
+}
+
+// channelStates returns the ChannelState of a song from checkpoints at every bar start, so seeking and jumping
+// only replays the commands since the bar start instead of the whole song. The checkpoints are computed on first use.
+type channelStates struct {
+	commands []cntl.Command
+	// frames holds the frames of the checkpoints in ascending order, states the ChannelState at each of them
+	frames []int
+	states []cntl.DMXCommands
+}
+
+func newChannelStates(commands []cntl.Command) *channelStates {
+	return &channelStates{commands: commands}
+}
+
+func (s *channelStates) build() {
+	v := newChannelValues(nil)
+	s.frames = []int{0}
+	s.states = []cntl.DMXCommands{{}}
+
+	for i := 1; i < len(s.commands); i++ {
+		v.apply(s.commands[i-1 : i])
+		if isBarStart(s.commands, i) {
+			s.frames = append(s.frames, i)
+			s.states = append(s.states, append(cntl.DMXCommands{}, v.state...))
+		}
+	}
+}
+
+// at returns the ChannelState at the given frame
+func (s *channelStates) at(frame int) cntl.DMXCommands {
+	if frame > len(s.commands) {
+		frame = len(s.commands)
+	}
+
+	if s.frames == nil {
+		s.build()
+	}
+
+	cp := sort.SearchInts(s.frames, frame+1) - 1
+	v := newChannelValues(s.states[cp])
+	v.apply(s.commands[s.frames[cp]:frame])
+
+	return v.state
+}
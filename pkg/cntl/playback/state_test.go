@@ -0,0 +1,70 @@
+package playback
+
+import (
+	"testing"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/song"
+	"github.com/StageAutoControl/controller/pkg/internal/fixtures"
+)
+
+func TestChannelState(t *testing.T) {
+	cs := make([]cntl.Command, 4)
+	cs[0].DMXCommands = cntl.DMXCommands{{Universe: 1, Channel: 1, Value: cntl.DMXValue{Value: 10}}, {Universe: 2, Channel: 1, Value: cntl.DMXValue{Value: 20}}}
+	cs[1].DMXCommands = cntl.DMXCommands{{Universe: 1, Channel: 1, Value: cntl.DMXValue{Value: 30}}}
+	cs[3].DMXCommands = cntl.DMXCommands{{Universe: 1, Channel: 2, Value: cntl.DMXValue{Value: 40}}}
+
+	exp := []struct {
+		frame int
+		state cntl.DMXCommands
+	}{
+		{0, cntl.DMXCommands{}},
+		{1, cntl.DMXCommands{{Universe: 1, Channel: 1, Value: cntl.DMXValue{Value: 10}}, {Universe: 2, Channel: 1, Value: cntl.DMXValue{Value: 20}}}},
+		{3, cntl.DMXCommands{{Universe: 1, Channel: 1, Value: cntl.DMXValue{Value: 30}}, {Universe: 2, Channel: 1, Value: cntl.DMXValue{Value: 20}}}},
+		{10, cntl.DMXCommands{{Universe: 1, Channel: 1, Value: cntl.DMXValue{Value: 30}}, {Universe: 2, Channel: 1, Value: cntl.DMXValue{Value: 20}}, {Universe: 1, Channel: 2, Value: cntl.DMXValue{Value: 40}}}},
+	}
+
+	for i, e := range exp {
+		if state := ChannelState(cs, e.frame); !state.Equals(e.state) {
+			t.Errorf("Expected to get state %+v at index %d, got %+v", e.state, i, state)
+		}
+	}
+}
+
+// fixtureCommands returns the rendered commands of the fixture song, which has DMX commands in most of its bars
+func fixtureCommands(t testing.TB) []cntl.Command {
+	t.Helper()
+
+	commands, err := song.Render(fixtures.DataStore(), "3c1065c8-0b14-11e7-96eb-5b134621c411")
+	if err != nil {
+		t.Fatalf("failed to render song: %v", err)
+	}
+
+	return commands
+}
+
+func TestChannelStates(t *testing.T) {
+	commands := fixtureCommands(t)
+	states := newChannelStates(commands)
+
+	for frame := 0; frame <= len(commands)+1; frame++ {
+		if exp, state := ChannelState(commands, frame), states.at(frame); !state.Equals(exp) {
+			t.Errorf("Expected to get state %+v at frame %d, got %+v", exp, frame, state)
+		}
+	}
+}
+
+func BenchmarkChannelState(b *testing.B) {
+	commands := fixtureCommands(b)
+	for i := 0; i < b.N; i++ {
+		ChannelState(commands, len(commands)-1)
+	}
+}
+
+func BenchmarkChannelStates(b *testing.B) {
+	commands := fixtureCommands(b)
+	states := newChannelStates(commands)
+	for i := 0; i < b.N; i++ {
+		states.at(len(commands) - 1)
+	}
+}
@@ -0,0 +1,30 @@
+package playback
+
+import (
+	"time"
+)
+
+// tapTempo averages the intervals of the latest taps, a pause of tapTimeout starts a new series of taps
+type tapTempo struct {
+	taps []time.Time
+}
+
+// tap adds a tap at the given time and returns the averaged tempo in BPM, or 0 if it is the first tap of a series
+func (t *tapTempo) tap(at time.Time) float64 {
+	if n := len(t.taps); n > 0 && (at.Sub(t.taps[n-1]) > tapTimeout || !at.After(t.taps[n-1])) {
+		t.taps = t.taps[:0]
+	}
+
+	t.taps = append(t.taps, at)
+	if len(t.taps) > maxTaps {
+		t.taps = t.taps[len(t.taps)-maxTaps:]
+	}
+
+	n := len(t.taps)
+	if n < 2 {
+		return 0
+	}
+
+	interval := t.taps[n-1].Sub(t.taps[0]) / time.Duration(n-1)
+	return float64(time.Minute) / float64(interval)
+}
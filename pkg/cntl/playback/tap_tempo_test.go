@@ -0,0 +1,36 @@
+package playback
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTapTempo(t *testing.T) {
+	start := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	exp := []struct {
+		taps []int
+		bpm  float64
+	}{
+		{[]int{0}, 0},
+		{[]int{0, 500}, 120},
+		{[]int{0, 500, 1100, 1500}, 120},
+		{[]int{0, 500, 3000}, 0},
+		{[]int{0, 500, 3000, 4000}, 60},
+		{[]int{0, 500, 500}, 0},
+		// only the latest taps are averaged
+		{[]int{0, 1000, 1100, 1200, 1300, 1400, 1500, 1600, 1700}, 600},
+	}
+
+	for i, e := range exp {
+		var tp tapTempo
+		var bpm float64
+		for _, ms := range e.taps {
+			bpm = tp.tap(start.Add(time.Duration(ms) * time.Millisecond))
+		}
+
+		if bpm != e.bpm {
+			t.Errorf("Expected to get %v BPM at index %d, got %v", e.bpm, i, bpm)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package playback
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/dmx"
+	"github.com/StageAutoControl/controller/pkg/cntl/song"
+)
+
+// tempo keeps track of the speed while playing, following the tempo ramps of the bar changes
+type tempo struct {
+	speed    float64
+	from, to float64
+	ease     dmx.EasingFunc
+
+	frame, frames uint64
+
+	// noteValue is the note value of the current bar, the speed is always given in quarter notes
+	noteValue uint8
+
+	// factor and nudge adjust the speed of the whole song at playback time, a factor of 0 keeps the speed
+	factor, nudge float64
+}
+
+// TempoOptions override the tempo of a whole song, keeping the relation between its bar changes.
+// BPM sets the speed of the first bar change, all other bar changes are scaled accordingly.
+type TempoOptions struct {
+	Multiplier float64 `json:"multiplier"`
+	BPM        uint16  `json:"bpm"`
+}
+
+// factor returns the factor the speed of the given commands is scaled with
+func (o TempoOptions) factor(commands []cntl.Command) (float64, error) {
+	switch {
+	case o.Multiplier != 0 && o.BPM != 0:
+		return 0, ErrTempoMultiplierAndBPMGiven
+
+	case o.Multiplier < 0:
+		return 0, fmt.Errorf("tempo multiplier must be positive, got %v", o.Multiplier)
+
+	case o.Multiplier > 0:
+		return o.Multiplier, nil
+
+	case o.BPM > 0:
+		if len(commands) == 0 || commands[0].BarChange == nil || commands[0].BarChange.Speed == 0 {
+			return 0, fmt.Errorf("cannot find a bar change with a speed at frame 0")
+		}
+		return float64(o.BPM) / float64(commands[0].BarChange.Speed), nil
+	}
+
+	return 1, nil
+}
+
+// FrameTimes returns the time every one of the given commands starts at when played with the given tempo options,
+// followed by the time the last one ends at
+func FrameTimes(commands []cntl.Command, opts TempoOptions) ([]time.Duration, error) {
+	factor, err := opts.factor(commands)
+	if err != nil {
+		return nil, err
+	}
+
+	return frameTimes(commands, factor)
+}
+
+func frameTimes(commands []cntl.Command, factor float64) ([]time.Duration, error) {
+	tp := tempo{factor: factor}
+	times := make([]time.Duration, len(commands)+1)
+	for i, cmd := range commands {
+		if cmd.BarChange != nil {
+			if err := tp.setBarChange(cmd.BarChange); err != nil {
+				return nil, err
+			}
+		}
+		times[i+1] = times[i] + tp.next()
+	}
+
+	return times, nil
+}
+
+// setBarChange applies the speed and ramp of the given bar change, a bar change without a speed keeps the current one
+func (t *tempo) setBarChange(bc *cntl.BarChange) error {
+	if bc.Speed != 0 {
+		t.speed = float64(bc.Speed)
+	}
+	if bc.NoteValue != 0 {
+		t.noteValue = bc.NoteValue
+	}
+	t.ease = nil
+
+	if bc.Ramp == nil {
+		return nil
+	}
+
+	ease := bc.Ramp.Ease
+	if ease == "" {
+		ease = cntl.EaseLinear
+	}
+
+	var err error
+	if t.ease, err = dmx.GetEasingFunc(ease); err != nil {
+		return err
+	}
+
+	t.from = t.speed
+	t.to = float64(bc.Ramp.Speed)
+	t.frame = 0
+	t.frames = uint64(bc.Ramp.Bars) * song.CalcBarLength(bc)
+
+	return nil
+}
+
+// seek sets the tempo of the bar change the given frame is in, including its ramp progress
+func (t *tempo) seek(commands []cntl.Command, frame int) error {
+	for i := frame; i >= 0; i-- {
+		if i >= len(commands) || commands[i].BarChange == nil {
+			continue
+		}
+
+		if err := t.setBarChange(commands[i].BarChange); err != nil {
+			return err
+		}
+
+		// the bar change itself is applied again when its frame is played
+		for j := i; j < frame; j++ {
+			t.next()
+		}
+		return nil
+	}
+
+	return nil
+}
+
+// next returns the duration of the next frame
+func (t *tempo) next() time.Duration {
+	if t.ease != nil {
+		if t.frame < t.frames {
+			t.speed = t.from + (t.to-t.from)*t.ease(float64(t.frame)/float64(t.frames))
+			t.frame++
+		} else {
+			t.speed = t.to
+			t.ease = nil
+		}
+	}
+
+	return time.Duration(float64(4*time.Minute) / (t.bpm() * float64(cntl.RenderFrames)))
+}
+
+// quarterBPM converts the given BPM in notes of the current bar, e.g. tapped eighths in 7/8, to quarter notes
+func (t *tempo) quarterBPM(bpm float64) float64 {
+	if t.noteValue == 0 {
+		return bpm
+	}
+
+	return bpm * 4 / float64(t.noteValue)
+}
+
+// bpm returns the current speed including the playback adjustments, never dropping below minSpeed
+func (t *tempo) bpm() float64 {
+	speed := t.speed
+	if t.factor != 0 {
+		speed *= t.factor
+	}
+
+	return math.Max(speed+t.nudge, minSpeed)
+}
@@ -0,0 +1,77 @@
+package playback
+
+import (
+	"testing"
+	"time"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+)
+
+func TestTempo(t *testing.T) {
+	bc := cntl.BarChange{BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 120}}
+	linear := bc
+	linear.Ramp = &cntl.TempoRamp{Speed: 240, Bars: 1}
+	eased := bc
+	eased.Ramp = &cntl.TempoRamp{Speed: 240, Bars: 1, Ease: cntl.EaseQuadIn}
+
+	exp := []struct {
+		bc     cntl.BarChange
+		frame  int
+		speed  time.Duration
+		factor float64
+		nudge  float64
+	}{
+		{bc, 0, time.Minute / 5760, 0, 0},
+		{bc, 500, time.Minute / 5760, 0, 0},
+		{linear, 0, time.Minute / 5760, 0, 0},
+		{linear, 96, time.Minute / 8640, 0, 0},
+		{linear, 192, time.Minute / 11520, 0, 0},
+		{linear, 500, time.Minute / 11520, 0, 0},
+		{eased, 96, time.Minute / 7200, 0, 0},
+		{bc, 0, time.Minute / 11520, 2, 0},
+		{bc, 0, time.Minute / 6240, 0, 10},
+		{linear, 192, time.Minute / 5760, 0.5, 0},
+		{bc, 0, time.Minute / 48, 0, -500},
+	}
+
+	for i, e := range exp {
+		tp := tempo{factor: e.factor, nudge: e.nudge}
+		if err := tp.setBarChange(&e.bc); err != nil {
+			t.Fatalf("Unexpected error at index %d: %v", i, err)
+		}
+
+		for frame := 0; frame < e.frame; frame++ {
+			tp.next()
+		}
+
+		if res := tp.next(); res != e.speed {
+			t.Errorf("Expected to get duration %q at index %d, got %q", e.speed, i, res)
+		}
+	}
+}
+
+func TestTempoOptions_Factor(t *testing.T) {
+	commands := []cntl.Command{{BarChange: &cntl.BarChange{BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 120}}}}
+
+	exp := []struct {
+		opts   TempoOptions
+		factor float64
+		err    bool
+	}{
+		{TempoOptions{}, 1, false},
+		{TempoOptions{Multiplier: 1.5}, 1.5, false},
+		{TempoOptions{BPM: 126}, 1.05, false},
+		{TempoOptions{Multiplier: -1}, 0, true},
+		{TempoOptions{Multiplier: 1.5, BPM: 126}, 0, true},
+	}
+
+	for i, e := range exp {
+		factor, err := e.opts.factor(commands)
+		if (err != nil) != e.err {
+			t.Errorf("Expected to get error %t at index %d, got %v", e.err, i, err)
+		}
+		if factor != e.factor {
+			t.Errorf("Expected to get factor %v at index %d, got %v", e.factor, i, factor)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package playback
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/timecode"
+)
+
+// timecodeChaser plays the frames of a song at the position of an incoming timecode, e.g. MTC from a video playback.
+// Between timecodes the frames are played at the tempo of the song. It locates to the position of the timecode
+// when it differs too much, and freewheels on dropouts until the timecode is missing for too long.
+type timecodeChaser struct {
+	clock     Clock
+	timecodes <-chan timecode.Timecode
+
+	// start is the timecode of the first frame of the song, times holds the time of every frame since the start
+	start time.Duration
+	times []time.Duration
+
+	frame    int
+	locked   bool
+	received time.Time
+	position time.Duration
+}
+
+func newTimecodeChaser(clock Clock, timecodes <-chan timecode.Timecode) *timecodeChaser {
+	return &timecodeChaser{clock: clock, timecodes: timecodes}
+}
+
+// load prepares chasing the given song with its rendered commands played at the given tempo factor
+func (c *timecodeChaser) load(s *cntl.Song, commands []cntl.Command, factor float64) error {
+	if s.Timecode == nil {
+		return ErrSongHasNoTimecode
+	}
+
+	start, err := timecode.Parse(s.Timecode.Start, s.Timecode.Rate)
+	if err != nil {
+		return err
+	}
+
+	times, err := frameTimes(commands, factor)
+	if err != nil {
+		return err
+	}
+
+	c.start = start.Duration()
+	c.times = times
+	c.frame = -1
+	return nil
+}
+
+// frameAt returns the frame that is played at the given time since the start of the song, -1 before the song started
+func (c *timecodeChaser) frameAt(d time.Duration) int {
+	if d < 0 {
+		return -1
+	}
+
+	// the first frame starting after the given time is the one after the played frame
+	i := sort.Search(len(c.times), func(i int) bool { return c.times[i] > d })
+	return i - 1
+}
+
+func (c *timecodeChaser) receive(tc timecode.Timecode) {
+	c.locked = true
+	c.received = c.clock.Now()
+	c.position = tc.Duration() - c.start
+}
+
+func (c *timecodeChaser) next(ctx context.Context) (int, error) {
+	for {
+		// always chase the latest timecode
+		for drained := false; !drained; {
+			select {
+			case tc, ok := <-c.timecodes:
+				if !ok {
+					c.timecodes = nil
+					drained = true
+					continue
+				}
+				c.receive(tc)
+			default:
+				drained = true
+			}
+		}
+
+		var due <-chan time.Time
+		if c.locked {
+			elapsed := c.clock.Now().Sub(c.received)
+			if elapsed >= timecodeFreewheel {
+				c.locked = false
+				continue
+			}
+
+			pos := c.position + elapsed
+			want := c.frameAt(pos)
+			next := c.frame + 1
+
+			switch {
+			case want >= 0 && (pos-c.times[next] > chaseTolerance || (c.frame >= 0 && c.times[c.frame]-pos > chaseTolerance)):
+				c.frame = want
+				return want, nil
+
+			case want >= next:
+				c.frame = next
+				return -1, nil
+			}
+
+			wait := timecodeFreewheel - elapsed
+			if d := c.times[next] - pos; d < wait {
+				wait = d
+			}
+			due = c.clock.After(wait)
+		} else if c.timecodes == nil {
+			return 0, ErrTimecodeInputClosed
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, ErrCancelled
+
+		case <-due:
+
+		case tc, ok := <-c.timecodes:
+			if !ok {
+				c.timecodes = nil
+				continue
+			}
+			c.receive(tc)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package playback
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/timecode"
+)
+
+func chaserTimecode(t *testing.T, s string) timecode.Timecode {
+	tc, err := timecode.Parse(s, cntl.TimecodeRate25)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tc
+}
+
+func TestTimecodeChaser(t *testing.T) {
+	s := &cntl.Song{Timecode: &cntl.SongTimecode{Start: "01:00:00:00", Rate: cntl.TimecodeRate25}}
+	tcs := make(chan timecode.Timecode, 10)
+	c := newTimecodeChaser(&fakeClock{}, tcs)
+
+	if err := c.load(&cntl.Song{}, schedulerCommands(), 1); err != ErrSongHasNoTimecode {
+		t.Errorf("Expected to get error %v, got %v", ErrSongHasNoTimecode, err)
+	}
+	if err := c.load(s, schedulerCommands(), 1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	exp := []struct {
+		tc    string
+		frame int
+	}{
+		// locates to the start of bar 2, then continues with the next frame
+		{"01:00:02:00", 192},
+		{"", -1},
+		// locates to the start of bar 3 as the timecode jumped
+		{"01:00:06:00", 384},
+		{"", -1},
+	}
+
+	for i, e := range exp {
+		if e.tc != "" {
+			tcs <- chaserTimecode(t, e.tc)
+		}
+
+		frame, err := c.next(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error at index %d: %v", i, err)
+		}
+		if frame != e.frame {
+			t.Errorf("Expected to get frame %d at index %d, got %d", e.frame, i, frame)
+		}
+	}
+
+	// freewheels for a second after the timecode dropped out, a frame lasts 1/48 second in bar 3
+	close(tcs)
+	var frames int
+	for {
+		frame, err := c.next(context.Background())
+		if err != nil {
+			if err != ErrTimecodeInputClosed {
+				t.Errorf("Expected to get error %v, got %v", ErrTimecodeInputClosed, err)
+			}
+			break
+		}
+		if frame != -1 {
+			t.Errorf("Expected to continue with the next frame while freewheeling, got %d", frame)
+		}
+		frames++
+	}
+
+	if frames != 47 {
+		t.Errorf("Expected to freewheel for 47 frames, got %d", frames)
+	}
+}
+
+func TestTimecodeChaser_BeforeStart(t *testing.T) {
+	s := &cntl.Song{Timecode: &cntl.SongTimecode{Start: "01:00:00:00", Rate: cntl.TimecodeRate25}}
+	tcs := make(chan timecode.Timecode, 1)
+	clock := &fakeClock{}
+	c := newTimecodeChaser(clock, tcs)
+	if err := c.load(s, schedulerCommands(), 1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tcs <- chaserTimecode(t, "00:59:59:12")
+	frame, err := c.next(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if frame != -1 {
+		t.Errorf("Expected to start with the first frame, got %d", frame)
+	}
+	if d := clock.Now().Sub(time.Time{}); d != 520*time.Millisecond {
+		t.Errorf("Expected to wait 520ms until the song starts, got %v", d)
+	}
+}
@@ -1,12 +1,44 @@
 package playback
 
-import "github.com/StageAutoControl/controller/pkg/cntl"
+import (
+	"context"
+	"time"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/timecode"
+)
 
 // TransportWriter is a writer to an output stream, for example a websocket or Stdout.
 type TransportWriter interface {
 	Write(cntl.Command) error
 }
 
+// TransportStopper is implemented by TransportWriters which need to know when the playback stopped, e.g. to stop a MIDI clock
+type TransportStopper interface {
+	Stop() error
+}
+
+// MIDIInput is a source of incoming MIDI commands
+type MIDIInput interface {
+	Listen(ctx context.Context) <-chan cntl.MIDICommand
+}
+
+// MIDIInputUser is implemented by processes which keep MIDI input devices open, so they are not opened twice
+type MIDIInputUser interface {
+	MIDIInputs() []int8
+}
+
+// Clock provides the current time and waits for a given duration, so songs can be played in virtual time
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// TimecodeInput is a source of incoming timecode, e.g. MTC
+type TimecodeInput interface {
+	Listen(ctx context.Context) <-chan timecode.Timecode
+}
+
 // Waiter waits for a trigger to happen
 type Waiter interface {
 	Wait(done chan struct{}, cancel chan struct{}) error
@@ -27,16 +59,55 @@ type loader interface {
 // Params specifies how to run a playback
 type Params struct {
 	Song struct {
-		ID string `json:"id"`
+		ID    string       `json:"id"`
+		Start StartOptions `json:"start"`
 	} `json:"song"`
 	SetList struct {
 		ID string `json:"id"`
 	} `json:"setList"`
 }
 
+// StartOptions define where a song starts playing, how many bars are counted in before, e.g. during rehearsals,
+// and how fast it is played
+type StartOptions struct {
+	Bar     uint16       `json:"bar"`
+	Marker  string       `json:"marker"`
+	CountIn uint8        `json:"countIn"`
+	Click   bool         `json:"click"`
+	Tempo   TempoOptions `json:"tempo"`
+}
+
 type parsedConfig struct {
-	waiters []Waiter
-	writers []TransportWriter
+	waiters        []Waiter
+	writers        []TransportWriter
+	midiInput      MIDIInput
+	midiClockInput MIDIInput
+	mtcInput       TimecodeInput
+	ltcInput       TimecodeInput
+}
+
+// MIDITrigger matches incoming MIDI commands by status and first data byte, e.g. a note on a channel
+type MIDITrigger struct {
+	Status uint8 `json:"status"`
+	Data1  uint8 `json:"data1"`
+}
+
+// MarkerTrigger jumps to the given marker when the trigger matches
+type MarkerTrigger struct {
+	MIDITrigger
+	Marker string `json:"marker"`
+}
+
+// MIDIControlsConfig configures which incoming MIDI commands control the navigation of the played song
+type MIDIControlsConfig struct {
+	Enabled       bool            `json:"enabled"`
+	InputDeviceID int8            `json:"inputDeviceId"`
+	Skip          *MIDITrigger    `json:"skip"`
+	Loop          *MIDITrigger    `json:"loop"`
+	Release       *MIDITrigger    `json:"release"`
+	Tap           *MIDITrigger    `json:"tap"`
+	DownbeatTap   *MIDITrigger    `json:"downbeatTap"`
+	Markers       []MarkerTrigger `json:"markers"`
 }
 
 // Config stores the information on which waiters and/or transport writers are enabled and what their config is
@@ -57,6 +128,25 @@ type Config struct {
 		MIDI struct {
 			Enabled        bool `json:"enabled"`
 			OutputDeviceID int8 `json:"outputDeviceId"`
+			Clock          bool `json:"clock"`
+			Transport      bool `json:"transport"`
 		} `json:"midi"`
 	} `json:"transportWriters"`
+	Controls struct {
+		MIDI MIDIControlsConfig `json:"midi"`
+	} `json:"controls"`
+	Sync struct {
+		MIDIClockInput struct {
+			Enabled       bool `json:"enabled"`
+			InputDeviceID int8 `json:"inputDeviceId"`
+		} `json:"midiClockInput"`
+		MTCInput struct {
+			Enabled       bool `json:"enabled"`
+			InputDeviceID int8 `json:"inputDeviceId"`
+		} `json:"mtcInput"`
+		LTCInput struct {
+			Enabled bool   `json:"enabled"`
+			File    string `json:"file"`
+		} `json:"ltcInput"`
+	} `json:"sync"`
 }
@@ -0,0 +1,148 @@
+package smf
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/dmx"
+	"github.com/StageAutoControl/controller/pkg/cntl/song"
+)
+
+// exportDivision is the number of ticks per quarter note of exported files, a multiple of the frames per quarter note
+const exportDivision = 10 * uint64(cntl.RenderFrames) / 4
+
+// ticksPerFrame is the number of ticks of an exported file per render frame
+const ticksPerFrame = exportDivision * 4 / uint64(cntl.RenderFrames)
+
+// Export renders the song with the given ID and returns it as a file with two tracks.
+// The first track contains the bar changes as tempo and time signature events, tempo ramps as a tempo event per note,
+// and the scene starts as markers. The second track contains the MIDI commands.
+func Export(ds *cntl.DataStore, songID string) (*File, error) {
+	commands, err := song.Render(ds, songID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render song: %v", err)
+	}
+
+	s, err := song.ResolvePositions(ds.Songs[songID])
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve positions: %v", err)
+	}
+
+	end := uint64(len(commands)) * ticksPerFrame
+	conductor := Track{Events: []Event{
+		metaEvent(0, MetaTrackName, []byte(s.Name)),
+		metaEvent(end, MetaEndOfTrack, []byte{}),
+	}}
+	midiTrack := Track{Events: []Event{
+		metaEvent(0, MetaTrackName, []byte("MIDI commands")),
+		metaEvent(end, MetaEndOfTrack, []byte{}),
+	}}
+
+	// a tempo ramp ends at the next bar change
+	until := uint64(len(commands))
+	for i := len(commands) - 1; i >= 0; i-- {
+		cmd := commands[i]
+		if cmd.BarChange == nil {
+			continue
+		}
+
+		events, err := barChangeEvents(cmd.Frame, until, cmd.BarChange)
+		if err != nil {
+			return nil, err
+		}
+		conductor.Events = append(conductor.Events, events...)
+		until = cmd.Frame
+	}
+
+	for _, cmd := range commands {
+		tick := cmd.Frame * ticksPerFrame
+		for _, mc := range cmd.MIDICommands {
+			midiTrack.Events = append(midiTrack.Events, Event{Tick: tick, Status: mc.Status, Data1: mc.Data1, Data2: mc.Data2})
+		}
+	}
+
+	for _, sp := range s.DMXScenes {
+		name := sp.ID
+		if sc, ok := ds.DMXScenes[sp.ID]; ok && sc.Name != "" {
+			name = sc.Name
+		}
+		conductor.Events = append(conductor.Events, metaEvent(sp.At*ticksPerFrame, MetaMarker, []byte(name)))
+	}
+
+	sort.SliceStable(conductor.Events, func(i, j int) bool { return conductor.Events[i].Tick < conductor.Events[j].Tick })
+
+	return &File{Format: 1, Division: uint16(exportDivision), Tracks: []Track{conductor, midiTrack}}, nil
+}
+
+func metaEvent(tick uint64, meta uint8, data []byte) Event {
+	return Event{Tick: tick, Status: StatusMeta, Meta: meta, Data: data}
+}
+
+func tempoEvent(tick uint64, bpm float64) Event {
+	micros := uint32(math.Round(60e6 / math.Max(bpm, 1)))
+	return metaEvent(tick, MetaTempo, []byte{byte(micros >> 16), byte(micros >> 8), byte(micros)})
+}
+
+// barChangeEvents returns the time signature and tempo events of the given bar change at the given frame,
+// a tempo ramp is cut at the until frame
+func barChangeEvents(at, until uint64, bc *cntl.BarChange) ([]Event, error) {
+	tick := at * ticksPerFrame
+	count, power, err := timeSignature(bc.NoteCount, bc.NoteValue)
+	if err != nil {
+		return nil, fmt.Errorf("bar change at frame %d: %v", bc.At, err)
+	}
+
+	events := []Event{
+		// a metronome click every quarter note, 8 thirty-second notes per quarter note
+		metaEvent(tick, MetaTimeSignature, []byte{count, power, 24, 8}),
+		tempoEvent(tick, float64(bc.Speed)),
+	}
+
+	if bc.Ramp == nil {
+		return events, nil
+	}
+
+	ease := bc.Ramp.Ease
+	if ease == "" {
+		ease = cntl.EaseLinear
+	}
+	easeFunc, err := dmx.GetEasingFunc(ease)
+	if err != nil {
+		return nil, fmt.Errorf("bar change at frame %d: %v", bc.At, err)
+	}
+
+	from, to := float64(bc.Speed), float64(bc.Ramp.Speed)
+	note := song.CalcNoteLength(bc)
+	frames := uint64(bc.Ramp.Bars) * song.CalcBarLength(bc)
+	for frame := note; frame < frames && at+frame < until; frame += note {
+		speed := from + (to-from)*easeFunc(float64(frame)/float64(frames))
+		events = append(events, tempoEvent(tick+frame*ticksPerFrame, speed))
+	}
+
+	if at+frames < until {
+		events = append(events, tempoEvent(tick+frames*ticksPerFrame, to))
+	}
+
+	return events, nil
+}
+
+// timeSignature returns the given time signature with a power of two note value, keeping the length of the bar.
+// Other note values like triplets are converted preferring quarter notes.
+func timeSignature(count, value uint8) (uint8, uint8, error) {
+	for _, power := range []uint8{0, 1, 2, 3, 4, 5, 6} {
+		if value == 1<<power {
+			return count, power, nil
+		}
+	}
+
+	for _, power := range []uint8{2, 3, 4, 5, 6, 1, 0} {
+		n := uint64(count) << power
+		if n%uint64(value) == 0 && n/uint64(value) <= math.MaxUint8 {
+			return uint8(n / uint64(value)), power, nil
+		}
+	}
+
+	return 0, 0, fmt.Errorf("time signature %d/%d cannot be written to a midi file", count, value)
+}
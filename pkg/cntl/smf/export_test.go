@@ -0,0 +1,131 @@
+package smf
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+)
+
+func exportDataStore() *cntl.DataStore {
+	return &cntl.DataStore{
+		Songs: map[string]*cntl.Song{
+			"song": {
+				ID:   "song",
+				Name: "Export",
+				BarChanges: []cntl.BarChange{
+					{At: 0, BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 120}},
+					{At: 192, BarParams: cntl.BarParams{NoteCount: 6, NoteValue: 8, Speed: 90}},
+					{At: 336, BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 100}, Ramp: &cntl.TempoRamp{Speed: 140, Bars: 1}},
+					{At: 528, BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 140}},
+				},
+				MIDICommands: []cntl.MIDICommand{
+					{At: 0, Status: 0xC0, Data1: 3},
+					{At: 24, Status: 0x90, Data1: 60, Data2: 100},
+					{At: 48, Status: 0x80, Data1: 60},
+					{At: 600, Status: 0xB0, Data1: 7, Data2: 127},
+				},
+				DMXScenes: []cntl.DMXScenePosition{
+					{ID: "scene", At: 192},
+				},
+			},
+		},
+		DMXScenes: map[string]*cntl.DMXScene{
+			"scene": {ID: "scene", Name: "Verse", NoteValue: 4, NoteCount: 4},
+		},
+	}
+}
+
+func TestExport(t *testing.T) {
+	f, err := Export(exportDataStore(), "song")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, f); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	read, err := Read(buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if read.Division != 480 || len(read.Tracks) != 2 {
+		t.Fatalf("Expected to get 2 tracks with division 480, got %d with %d", len(read.Tracks), read.Division)
+	}
+
+	var tempos []uint64
+	for _, e := range read.Tracks[0].Events {
+		if e.Status == StatusMeta && e.Meta == MetaTempo {
+			tempos = append(tempos, e.Tick/ticksPerFrame)
+		}
+	}
+
+	// the ramp has a tempo event per note until the next bar change
+	expTempos := []uint64{0, 192, 336, 384, 432, 480, 528}
+	if !reflect.DeepEqual(tempos, expTempos) {
+		t.Errorf("Expected to get tempo events at frames %v, got %v", expTempos, tempos)
+	}
+
+	s, err := Import(read, ImportOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := exportDataStore().Songs["song"]
+	if s.Name != expected.Name {
+		t.Errorf("Expected to get name %q, got %q", expected.Name, s.Name)
+	}
+
+	if !reflect.DeepEqual(s.MIDICommands, expected.MIDICommands) {
+		t.Errorf("Expected to get MIDI commands %+v, got %+v", expected.MIDICommands, s.MIDICommands)
+	}
+
+	expSections := []cntl.SongSection{{Name: "Verse", At: 192}}
+	if !reflect.DeepEqual(s.Sections, expSections) {
+		t.Errorf("Expected to get scene markers %+v, got %+v", expSections, s.Sections)
+	}
+
+	// the tempo events of the ramp are applied at the next bar change
+	expBarChanges := []cntl.BarChange{
+		{At: 0, BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 120}},
+		{At: 192, BarParams: cntl.BarParams{NoteCount: 6, NoteValue: 8, Speed: 90}},
+		{At: 336, BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 100}},
+		{At: 528, BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 140}},
+	}
+	if !reflect.DeepEqual(s.BarChanges, expBarChanges) {
+		t.Errorf("Expected to get bar changes %+v, got %+v", expBarChanges, s.BarChanges)
+	}
+}
+
+func TestTimeSignature(t *testing.T) {
+	exp := []struct {
+		count, value uint8
+		expCount     uint8
+		expPower     uint8
+		err          bool
+	}{
+		{4, 4, 4, 2, false},
+		{6, 8, 6, 3, false},
+		{12, 12, 4, 2, false},
+		{6, 12, 2, 2, false},
+		{5, 12, 0, 0, true},
+	}
+
+	for i, e := range exp {
+		count, power, err := timeSignature(e.count, e.value)
+		if e.err {
+			if err == nil {
+				t.Errorf("Expected to get an error at index %d", i)
+			}
+			continue
+		}
+
+		if err != nil || count != e.expCount || power != e.expPower {
+			t.Errorf("Expected to get %d/%d at index %d, got %d/%d (%v)", e.expCount, 1<<e.expPower, i, count, 1<<power, err)
+		}
+	}
+}
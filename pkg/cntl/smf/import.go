@@ -0,0 +1,171 @@
+package smf
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/song"
+)
+
+// SMF defaults until the first tempo and time signature events
+const (
+	defaultMicrosPerQuarter = 500000
+	defaultNoteCount        = 4
+	defaultNoteValue        = 4
+)
+
+// ImportOptions select what is imported from a Standard MIDI File
+type ImportOptions struct {
+	// Tracks are the indexes of the tracks the MIDI commands are imported from, all tracks if none are given
+	Tracks []int `json:"tracks"`
+	// Scenes maps note numbers to the DMX scenes they place instead of MIDI commands
+	Scenes map[uint8]string `json:"scenes"`
+}
+
+// Import converts the given file into a song.
+// Tempo and time signature events of all tracks become bar changes. As bar changes can only happen at the start of a
+// bar, changes within a bar are applied at the start of the next one. Markers become sections. Notes, control and
+// program changes of the selected tracks become MIDI commands, or DMX scenes if their note is mapped to a scene.
+func Import(f *File, opts ImportOptions) (*cntl.Song, error) {
+	tracks, err := selectTracks(f, opts.Tracks)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &cntl.Song{
+		MIDICommands:  []cntl.MIDICommand{},
+		FormatVersion: cntl.SongFormatVersion,
+	}
+	if len(f.Tracks) > 0 {
+		s.Name = f.Tracks[0].Name()
+	}
+
+	var timing []Event
+	for i, t := range f.Tracks {
+		for _, e := range t.Events {
+			switch {
+			case e.Status == StatusMeta && (e.Meta == MetaTempo || e.Meta == MetaTimeSignature):
+				timing = append(timing, e)
+
+			case e.Status == StatusMeta && e.Meta == MetaMarker:
+				s.Sections = append(s.Sections, cntl.SongSection{Name: string(e.Data), At: f.frame(e.Tick)})
+
+			case tracks[i]:
+				importEvent(s, f.frame(e.Tick), e, opts.Scenes)
+			}
+		}
+	}
+
+	if s.BarChanges, err = f.barChanges(timing); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(s.MIDICommands, func(i, j int) bool { return s.MIDICommands[i].At < s.MIDICommands[j].At })
+	sort.SliceStable(s.DMXScenes, func(i, j int) bool { return s.DMXScenes[i].At < s.DMXScenes[j].At })
+	sort.SliceStable(s.Sections, func(i, j int) bool { return s.Sections[i].At < s.Sections[j].At })
+
+	return s, nil
+}
+
+func selectTracks(f *File, indexes []int) (map[int]bool, error) {
+	tracks := make(map[int]bool)
+	for i := range f.Tracks {
+		tracks[i] = len(indexes) == 0
+	}
+
+	for _, i := range indexes {
+		if i < 0 || i >= len(f.Tracks) {
+			return nil, fmt.Errorf("file has no track %d, only %d tracks", i, len(f.Tracks))
+		}
+		tracks[i] = true
+	}
+
+	return tracks, nil
+}
+
+// frame returns the frame of the given tick, rounded to the nearest frame
+func (f *File) frame(tick uint64) uint64 {
+	return uint64(math.Round(float64(tick) * float64(cntl.RenderFrames) / float64(4*uint64(f.Division))))
+}
+
+// importEvent adds the given channel message at the given frame to the song, other events are ignored
+func importEvent(s *cntl.Song, at uint64, e Event, scenes map[uint8]string) {
+	switch e.Status & 0xF0 {
+	case 0x80, 0x90:
+		if id, ok := scenes[e.Data1]; ok {
+			// a note off or a note on with velocity 0 ends the note, scenes only need the start
+			if e.Status&0xF0 == 0x90 && e.Data2 > 0 {
+				s.DMXScenes = append(s.DMXScenes, cntl.DMXScenePosition{ID: id, At: at})
+			}
+			return
+		}
+
+	case 0xB0, 0xC0:
+
+	default:
+		return
+	}
+
+	s.MIDICommands = append(s.MIDICommands, cntl.MIDICommand{At: at, Status: e.Status, Data1: e.Data1, Data2: e.Data2})
+}
+
+// barChanges returns the bar changes of the given tempo and time signature events
+func (f *File) barChanges(timing []Event) ([]cntl.BarChange, error) {
+	sort.SliceStable(timing, func(i, j int) bool { return timing[i].Tick < timing[j].Tick })
+
+	bc := cntl.BarChange{BarParams: cntl.BarParams{
+		NoteCount: defaultNoteCount,
+		NoteValue: defaultNoteValue,
+		Speed:     uint16(math.Round(60e6 / defaultMicrosPerQuarter)),
+	}}
+
+	var bcs []cntl.BarChange
+	for i := 0; ; {
+		for ; i < len(timing) && f.frame(timing[i].Tick) <= bc.At; i++ {
+			if err := applyTiming(&bc.BarParams, timing[i]); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(bcs) == 0 || bcs[len(bcs)-1].BarParams != bc.BarParams {
+			bcs = append(bcs, bc)
+		}
+
+		if i >= len(timing) {
+			return bcs, nil
+		}
+
+		bc.At += song.CalcBarLength(&bc)
+	}
+}
+
+// applyTiming applies a tempo or time signature event to the given bar params
+func applyTiming(p *cntl.BarParams, e Event) error {
+	switch e.Meta {
+	case MetaTempo:
+		if len(e.Data) != 3 {
+			return fmt.Errorf("tempo at tick %d is invalid", e.Tick)
+		}
+
+		micros := uint64(e.Data[0])<<16 | uint64(e.Data[1])<<8 | uint64(e.Data[2])
+		if micros == 0 {
+			return fmt.Errorf("tempo at tick %d is invalid", e.Tick)
+		}
+		p.Speed = uint16(math.Max(1, math.Min(math.MaxUint16, math.Round(60e6/float64(micros)))))
+
+	case MetaTimeSignature:
+		if len(e.Data) < 2 || e.Data[1] > 7 {
+			return fmt.Errorf("time signature at tick %d is invalid", e.Tick)
+		}
+
+		count, value := e.Data[0], uint8(1)<<e.Data[1]
+		if count == 0 || !cntl.ValidNoteValue(value) {
+			return fmt.Errorf("time signature %d/%d at tick %d is not supported", count, value, e.Tick)
+		}
+		p.NoteCount, p.NoteValue = count, value
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package smf
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+)
+
+// importFile returns a file with a tempo map and cue notes, one tick is half a frame
+func importFile(t *testing.T) *File {
+	f, err := Read(bytes.NewReader(rawFile(96,
+		track(
+			event(0, 0xFF, 0x03, 0x04, 'S', 'o', 'n', 'g'),
+			event(0, 0xFF, 0x51, 0x03, 0x07, 0xA1, 0x20),
+			event(0, 0xFF, 0x58, 0x04, 4, 2, 24, 8),
+			event(384, 0xFF, 0x51, 0x03, 0x0F, 0x42, 0x40),
+			event(0, 0xFF, 0x06, 0x06, 'c', 'h', 'o', 'r', 'u', 's'),
+			event(384, 0xFF, 0x58, 0x04, 3, 2, 24, 8),
+			// within the bar, applied at the next one
+			event(96, 0xFF, 0x51, 0x03, 0x0A, 0x2C, 0x2B),
+		),
+		track(
+			event(0, 0x90, 60, 100),
+			event(96, 60, 0),
+			event(96, 0xB0, 7, 100),
+			event(0, 0xC0, 5),
+			event(8, 6),
+			event(0, 0xE0, 0, 64),
+			event(184, 0x90, 36, 127),
+			event(16, 0x80, 36, 0),
+		),
+		track(
+			event(48, 0x91, 62, 100),
+		),
+	)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return f
+}
+
+func TestImport(t *testing.T) {
+	s, err := Import(importFile(t), ImportOptions{Tracks: []int{1}, Scenes: map[uint8]string{36: "intro-scene"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if s.Name != "Song" {
+		t.Errorf("Expected to get name Song, got %q", s.Name)
+	}
+
+	expBarChanges := []cntl.BarChange{
+		{At: 0, BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 120}},
+		{At: 192, BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 60}},
+		{At: 384, BarParams: cntl.BarParams{NoteCount: 3, NoteValue: 4, Speed: 60}},
+		{At: 528, BarParams: cntl.BarParams{NoteCount: 3, NoteValue: 4, Speed: 90}},
+	}
+	if !reflect.DeepEqual(s.BarChanges, expBarChanges) {
+		t.Errorf("Expected to get bar changes %+v, got %+v", expBarChanges, s.BarChanges)
+	}
+
+	expMIDICommands := []cntl.MIDICommand{
+		{At: 0, Status: 0x90, Data1: 60, Data2: 100},
+		{At: 48, Status: 0x90, Data1: 60, Data2: 0},
+		{At: 96, Status: 0xB0, Data1: 7, Data2: 100},
+		{At: 96, Status: 0xC0, Data1: 5},
+		{At: 100, Status: 0xC0, Data1: 6},
+	}
+	if !reflect.DeepEqual(s.MIDICommands, expMIDICommands) {
+		t.Errorf("Expected to get MIDI commands %+v, got %+v", expMIDICommands, s.MIDICommands)
+	}
+
+	expScenes := []cntl.DMXScenePosition{{ID: "intro-scene", At: 192}}
+	if !reflect.DeepEqual(s.DMXScenes, expScenes) {
+		t.Errorf("Expected to get scenes %+v, got %+v", expScenes, s.DMXScenes)
+	}
+
+	expSections := []cntl.SongSection{{Name: "chorus", At: 192}}
+	if !reflect.DeepEqual(s.Sections, expSections) {
+		t.Errorf("Expected to get sections %+v, got %+v", expSections, s.Sections)
+	}
+}
+
+func TestImport_AllTracks(t *testing.T) {
+	s, err := Import(importFile(t), ImportOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(s.MIDICommands) != 8 || len(s.DMXScenes) != 0 {
+		t.Errorf("Expected to get 8 MIDI commands and no scenes, got %d and %d", len(s.MIDICommands), len(s.DMXScenes))
+	}
+}
+
+func TestImport_Errors(t *testing.T) {
+	exp := []struct {
+		file *File
+		opts ImportOptions
+	}{
+		{importFile(t), ImportOptions{Tracks: []int{3}}},
+		{&File{Division: 96, Tracks: []Track{{Events: []Event{{Status: StatusMeta, Meta: MetaTimeSignature, Data: []byte{4, 7}}}}}}, ImportOptions{}},
+		{&File{Division: 96, Tracks: []Track{{Events: []Event{{Status: StatusMeta, Meta: MetaTempo, Data: []byte{0, 0, 0}}}}}}, ImportOptions{}},
+	}
+
+	for i, e := range exp {
+		if _, err := Import(e.file, e.opts); err == nil {
+			t.Errorf("Expected to get an error at index %d", i)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+// Package smf reads and writes Standard MIDI Files and converts them from and to songs.
+package smf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// Status bytes of the events in a track which are no channel messages
+const (
+	StatusMeta      uint8 = 0xFF
+	StatusSysEx     uint8 = 0xF0
+	StatusSysExCont uint8 = 0xF7
+)
+
+// Meta event types
+const (
+	MetaTrackName     uint8 = 0x03
+	MetaMarker        uint8 = 0x06
+	MetaEndOfTrack    uint8 = 0x2F
+	MetaTempo         uint8 = 0x51
+	MetaTimeSignature uint8 = 0x58
+)
+
+// Errors returned when reading a file
+var (
+	ErrInvalidFile     = errors.New("invalid standard midi file")
+	ErrSMPTEDivision   = errors.New("standard midi files with SMPTE time division are not supported")
+	ErrUnexpectedEnd   = errors.New("unexpected end of standard midi file")
+	ErrNoRunningStatus = errors.New("data byte without running status")
+)
+
+// File is a Standard MIDI File
+type File struct {
+	Format uint16
+	// Division is the number of ticks per quarter note
+	Division uint16
+	Tracks   []Track
+}
+
+// Track is a track of a Standard MIDI File
+type Track struct {
+	Events []Event
+}
+
+// Event is an event of a track at the given tick since the start of the file.
+// Meta events have the status StatusMeta and their type in Meta, meta and system exclusive events keep their payload
+// in Data, channel messages their data bytes in Data1 and Data2.
+type Event struct {
+	Tick   uint64
+	Status uint8
+	Data1  uint8
+	Data2  uint8
+	Meta   uint8
+	Data   []byte
+}
+
+// Name returns the name of the track given by its first track name event
+func (t Track) Name() string {
+	for _, e := range t.Events {
+		if e.Status == StatusMeta && e.Meta == MetaTrackName {
+			return string(e.Data)
+		}
+	}
+
+	return ""
+}
+
+// Read reads a Standard MIDI File from the given reader
+func Read(r io.Reader) (*File, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	id, header, data, err := readChunk(data)
+	if err != nil {
+		return nil, err
+	}
+	if id != "MThd" || len(header) < 6 {
+		return nil, ErrInvalidFile
+	}
+
+	f := &File{
+		Format:   binary.BigEndian.Uint16(header[0:2]),
+		Division: binary.BigEndian.Uint16(header[4:6]),
+	}
+	tracks := int(binary.BigEndian.Uint16(header[2:4]))
+
+	if f.Division&0x8000 != 0 {
+		return nil, ErrSMPTEDivision
+	}
+	if f.Division == 0 {
+		return nil, ErrInvalidFile
+	}
+
+	for len(f.Tracks) < tracks {
+		var chunk []byte
+		id, chunk, data, err = readChunk(data)
+		if err != nil {
+			return nil, err
+		}
+
+		// unknown chunks have to be ignored
+		if id != "MTrk" {
+			continue
+		}
+
+		t, err := readTrack(chunk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read track %d: %v", len(f.Tracks), err)
+		}
+		f.Tracks = append(f.Tracks, t)
+	}
+
+	return f, nil
+}
+
+func readChunk(data []byte) (string, []byte, []byte, error) {
+	if len(data) < 8 {
+		return "", nil, nil, ErrUnexpectedEnd
+	}
+
+	size := int(binary.BigEndian.Uint32(data[4:8]))
+	if len(data) < 8+size {
+		return "", nil, nil, ErrUnexpectedEnd
+	}
+
+	return string(data[0:4]), data[8 : 8+size], data[8+size:], nil
+}
+
+func readTrack(data []byte) (Track, error) {
+	var t Track
+	var tick uint64
+	var running uint8
+	r := bytes.NewReader(data)
+
+	for r.Len() > 0 {
+		delta, err := readVarLen(r)
+		if err != nil {
+			return t, err
+		}
+		tick += delta
+
+		status, err := r.ReadByte()
+		if err != nil {
+			return t, ErrUnexpectedEnd
+		}
+
+		e := Event{Tick: tick, Status: status}
+		switch {
+		case status == StatusMeta:
+			if e.Meta, err = r.ReadByte(); err != nil {
+				return t, ErrUnexpectedEnd
+			}
+			if e.Data, err = readData(r); err != nil {
+				return t, err
+			}
+
+		case status == StatusSysEx || status == StatusSysExCont:
+			if e.Data, err = readData(r); err != nil {
+				return t, err
+			}
+
+		case status < 0x80:
+			// running status, the byte is the first data byte
+			if running == 0 {
+				return t, ErrNoRunningStatus
+			}
+			e.Status = running
+			if err := r.UnreadByte(); err != nil {
+				return t, err
+			}
+			fallthrough
+
+		default:
+			running = e.Status
+			if e.Data1, err = r.ReadByte(); err != nil {
+				return t, ErrUnexpectedEnd
+			}
+			if dataBytes(e.Status) == 2 {
+				if e.Data2, err = r.ReadByte(); err != nil {
+					return t, ErrUnexpectedEnd
+				}
+			}
+		}
+
+		// meta and system exclusive events cancel the running status
+		if status >= 0xF0 {
+			running = 0
+		}
+
+		t.Events = append(t.Events, e)
+		if e.Status == StatusMeta && e.Meta == MetaEndOfTrack {
+			break
+		}
+	}
+
+	return t, nil
+}
+
+// dataBytes returns the number of data bytes of the channel message with the given status
+func dataBytes(status uint8) int {
+	switch status & 0xF0 {
+	case 0xC0, 0xD0:
+		return 1
+	}
+
+	return 2
+}
+
+func readData(r *bytes.Reader) ([]byte, error) {
+	size, err := readVarLen(r)
+	if err != nil {
+		return nil, err
+	}
+	if size > uint64(r.Len()) {
+		return nil, ErrUnexpectedEnd
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, ErrUnexpectedEnd
+	}
+
+	return data, nil
+}
+
+// readVarLen reads a variable length quantity of up to four bytes
+func readVarLen(r *bytes.Reader) (uint64, error) {
+	var v uint64
+	for i := 0; i < 4; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, ErrUnexpectedEnd
+		}
+
+		v = v<<7 | uint64(b&0x7F)
+		if b&0x80 == 0 {
+			return v, nil
+		}
+	}
+
+	return 0, ErrInvalidFile
+}
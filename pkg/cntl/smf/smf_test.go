@@ -0,0 +1,122 @@
+package smf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// vlq encodes a variable length quantity
+func vlq(v uint64) []byte {
+	b := []byte{byte(v & 0x7F)}
+	for v >>= 7; v > 0; v >>= 7 {
+		b = append([]byte{byte(v&0x7F) | 0x80}, b...)
+	}
+	return b
+}
+
+func chunk(id string, data []byte) []byte {
+	size := make([]byte, 4)
+	binary.BigEndian.PutUint32(size, uint32(len(data)))
+	return append(append([]byte(id), size...), data...)
+}
+
+// rawFile returns a file with the given division and tracks, which are given as delta times followed by event bytes
+func rawFile(division uint16, tracks ...[]byte) []byte {
+	header := make([]byte, 6)
+	binary.BigEndian.PutUint16(header[0:2], 1)
+	binary.BigEndian.PutUint16(header[2:4], uint16(len(tracks)))
+	binary.BigEndian.PutUint16(header[4:6], division)
+
+	f := chunk("MThd", header)
+	for _, t := range tracks {
+		f = append(f, chunk("MTrk", t)...)
+	}
+	return f
+}
+
+// track joins the given events, each one given as its delta time and bytes
+func track(events ...[]byte) []byte {
+	var t []byte
+	for _, e := range events {
+		t = append(t, e...)
+	}
+	return append(t, 0x00, 0xFF, 0x2F, 0x00)
+}
+
+func event(delta uint64, b ...byte) []byte {
+	return append(vlq(delta), b...)
+}
+
+func TestRead(t *testing.T) {
+	data := rawFile(96,
+		track(event(0, 0xFF, 0x03, 0x04, 'S', 'o', 'n', 'g')),
+		track(
+			event(0, 0x90, 60, 100),
+			event(200, 60, 0),
+			event(0, 0xF0, 0x02, 0x01, 0xF7),
+			event(0, 0xC0, 5),
+			event(16384, 6),
+		),
+	)
+	// unknown chunks are skipped
+	data = append(data[:14], append(chunk("XFIH", []byte{1, 2, 3}), data[14:]...)...)
+
+	f, err := Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if f.Division != 96 || len(f.Tracks) != 2 || f.Tracks[0].Name() != "Song" {
+		t.Fatalf("Expected to get 2 tracks with division 96 and name Song, got %d with %d and %q", len(f.Tracks), f.Division, f.Tracks[0].Name())
+	}
+
+	exp := []Event{
+		{Tick: 0, Status: 0x90, Data1: 60, Data2: 100},
+		{Tick: 200, Status: 0x90, Data1: 60, Data2: 0},
+		{Tick: 200, Status: 0xF0, Data: []byte{0x01, 0xF7}},
+		{Tick: 200, Status: 0xC0, Data1: 5},
+		{Tick: 16584, Status: 0xC0, Data1: 6},
+		{Tick: 16584, Status: 0xFF, Meta: MetaEndOfTrack, Data: []byte{}},
+	}
+
+	events := f.Tracks[1].Events
+	if len(events) != len(exp) {
+		t.Fatalf("Expected to get %d events, got %d: %+v", len(exp), len(events), events)
+	}
+
+	for i, e := range exp {
+		a := events[i]
+		if a.Tick != e.Tick || a.Status != e.Status || a.Data1 != e.Data1 || a.Data2 != e.Data2 || a.Meta != e.Meta || !bytes.Equal(a.Data, e.Data) {
+			t.Errorf("Expected to get event %+v at index %d, got %+v", e, i, a)
+		}
+	}
+}
+
+func TestRead_Errors(t *testing.T) {
+	valid := rawFile(96, track(event(0, 0x90, 60, 100)))
+
+	exp := []struct {
+		data []byte
+		err  error
+	}{
+		{[]byte("MThd"), ErrUnexpectedEnd},
+		{chunk("RIFF", make([]byte, 6)), ErrInvalidFile},
+		{rawFile(0xE728, track()), ErrSMPTEDivision},
+		{valid[:len(valid)-3], ErrUnexpectedEnd},
+		{rawFile(96, track(event(0, 60, 100))), ErrNoRunningStatus},
+		{rawFile(96, track(event(0, 0xFF, 0x03, 0x10, 'a'))), ErrUnexpectedEnd},
+	}
+
+	for i, e := range exp {
+		_, err := Read(bytes.NewReader(e.data))
+		if err == nil {
+			t.Errorf("Expected to get an error at index %d", i)
+			continue
+		}
+
+		if err != e.err && !bytes.Contains([]byte(err.Error()), []byte(e.err.Error())) {
+			t.Errorf("Expected to get error %v at index %d, got %v", e.err, i, err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package smf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"sort"
+)
+
+// Write writes the given file as Standard MIDI File, the events of every track are written in order of their ticks
+func Write(w io.Writer, f *File) error {
+	header := make([]byte, 6)
+	binary.BigEndian.PutUint16(header[0:2], f.Format)
+	binary.BigEndian.PutUint16(header[2:4], uint16(len(f.Tracks)))
+	binary.BigEndian.PutUint16(header[4:6], f.Division)
+
+	buf := &bytes.Buffer{}
+	writeChunk(buf, "MThd", header)
+	for _, t := range f.Tracks {
+		writeChunk(buf, "MTrk", encodeTrack(t))
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func writeChunk(buf *bytes.Buffer, id string, data []byte) {
+	buf.WriteString(id)
+	_ = binary.Write(buf, binary.BigEndian, uint32(len(data)))
+	buf.Write(data)
+}
+
+func encodeTrack(t Track) []byte {
+	events := append([]Event{}, t.Events...)
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Tick < events[j].Tick })
+
+	buf := &bytes.Buffer{}
+	var tick uint64
+	for _, e := range events {
+		if e.Status == StatusMeta && e.Meta == MetaEndOfTrack {
+			continue
+		}
+
+		writeVarLen(buf, e.Tick-tick)
+		tick = e.Tick
+
+		buf.WriteByte(e.Status)
+		switch {
+		case e.Status == StatusMeta:
+			buf.WriteByte(e.Meta)
+			writeData(buf, e.Data)
+
+		case e.Status == StatusSysEx || e.Status == StatusSysExCont:
+			writeData(buf, e.Data)
+
+		default:
+			buf.WriteByte(e.Data1 & 0x7F)
+			if dataBytes(e.Status) == 2 {
+				buf.WriteByte(e.Data2 & 0x7F)
+			}
+		}
+	}
+
+	// every track ends with an end of track event, at the latest given one
+	end := tick
+	for _, e := range events {
+		if e.Status == StatusMeta && e.Meta == MetaEndOfTrack && e.Tick > end {
+			end = e.Tick
+		}
+	}
+	writeVarLen(buf, end-tick)
+	buf.Write([]byte{StatusMeta, MetaEndOfTrack, 0x00})
+
+	return buf.Bytes()
+}
+
+func writeData(buf *bytes.Buffer, data []byte) {
+	writeVarLen(buf, uint64(len(data)))
+	buf.Write(data)
+}
+
+// writeVarLen writes a variable length quantity
+func writeVarLen(buf *bytes.Buffer, v uint64) {
+	b := []byte{byte(v & 0x7F)}
+	for v >>= 7; v > 0; v >>= 7 {
+		b = append([]byte{byte(v&0x7F) | 0x80}, b...)
+	}
+
+	buf.Write(b)
+}
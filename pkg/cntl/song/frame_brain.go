@@ -2,37 +2,30 @@ package song
 
 import "github.com/StageAutoControl/controller/pkg/cntl"
 
+// frameBrain counts bars and notes, relative to the frame of the last bar change
 type frameBrain struct {
-	bar    uint16
-	note   uint8
-	lastBC *cntl.BarChange
-
-	framesEachNote uint64
-	currentFrame   uint64
+	bar      uint16
+	lastBC   *cntl.BarChange
+	barStart uint64
 }
 
-func (f *frameBrain) setBarChange(bc *cntl.BarChange) {
+// setBarChange starts the given bar change at the given frame, which always starts a new bar
+func (f *frameBrain) setBarChange(frame uint64, bc *cntl.BarChange) {
 	f.lastBC = bc
-	f.framesEachNote = CalcNoteLength(bc)
-	f.bar++
-	f.note = 1
-	f.currentFrame = 0
+	f.barStart = frame
 }
 
 func (f *frameBrain) update(frame uint64, cmd *cntl.Command) {
-	f.currentFrame++
-
-	if f.currentFrame >= f.framesEachNote {
-		f.note++
-		f.currentFrame = 0
+	cmd.Frame = frame
+	if f.lastBC == nil {
+		return
+	}
 
-		if f.note > f.lastBC.NoteCount {
-			f.note = 0
-			f.bar++
-		}
+	rel := frame - f.barStart
+	if rel%CalcBarLength(f.lastBC) == 0 {
+		f.bar++
 	}
 
-	cmd.Frame = frame
 	cmd.Bar = f.bar
-	cmd.Note = f.note
+	cmd.Note = uint8(rel/CalcNoteLength(f.lastBC)%uint64(f.lastBC.NoteCount)) + 1
 }
@@ -2,9 +2,12 @@ package song
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
+	"sort"
 
 	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/dmx"
 )
 
 // max returns the bigger of two given uint64 values
@@ -74,7 +77,54 @@ func ValidateBarChanges(bc map[uint64]cntl.BarChange) error {
 		return ErrSongMustHaveABarChangeAtFrame0
 	}
 
-	// @TODO Add validation of bar change distance, so that one can't add a BC if the previous bar isn't finished yet
+	for at, bc := range bc {
+		if bc.NoteCount == 0 {
+			return fmt.Errorf("bar change at frame %d needs a note count", at)
+		}
+
+		if !cntl.ValidNoteValue(bc.NoteValue) {
+			return fmt.Errorf("bar change at frame %d has note value %d, which does not divide the %d render frames of a bar", at, bc.NoteValue, cntl.RenderFrames)
+		}
+
+		if err := ValidateSwing(&bc); err != nil {
+			return err
+		}
+
+		if err := validateTempoRamp(&bc); err != nil {
+			return err
+		}
+	}
+
+	frames := make([]uint64, 0, len(bc))
+	for at := range bc {
+		frames = append(frames, at)
+	}
+	sort.Slice(frames, func(i, j int) bool { return frames[i] < frames[j] })
+
+	for i := 1; i < len(frames); i++ {
+		prev := bc[frames[i-1]]
+		if barLength := CalcBarLength(&prev); (frames[i]-frames[i-1])%barLength != 0 {
+			return fmt.Errorf("bar change at frame %d is not at the end of a bar of the bar change at frame %d", frames[i], frames[i-1])
+		}
+	}
+
+	return nil
+}
+
+func validateTempoRamp(bc *cntl.BarChange) error {
+	if bc.Ramp == nil {
+		return nil
+	}
+
+	if bc.Ramp.Speed == 0 || bc.Ramp.Bars == 0 {
+		return fmt.Errorf("tempo ramp of bar change at frame %d needs a speed and an amount of bars", bc.At)
+	}
+
+	if bc.Ramp.Ease != "" {
+		if _, err := dmx.GetEasingFunc(bc.Ramp.Ease); err != nil {
+			return fmt.Errorf("tempo ramp of bar change at frame %d is invalid: %v", bc.At, err)
+		}
+	}
 
 	return nil
 }
@@ -11,11 +11,12 @@ func TestCalcBarLength(t *testing.T) {
 		bc     cntl.BarChange
 		length uint64
 	}{
-		{cntl.BarChange{At: 0, BarParams: cntl.BarParams{NoteCount: 3, NoteValue: 4}}, 48},
-		{cntl.BarChange{At: 63, BarParams: cntl.BarParams{NoteCount: 12, NoteValue: 8}}, 96},
-		{cntl.BarChange{At: 10, BarParams: cntl.BarParams{NoteCount: 11, NoteValue: 4}}, 176},
-		{cntl.BarChange{At: 104, BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4}}, 64},
-		{cntl.BarChange{At: 5, BarParams: cntl.BarParams{NoteCount: 9, NoteValue: 8}}, 72},
+		{cntl.BarChange{At: 0, BarParams: cntl.BarParams{NoteCount: 3, NoteValue: 4}}, 144},
+		{cntl.BarChange{At: 63, BarParams: cntl.BarParams{NoteCount: 12, NoteValue: 8}}, 288},
+		{cntl.BarChange{At: 10, BarParams: cntl.BarParams{NoteCount: 11, NoteValue: 4}}, 528},
+		{cntl.BarChange{At: 104, BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4}}, 192},
+		{cntl.BarChange{At: 5, BarParams: cntl.BarParams{NoteCount: 9, NoteValue: 8}}, 216},
+		{cntl.BarChange{At: 0, BarParams: cntl.BarParams{NoteCount: 12, NoteValue: 12}}, 192},
 	}
 
 	for i, e := range exp {
@@ -24,3 +25,27 @@ func TestCalcBarLength(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateBarChanges(t *testing.T) {
+	exp := []struct {
+		bcs map[uint64]cntl.BarChange
+		err bool
+	}{
+		{map[uint64]cntl.BarChange{0: {BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4}}}, false},
+		{map[uint64]cntl.BarChange{0: {BarParams: cntl.BarParams{NoteCount: 12, NoteValue: 12}}}, false},
+		{map[uint64]cntl.BarChange{0: {BarParams: cntl.BarParams{NoteCount: 5, NoteValue: 5}}}, true},
+		{map[uint64]cntl.BarChange{0: {BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4}, Swing: &cntl.Swing{NoteValue: 8, Amount: 67}}}, false},
+		{map[uint64]cntl.BarChange{0: {BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4}, Swing: &cntl.Swing{NoteValue: 8, Amount: 40}}}, true},
+		{map[uint64]cntl.BarChange{0: {BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4}, Swing: &cntl.Swing{NoteValue: 7, Amount: 67}}}, true},
+		{map[uint64]cntl.BarChange{0: {BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4}, Ramp: &cntl.TempoRamp{Speed: 140, Bars: 4, Ease: cntl.EaseSineInOut}}}, false},
+		{map[uint64]cntl.BarChange{0: {BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4}, Ramp: &cntl.TempoRamp{Speed: 140}}}, true},
+		{map[uint64]cntl.BarChange{0: {BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4}, Ramp: &cntl.TempoRamp{Speed: 140, Bars: 4, Ease: "Wobbly"}}}, true},
+		{map[uint64]cntl.BarChange{192: {BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4}}}, true},
+	}
+
+	for i, e := range exp {
+		if err := ValidateBarChanges(e.bcs); (err != nil) != e.err {
+			t.Errorf("Expected to get error %v, got %v at index %d", e.err, err, i)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package song
+
+import (
+	"fmt"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+)
+
+// Migrate returns a copy of the given song converted to the current SongFormatVersion and whether anything changed.
+// Songs without a format version were stored on the legacy bar grid, so all their frames and ticks are scaled.
+func Migrate(s *cntl.Song) (*cntl.Song, bool, error) {
+	if s.FormatVersion >= cntl.SongFormatVersion {
+		return s, false, nil
+	}
+
+	factor := uint64(cntl.RenderFrames / cntl.LegacyRenderFrames)
+	migrated := *s
+	migrated.FormatVersion = cntl.SongFormatVersion
+
+	var err error
+	scale := func(pos string) (string, error) {
+		if pos == "" {
+			return "", nil
+		}
+
+		bar, beat, tick, err := ParsePosition(pos)
+		if err != nil {
+			return "", err
+		}
+
+		return FormatPosition(bar, beat, tick*factor), nil
+	}
+
+	migrated.BarChanges = make([]cntl.BarChange, len(s.BarChanges))
+	for i, bc := range s.BarChanges {
+		bc.At *= factor
+		if bc.Position, err = scale(bc.Position); err != nil {
+			return nil, false, fmt.Errorf("failed to migrate bar change %d: %v", i, err)
+		}
+		migrated.BarChanges[i] = bc
+	}
+
+	migrated.DMXScenes = make([]cntl.DMXScenePosition, len(s.DMXScenes))
+	for i, sp := range s.DMXScenes {
+		sp.At *= factor
+		if sp.Position, err = scale(sp.Position); err != nil {
+			return nil, false, fmt.Errorf("failed to migrate DMXScene %q: %v", sp.ID, err)
+		}
+		migrated.DMXScenes[i] = sp
+	}
+
+	migrated.MIDICommands = make([]cntl.MIDICommand, len(s.MIDICommands))
+	for i, mc := range s.MIDICommands {
+		mc.At *= factor
+		if mc.Position, err = scale(mc.Position); err != nil {
+			return nil, false, fmt.Errorf("failed to migrate MIDICommand %d: %v", i, err)
+		}
+		migrated.MIDICommands[i] = mc
+	}
+
+	migrated.Sections = make([]cntl.SongSection, len(s.Sections))
+	for i, sec := range s.Sections {
+		sec.At *= factor
+		if sec.Position, err = scale(sec.Position); err != nil {
+			return nil, false, fmt.Errorf("failed to migrate section %q: %v", sec.Name, err)
+		}
+		migrated.Sections[i] = sec
+	}
+
+	if migrated.End, err = scale(s.End); err != nil {
+		return nil, false, fmt.Errorf("failed to migrate song end: %v", err)
+	}
+
+	return &migrated, true, nil
+}
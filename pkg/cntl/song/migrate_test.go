@@ -0,0 +1,59 @@
+package song
+
+import (
+	"testing"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+)
+
+func TestMigrate(t *testing.T) {
+	legacy := &cntl.Song{
+		ID: "legacy",
+		BarChanges: []cntl.BarChange{
+			{At: 0, BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 120}},
+			{At: 128, BarParams: cntl.BarParams{NoteCount: 3, NoteValue: 4}},
+		},
+		DMXScenes:    []cntl.DMXScenePosition{{At: 16, ID: "scene"}, {Position: "2.1.4", ID: "scene"}},
+		MIDICommands: []cntl.MIDICommand{{At: 8, Status: 0x90}},
+		Sections:     []cntl.SongSection{{At: 64, Name: "verse"}},
+		End:          "4.1.2",
+	}
+
+	migrated, changed, err := Migrate(legacy)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !changed {
+		t.Fatal("Expected the legacy song to be changed")
+	}
+
+	exp := []struct {
+		name     string
+		got, exp uint64
+	}{
+		{"bar change", migrated.BarChanges[1].At, 384},
+		{"scene", migrated.DMXScenes[0].At, 48},
+		{"midi command", migrated.MIDICommands[0].At, 24},
+		{"section", migrated.Sections[0].At, 192},
+	}
+
+	for i, e := range exp {
+		if e.got != e.exp {
+			t.Errorf("Expected %s to be at frame %d, got %d at index %d", e.name, e.exp, e.got, i)
+		}
+	}
+
+	if migrated.DMXScenes[1].Position != "2.1.12" || migrated.End != "4.1.6" {
+		t.Errorf("Expected the ticks of positions to be scaled, got %q and %q", migrated.DMXScenes[1].Position, migrated.End)
+	}
+	if migrated.FormatVersion != cntl.SongFormatVersion {
+		t.Errorf("Expected format version %d, got %d", cntl.SongFormatVersion, migrated.FormatVersion)
+	}
+	if legacy.BarChanges[1].At != 128 || legacy.FormatVersion != 0 {
+		t.Errorf("Expected the legacy song to be left untouched")
+	}
+
+	if _, changed, err := Migrate(migrated); err != nil || changed {
+		t.Errorf("Expected a migrated song not to be changed again, got %v, %v", changed, err)
+	}
+}
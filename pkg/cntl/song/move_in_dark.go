@@ -0,0 +1,119 @@
+package song
+
+import "github.com/StageAutoControl/controller/pkg/cntl"
+
+// movingHead holds the resolved channels of a moving device that are relevant for move in dark
+type movingHead struct {
+	universe cntl.DMXUniverse
+	dimmer   cntl.DMXChannel
+	channels []cntl.DMXChannel
+}
+
+func (h *movingHead) isPrepared(c cntl.DMXCommand) bool {
+	if c.Universe != h.universe {
+		return false
+	}
+
+	for _, ch := range h.channels {
+		if ch == c.Channel {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resolveMovingHeads returns all moving devices with a dimmer channel, as the dimmer is what tells us whether they are dark
+func resolveMovingHeads(ds *cntl.DataStore) []*movingHead {
+	var heads []*movingHead
+
+	for _, d := range ds.DMXDevices {
+		dt, ok := ds.DMXDeviceTypes[d.TypeID]
+		if !ok || !dt.Moving || !dt.DimmerEnabled {
+			continue
+		}
+
+		// the channels are resolved from the device type directly, as moving heads don't need to have LEDs
+		h := &movingHead{universe: d.Universe, dimmer: d.StartChannel + dt.DimmerChannel}
+		h.channels = append(h.channels, d.StartChannel+dt.PanChannel, d.StartChannel+dt.TiltChannel)
+
+		if dt.PanFineEnabled {
+			h.channels = append(h.channels, d.StartChannel+dt.PanFineChannel)
+		}
+		if dt.TiltFineEnabled {
+			h.channels = append(h.channels, d.StartChannel+dt.TiltFineChannel)
+		}
+
+		for _, led := range dt.LEDs {
+			for _, c := range []cntl.DMXChannel{led.Red, led.Green, led.Blue, led.White} {
+				h.channels = append(h.channels, d.StartChannel+c)
+			}
+		}
+
+		heads = append(heads, h)
+	}
+
+	return heads
+}
+
+// MoveInDark copies position and color changes of moving heads that are dark before the change into the dark gap,
+// at most leadTime notes ahead, so the heads don't visibly swing into position once they are lit again.
+// A head is only moved from the frame after its dimmer went to 0, so a fading dimmer is dark before it moves.
+// The commands at the original frame are kept, which leaves the authored scenes untouched. Gobo changes are not prepared.
+func MoveInDark(ds *cntl.DataStore, cs []cntl.Command, leadTime uint16) {
+	for _, h := range resolveMovingHeads(ds) {
+		prepareMovingHead(h, cs, leadTime)
+	}
+}
+
+func prepareMovingHead(h *movingHead, cs []cntl.Command, leadTime uint16) {
+	// a device is dark until the first command sets its dimmer
+	dark := true
+	var darkSince, noteLength uint64
+	lastWrite := make(map[cntl.DMXChannel]uint64)
+
+	for frame := uint64(0); frame < uint64(len(cs)); frame++ {
+		if bc := cs[frame].BarChange; bc != nil {
+			noteLength = CalcNoteLength(bc)
+		}
+
+		lead := uint64(leadTime) * noteLength
+		dimmer := -1
+
+		for _, c := range cs[frame].DMXCommands {
+			if c.Universe == h.universe && c.Channel == h.dimmer {
+				dimmer = int(c.Value.Value)
+				continue
+			}
+
+			if !h.isPrepared(c) {
+				continue
+			}
+
+			if dark {
+				at := darkSince
+				if frame > lead && frame-lead > at {
+					at = frame - lead
+				}
+				if w, ok := lastWrite[c.Channel]; ok && w+1 > at {
+					at = w + 1
+				}
+
+				if at < frame {
+					cs[at].DMXCommands = append(cs[at].DMXCommands, c)
+				}
+			}
+
+			lastWrite[c.Channel] = frame
+		}
+
+		// the dimmer state of this frame only applies to the following frames,
+		// changes in the same frame as the dimmer are what we prepare for
+		if dimmer == 0 && !dark {
+			dark = true
+			darkSince = frame + 1
+		} else if dimmer > 0 {
+			dark = false
+		}
+	}
+}
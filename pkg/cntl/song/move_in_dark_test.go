@@ -0,0 +1,115 @@
+package song
+
+import (
+	"testing"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/internal/fixtures"
+)
+
+func movingHeadDataStore() *cntl.DataStore {
+	ds := cntl.NewStore()
+	ds.DMXDeviceTypes["moving-head"] = &cntl.DMXDeviceType{
+		ID:            "moving-head",
+		DimmerEnabled: true,
+		DimmerChannel: 0,
+		Moving:        true,
+		PanChannel:    1,
+		TiltChannel:   2,
+		LEDs:          []cntl.LED{{Red: 3, Green: 4, Blue: 5, White: 6}},
+	}
+	ds.DMXDevices["head-1"] = &cntl.DMXDevice{
+		ID:           "head-1",
+		TypeID:       "moving-head",
+		Universe:     1,
+		StartChannel: 100,
+	}
+
+	return ds
+}
+
+func TestMoveInDark(t *testing.T) {
+	dimmerOn := cntl.DMXCommand{Universe: 1, Channel: 100, Value: *fixtures.Value255}
+	dimmerOff := cntl.DMXCommand{Universe: 1, Channel: 100, Value: *fixtures.Value0}
+	pan := cntl.DMXCommand{Universe: 1, Channel: 101, Value: *fixtures.Value127}
+	otherUniverse := cntl.DMXCommand{Universe: 2, Channel: 101, Value: *fixtures.Value127}
+
+	exp := []struct {
+		dimmerOffAt uint64
+		cmd         cntl.DMXCommand
+		preparedAt  int
+	}{
+		{dimmerOffAt: 30, cmd: pan, preparedAt: 204},
+		{dimmerOffAt: 270, cmd: pan, preparedAt: 271},
+		{dimmerOffAt: 299, cmd: pan, preparedAt: -1},
+		{dimmerOffAt: 360, cmd: pan, preparedAt: -1},
+		{dimmerOffAt: 30, cmd: otherUniverse, preparedAt: -1},
+	}
+
+	for i, e := range exp {
+		cs := makeCommandArray(384)
+		cs[0].BarChange = &cntl.BarChange{BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 120}}
+		cs[0].DMXCommands = cntl.DMXCommands{dimmerOn}
+		cs[e.dimmerOffAt].DMXCommands = cntl.DMXCommands{dimmerOff}
+		cs[300].DMXCommands = append(cs[300].DMXCommands, e.cmd, dimmerOn)
+
+		MoveInDark(movingHeadDataStore(), cs, 2)
+
+		if !cs[300].DMXCommands.Contains(e.cmd) {
+			t.Errorf("Expected the original command to be left untouched at index %d", i)
+		}
+
+		for frame := 1; frame < 300; frame++ {
+			has := cs[frame].DMXCommands.Contains(e.cmd)
+			if frame == e.preparedAt && !has {
+				t.Errorf("Expected command to be prepared at frame %d at index %d", frame, i)
+			}
+			if frame != e.preparedAt && has {
+				t.Errorf("Expected no prepared command at frame %d at index %d", frame, i)
+			}
+		}
+	}
+}
+
+func TestResolveMovingHeads(t *testing.T) {
+	ds := movingHeadDataStore()
+	ds.DMXDeviceTypes["spot"] = &cntl.DMXDeviceType{
+		ID:              "spot",
+		DimmerEnabled:   true,
+		DimmerChannel:   0,
+		Moving:          true,
+		PanChannel:      1,
+		PanFineEnabled:  true,
+		PanFineChannel:  0,
+		TiltChannel:     3,
+		TiltFineEnabled: true,
+		TiltFineChannel: 4,
+	}
+	ds.DMXDevices["spot-1"] = &cntl.DMXDevice{ID: "spot-1", TypeID: "spot", Universe: 2, StartChannel: 10}
+
+	exp := map[cntl.DMXUniverse][]cntl.DMXChannel{
+		1: {101, 102, 103, 104, 105, 106},
+		2: {11, 13, 10, 14},
+	}
+
+	heads := resolveMovingHeads(ds)
+	if len(heads) != len(exp) {
+		t.Fatalf("Expected to get %d moving heads, got %d", len(exp), len(heads))
+	}
+
+	for _, h := range heads {
+		channels := exp[h.universe]
+		if h.dimmer != channels[0]-1 {
+			t.Errorf("Expected dimmer of universe %d to be %d, got %d", h.universe, channels[0]-1, h.dimmer)
+		}
+
+		if len(h.channels) != len(channels) {
+			t.Fatalf("Expected to get channels %v in universe %d, got %v", channels, h.universe, h.channels)
+		}
+		for i := range channels {
+			if h.channels[i] != channels[i] {
+				t.Errorf("Expected to get channel %d, got %d at index %d", channels[i], h.channels[i], i)
+			}
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package song
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+)
+
+// ParsePosition parses a song position in the form of bar.beat or bar.beat.tick.
+// Bars and beats start at 1, ticks are render frames within the beat and start at 0.
+func ParsePosition(pos string) (bar, beat, tick uint64, err error) {
+	parts := strings.Split(pos, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, 0, 0, fmt.Errorf("position %q needs to be in the form of bar.beat or bar.beat.tick", pos)
+	}
+
+	values := make([]uint64, 3)
+	for i, part := range parts {
+		if values[i], err = strconv.ParseUint(part, 10, 64); err != nil {
+			return 0, 0, 0, fmt.Errorf("position %q needs to be in the form of bar.beat or bar.beat.tick", pos)
+		}
+	}
+
+	if values[0] == 0 || values[1] == 0 {
+		return 0, 0, 0, fmt.Errorf("bar and beat of position %q start at 1", pos)
+	}
+
+	return values[0], values[1], values[2], nil
+}
+
+// FormatPosition formats the given bar, beat and tick to a song position
+func FormatPosition(bar, beat, tick uint64) string {
+	if tick == 0 {
+		return fmt.Sprintf("%d.%d", bar, beat)
+	}
+
+	return fmt.Sprintf("%d.%d.%d", bar, beat, tick)
+}
+
+// timelineEntry is a bar change with the bar number it starts at
+type timelineEntry struct {
+	bc  cntl.BarChange
+	bar uint64
+}
+
+// timeline converts between frames and song positions, based on the bar changes of a song
+type timeline []timelineEntry
+
+// newTimeline resolves the frames of the given bar changes, which may be given in any order.
+// The first bar change needs to be at frame 0 and every other one at the end of a bar of the previous one.
+func newTimeline(bcs []cntl.BarChange) (timeline, error) {
+	// bar changes given in frames are sorted by frame, the ones given as position by their bar,
+	// the position of the next one is resolved against the bar changes before it to merge both
+	var frames, positions []int
+	keys := make([]uint64, len(bcs))
+	for i, bc := range bcs {
+		if !cntl.ValidNoteValue(bc.NoteValue) || bc.NoteCount == 0 {
+			return nil, fmt.Errorf("bar change %d has an invalid time signature %d/%d", i, bc.NoteCount, bc.NoteValue)
+		}
+
+		if bc.Position == "" {
+			keys[i] = bc.At
+			frames = append(frames, i)
+			continue
+		}
+
+		bar, beat, tick, err := ParsePosition(bc.Position)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve position of bar change %d: %v", i, err)
+		}
+		if beat != 1 || tick != 0 {
+			return nil, fmt.Errorf("bar change %d at position %q needs to be at the start of a bar", i, bc.Position)
+		}
+		keys[i] = bar
+		positions = append(positions, i)
+	}
+
+	sort.SliceStable(frames, func(a, b int) bool { return keys[frames[a]] < keys[frames[b]] })
+	sort.SliceStable(positions, func(a, b int) bool { return keys[positions[a]] < keys[positions[b]] })
+
+	var t timeline
+	for len(frames) > 0 || len(positions) > 0 {
+		var i int
+		var at uint64
+
+		switch {
+		case len(positions) == 0 || len(t) == 0 && len(frames) > 0 && keys[frames[0]] == 0:
+			i, frames = frames[0], frames[1:]
+			at = keys[i]
+
+		case len(t) == 0:
+			i, positions = positions[0], positions[1:]
+			if keys[i] != 1 {
+				return nil, ErrSongMustHaveABarChangeAtFrame0
+			}
+
+		default:
+			var err error
+			if at, err = t.toFrame(bcs[positions[0]].Position); err != nil {
+				return nil, fmt.Errorf("failed to resolve position of bar change %d: %v", positions[0], err)
+			}
+
+			if len(frames) > 0 && keys[frames[0]] <= at {
+				i, frames = frames[0], frames[1:]
+				at = keys[i]
+			} else {
+				i, positions = positions[0], positions[1:]
+			}
+		}
+
+		bc := bcs[i]
+		bc.At = at
+
+		if len(t) == 0 {
+			if at != 0 {
+				return nil, ErrSongMustHaveABarChangeAtFrame0
+			}
+
+			t = append(t, timelineEntry{bc: bc, bar: 1})
+			continue
+		}
+
+		prev := t[len(t)-1]
+		barLength := CalcBarLength(&prev.bc)
+		if (at-prev.bc.At)%barLength != 0 {
+			return nil, fmt.Errorf("bar change %d at frame %d is not at the end of a bar of the bar change at frame %d", i, at, prev.bc.At)
+		}
+
+		t = append(t, timelineEntry{bc: bc, bar: prev.bar + (at-prev.bc.At)/barLength})
+	}
+
+	return t, nil
+}
+
+// entryAtBar returns the last entry starting at or before the given bar
+func (t timeline) entryAtBar(bar uint64) timelineEntry {
+	e := t[0]
+	for _, entry := range t {
+		if entry.bar > bar {
+			break
+		}
+		e = entry
+	}
+
+	return e
+}
+
+// entryAtFrame returns the last entry starting at or before the given frame
+func (t timeline) entryAtFrame(frame uint64) timelineEntry {
+	e := t[0]
+	for _, entry := range t {
+		if entry.bc.At > frame {
+			break
+		}
+		e = entry
+	}
+
+	return e
+}
+
+func (t timeline) toFrame(pos string) (uint64, error) {
+	bar, beat, tick, err := ParsePosition(pos)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(t) == 0 {
+		return 0, fmt.Errorf("cannot resolve position %q without a bar change", pos)
+	}
+
+	e := t.entryAtBar(bar)
+	noteLength := CalcNoteLength(&e.bc)
+	if beat > uint64(e.bc.NoteCount) {
+		return 0, fmt.Errorf("position %q is beyond the %d beats of its bar", pos, e.bc.NoteCount)
+	}
+	if tick >= noteLength {
+		return 0, fmt.Errorf("position %q is beyond the %d ticks of its beat", pos, noteLength)
+	}
+
+	return e.bc.At + (bar-e.bar)*CalcBarLength(&e.bc) + (beat-1)*noteLength + tick, nil
+}
+
+func (t timeline) toPosition(frame uint64) string {
+	e := t.entryAtFrame(frame)
+	barLength := CalcBarLength(&e.bc)
+	noteLength := CalcNoteLength(&e.bc)
+
+	rel := frame - e.bc.At
+	rest := rel % barLength
+
+	return FormatPosition(e.bar+rel/barLength, rest/noteLength+1, rest%noteLength)
+}
+
+// ResolvePositions returns a copy of the given song where all positions given in bars and beats are converted to frames
+// and all bar changes have a speed
+func ResolvePositions(s *cntl.Song) (*cntl.Song, error) {
+	t, err := newTimeline(s.BarChanges)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := *s
+	resolved.BarChanges = make([]cntl.BarChange, len(t))
+	for i, e := range t {
+		// a bar change without a speed keeps the tempo of the previous one, including where its ramp ended
+		if e.bc.Speed == 0 {
+			if i == 0 {
+				return nil, fmt.Errorf("first bar change needs a speed")
+			}
+
+			prev := resolved.BarChanges[i-1]
+			e.bc.Speed = prev.Speed
+			if prev.Ramp != nil {
+				e.bc.Speed = prev.Ramp.Speed
+			}
+		}
+
+		resolved.BarChanges[i] = e.bc
+	}
+
+	resolved.DMXScenes = make([]cntl.DMXScenePosition, len(s.DMXScenes))
+	for i, sp := range s.DMXScenes {
+		if sp.Position != "" {
+			if sp.At, err = t.toFrame(sp.Position); err != nil {
+				return nil, fmt.Errorf("failed to resolve position of DMXScene %q: %v", sp.ID, err)
+			}
+		}
+		resolved.DMXScenes[i] = sp
+	}
+
+	resolved.MIDICommands = make([]cntl.MIDICommand, len(s.MIDICommands))
+	for i, mc := range s.MIDICommands {
+		if mc.Position != "" {
+			if mc.At, err = t.toFrame(mc.Position); err != nil {
+				return nil, fmt.Errorf("failed to resolve position of MIDICommand %d: %v", i, err)
+			}
+		}
+		resolved.MIDICommands[i] = mc
+	}
+
+	resolved.Sections = make([]cntl.SongSection, len(s.Sections))
+	for i, sec := range s.Sections {
+		if sec.Position != "" {
+			if sec.At, err = t.toFrame(sec.Position); err != nil {
+				return nil, fmt.Errorf("failed to resolve position of section %q: %v", sec.Name, err)
+			}
+		}
+		resolved.Sections[i] = sec
+	}
+
+	return &resolved, nil
+}
+
+// ResolveEnd returns the frame the given song ends at, or false if it has no end
+func ResolveEnd(s *cntl.Song) (uint64, bool, error) {
+	if s.End == "" {
+		return 0, false, nil
+	}
+
+	t, err := newTimeline(s.BarChanges)
+	if err != nil {
+		return 0, false, err
+	}
+
+	end, err := t.toFrame(s.End)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return end, true, nil
+}
+
+// ConvertToPositions returns a copy of the given song where all frame positions are replaced by positions in bars and beats
+func ConvertToPositions(s *cntl.Song) (*cntl.Song, error) {
+	resolved, err := ResolvePositions(s)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := newTimeline(resolved.BarChanges)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range resolved.BarChanges {
+		resolved.BarChanges[i].Position = t.toPosition(resolved.BarChanges[i].At)
+		resolved.BarChanges[i].At = 0
+	}
+
+	for i := range resolved.DMXScenes {
+		resolved.DMXScenes[i].Position = t.toPosition(resolved.DMXScenes[i].At)
+		resolved.DMXScenes[i].At = 0
+	}
+
+	for i := range resolved.MIDICommands {
+		resolved.MIDICommands[i].Position = t.toPosition(resolved.MIDICommands[i].At)
+		resolved.MIDICommands[i].At = 0
+	}
+
+	for i := range resolved.Sections {
+		resolved.Sections[i].Position = t.toPosition(resolved.Sections[i].At)
+		resolved.Sections[i].At = 0
+	}
+
+	return resolved, nil
+}
@@ -0,0 +1,130 @@
+package song
+
+import (
+	"testing"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/internal/fixtures"
+)
+
+func TestResolvePositions(t *testing.T) {
+	s := &cntl.Song{
+		BarChanges: []cntl.BarChange{
+			{Position: "1.1", BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 120}},
+			{Position: "5.1", BarParams: cntl.BarParams{NoteCount: 12, NoteValue: 12}},
+			{Position: "4.1", BarParams: cntl.BarParams{NoteCount: 7, NoteValue: 8}},
+		},
+		DMXScenes: []cntl.DMXScenePosition{
+			{Position: "2.2"},
+			{Position: "2.2.12"},
+			{Position: "3.1"},
+			{Position: "4.7"},
+			{Position: "5.2"},
+			{At: 1000},
+		},
+	}
+
+	exp := []uint64{240, 252, 384, 720, 760, 1000}
+
+	resolved, err := ResolvePositions(s)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if resolved.BarChanges[1].At != 576 || resolved.BarChanges[2].At != 744 {
+		t.Errorf("Expected bar changes at frames 576 and 744, got %d and %d", resolved.BarChanges[1].At, resolved.BarChanges[2].At)
+	}
+
+	for i, e := range exp {
+		if resolved.DMXScenes[i].At != e {
+			t.Errorf("Expected to get frame %d at index %d, got %d", e, i, resolved.DMXScenes[i].At)
+		}
+	}
+
+	if s.DMXScenes[0].At != 0 {
+		t.Errorf("Expected the given song to be left untouched")
+	}
+}
+
+func TestResolvePositions_Invalid(t *testing.T) {
+	bcs := []cntl.BarChange{{BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 120}}}
+	exp := []string{"1", "0.1", "1.0", "1.5", "1.1.48", "a.b"}
+
+	for i, e := range exp {
+		s := &cntl.Song{BarChanges: bcs, MIDICommands: []cntl.MIDICommand{{Position: e}}}
+		if _, err := ResolvePositions(s); err == nil {
+			t.Errorf("Expected to get an error for position %q at index %d", e, i)
+		}
+	}
+}
+
+func TestResolvePositions_BarChanges(t *testing.T) {
+	fourFour := cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 120}
+	sevenEight := cntl.BarParams{NoteCount: 7, NoteValue: 8}
+
+	exp := []struct {
+		bcs    []cntl.BarChange
+		at     []uint64
+		hasErr bool
+	}{
+		{bcs: []cntl.BarChange{{At: 384, BarParams: sevenEight}, {At: 0, BarParams: fourFour}}, at: []uint64{0, 384}},
+		{bcs: []cntl.BarChange{{At: 552, BarParams: fourFour}, {Position: "3.1", BarParams: sevenEight}, {BarParams: fourFour}}, at: []uint64{0, 384, 552}},
+		{bcs: []cntl.BarChange{{At: 192, BarParams: fourFour}}, hasErr: true},
+		{bcs: []cntl.BarChange{{Position: "2.1", BarParams: fourFour}}, hasErr: true},
+		{bcs: []cntl.BarChange{{BarParams: fourFour}, {At: 96, BarParams: sevenEight}}, hasErr: true},
+		{bcs: []cntl.BarChange{{BarParams: fourFour}, {Position: "2.3", BarParams: sevenEight}}, hasErr: true},
+	}
+
+	for i, e := range exp {
+		resolved, err := ResolvePositions(&cntl.Song{BarChanges: e.bcs})
+		if e.hasErr {
+			if err == nil {
+				t.Errorf("Expected to get an error at index %d", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unexpected error at index %d: %v", i, err)
+			continue
+		}
+
+		for j, at := range e.at {
+			if resolved.BarChanges[j].At != at {
+				t.Errorf("Expected bar change %d to be at frame %d at index %d, got %d", j, at, i, resolved.BarChanges[j].At)
+			}
+		}
+	}
+}
+
+func TestConvertToPositions(t *testing.T) {
+	s := fixtures.DataStore().Songs["3c1065c8-0b14-11e7-96eb-5b134621c411"]
+
+	converted, err := ConvertToPositions(s)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expBCs := []string{"1.1", "9.1", "23.1", "31.1"}
+	for i, e := range expBCs {
+		if converted.BarChanges[i].Position != e {
+			t.Errorf("Expected bar change at position %q at index %d, got %q", e, i, converted.BarChanges[i].Position)
+		}
+	}
+
+	resolved, err := ResolvePositions(converted)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for i := range s.BarChanges {
+		if resolved.BarChanges[i].At != s.BarChanges[i].At {
+			t.Errorf("Expected bar change %d to be at frame %d, got %d", i, s.BarChanges[i].At, resolved.BarChanges[i].At)
+		}
+	}
+
+	for i := range s.DMXScenes {
+		if resolved.DMXScenes[i].At != s.DMXScenes[i].At {
+			t.Errorf("Expected scene %d to be at frame %d, got %d", i, s.DMXScenes[i].At, resolved.DMXScenes[i].At)
+		}
+	}
+}
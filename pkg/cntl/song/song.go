@@ -16,6 +16,13 @@ func Render(ds *cntl.DataStore, songID string) ([]cntl.Command, error) {
 		return nil, fmt.Errorf("cannot find Song %q", songID)
 	}
 
+	s, err := ResolvePositions(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve positions: %v", err)
+	}
+
+	ds = dmx.OverrideColorVariables(ds, s.ColorVariables)
+
 	scs, err := dmx.StreamlineScenes(ds, s)
 	if err != nil {
 		return nil, err
@@ -28,18 +35,10 @@ func Render(ds *cntl.DataStore, songID string) ([]cntl.Command, error) {
 
 	mcs := midi.StreamlineMidiCommands(s)
 
-	fb := &frameBrain{}
 	numFrames := max(maxKey(scs), maxKey(mcs)) + 1
 	cs := makeCommandArray(numFrames)
 
 	for frame := uint64(0); frame < numFrames; frame++ {
-		if bc, ok := bcs[frame]; ok {
-			cs[frame].BarChange = &bc
-			fb.setBarChange(&bc)
-		}
-
-		fb.update(frame, &cs[frame])
-
 		if mc, ok := mcs[frame]; ok {
 			cs[frame].MIDICommands = append(cs[frame].MIDICommands, mc...)
 		}
@@ -71,5 +70,22 @@ func Render(ds *cntl.DataStore, songID string) ([]cntl.Command, error) {
 		}
 	}
 
+	// scenes may last longer than the last scene start, so the frames are counted once everything is rendered
+	fb := &frameBrain{}
+	for frame := range cs {
+		if bc, ok := bcs[uint64(frame)]; ok {
+			cs[frame].BarChange = &bc
+			fb.setBarChange(uint64(frame), &bc)
+		}
+
+		fb.update(uint64(frame), &cs[frame])
+	}
+
+	cs = Swing(cs)
+
+	if s.MoveInDark != nil {
+		MoveInDark(ds, cs, s.MoveInDark.LeadTime)
+	}
+
 	return cs, nil
 }
@@ -29,9 +29,9 @@ func TestStreamlineBarChanges(t *testing.T) {
 			s: ds.Songs["3c1065c8-0b14-11e7-96eb-5b134621c411"],
 			m: map[uint64]cntl.BarChange{
 				0:    {At: 0, BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 160}},
-				512:  {At: 512, BarParams: cntl.BarParams{NoteCount: 3, NoteValue: 4}},
-				1184: {At: 1184, BarParams: cntl.BarParams{NoteCount: 7, NoteValue: 8}},
-				1632: {At: 1632, BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4}},
+				1536: {At: 1536, BarParams: cntl.BarParams{NoteCount: 3, NoteValue: 4}},
+				3552: {At: 3552, BarParams: cntl.BarParams{NoteCount: 7, NoteValue: 8}},
+				4896: {At: 4896, BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4}},
 			},
 		},
 	}
@@ -55,3 +55,77 @@ func TestStreamlineBarChanges(t *testing.T) {
 
 	}
 }
+
+func TestRender_ColorVariables(t *testing.T) {
+	fix := fixtures.DataStore()
+	s := *fix.Songs["3c1065c8-0b14-11e7-96eb-5b134621c411"]
+	s.ColorVariables = []cntl.DMXColorVariable{{Name: "Red255", Green: fixtures.Value255}}
+
+	ds := *fix
+	ds.Songs = map[string]*cntl.Song{s.ID: &s}
+
+	cs, err := Render(&ds, s.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	green := cntl.DMXCommand{Universe: 1, Channel: 223, Value: *fixtures.Value255}
+	if !cs[0].DMXCommands.Contains(green) {
+		t.Errorf("Expected the overridden color to be rendered, got %+v", cs[0].DMXCommands)
+	}
+
+	if c := fix.DMXColorVariables["4b848ea8-5094-4509-a067-09a0e568220d"]; c.Green != nil {
+		t.Errorf("Expected the global color variable to be left untouched, got %+v", c)
+	}
+}
+
+func TestRender_FrameState(t *testing.T) {
+	ds := cntl.NewStore()
+	ds.Songs["signatures"] = &cntl.Song{
+		ID: "signatures",
+		BarChanges: []cntl.BarChange{
+			{At: 0, BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 120}},
+			{At: 384, BarParams: cntl.BarParams{NoteCount: 3, NoteValue: 4}},
+			{At: 672, BarParams: cntl.BarParams{NoteCount: 7, NoteValue: 8, Speed: 90}, Ramp: &cntl.TempoRamp{Speed: 100, Bars: 1}},
+			{At: 840, BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4}},
+		},
+		MIDICommands: []cntl.MIDICommand{{At: 1100, Status: 0x90, Data1: 60, Data2: 100}},
+	}
+
+	cs, err := Render(ds, "signatures")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	exp := []struct {
+		frame uint64
+		bar   uint16
+		note  uint8
+	}{
+		{0, 1, 1}, {47, 1, 1}, {48, 1, 2}, {191, 1, 4},
+		{192, 2, 1}, {383, 2, 4},
+		{384, 3, 1}, {432, 3, 2}, {527, 3, 3},
+		{528, 4, 1}, {671, 4, 3},
+		{672, 5, 1}, {696, 5, 2}, {839, 5, 7},
+		{840, 6, 1}, {1031, 6, 4}, {1032, 7, 1}, {1100, 7, 2},
+	}
+
+	for i, e := range exp {
+		c := cs[e.frame]
+		if c.Frame != e.frame || c.Bar != e.bar || c.Note != e.note {
+			t.Errorf("Expected frame %d to be bar %d note %d, got frame %d bar %d note %d at index %d", e.frame, e.bar, e.note, c.Frame, c.Bar, c.Note, i)
+		}
+	}
+
+	speeds := map[uint64]uint16{0: 120, 384: 120, 672: 90, 840: 100}
+	for frame, speed := range speeds {
+		if bc := cs[frame].BarChange; bc == nil || bc.Speed != speed {
+			t.Errorf("Expected bar change at frame %d with speed %d, got %+v", frame, speed, bc)
+		}
+	}
+
+	ds.Songs["signatures"].BarChanges[0].Speed = 0
+	if _, err := Render(ds, "signatures"); err == nil {
+		t.Errorf("Expected to get an error for a first bar change without speed")
+	}
+}
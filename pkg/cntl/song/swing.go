@@ -0,0 +1,118 @@
+package song
+
+import (
+	"fmt"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+)
+
+// ValidateSwing validates the swing of the given bar change
+func ValidateSwing(bc *cntl.BarChange) error {
+	if bc.Swing == nil {
+		return nil
+	}
+
+	if !cntl.ValidNoteValue(bc.Swing.NoteValue) || cntl.RenderFrames/bc.Swing.NoteValue < 2 {
+		return fmt.Errorf("swing note value %d of bar change at frame %d cannot be rendered", bc.Swing.NoteValue, bc.At)
+	}
+
+	if bc.Swing.Amount < 50 || bc.Swing.Amount >= 100 {
+		return fmt.Errorf("swing amount %d of bar change at frame %d must be between 50 and 99", bc.Swing.Amount, bc.At)
+	}
+
+	return nil
+}
+
+// calcSwingOffset returns the swung offset of the given offset within a pair of notes of the given length
+func calcSwingOffset(offset, noteLength uint64, amount uint8) uint64 {
+	pair := 2 * noteLength
+	a := uint64(amount)
+
+	if offset < noteLength {
+		return offset * pair * a / (100 * noteLength)
+	}
+
+	return (pair*a + (offset-noteLength)*2*(100-a)) / 100
+}
+
+// Swing moves the DMX and MIDI commands of every frame to their swung position, according to the swing
+// of the bar change they are in. Scene starts, chase steps and MIDI commands are all shifted alike.
+// The second note of a pair is compressed, so when several frames land on the same frame,
+// the DMX command of the latest one wins for every channel.
+func Swing(cs []cntl.Command) []cntl.Command {
+	var bc *cntl.BarChange
+	swung := make(map[uint64]cntl.Command)
+
+	for frame := uint64(0); frame < uint64(len(cs)); frame++ {
+		if cs[frame].BarChange != nil {
+			bc = cs[frame].BarChange
+		}
+
+		if bc == nil || bc.Swing == nil {
+			continue
+		}
+
+		noteLength := uint64(cntl.RenderFrames / bc.Swing.NoteValue)
+		rel := frame - bc.At
+		offset := rel % (2 * noteLength)
+		target := frame - offset + calcSwingOffset(offset, noteLength, bc.Swing.Amount)
+
+		if target == frame {
+			continue
+		}
+
+		// never swing a command into the next bar change
+		for next := frame + 1; next <= target && next < uint64(len(cs)); next++ {
+			if cs[next].BarChange != nil {
+				target = next - 1
+				break
+			}
+		}
+
+		c := swung[target]
+		c.DMXCommands = append(c.DMXCommands, cs[frame].DMXCommands...)
+		c.MIDICommands = append(c.MIDICommands, cs[frame].MIDICommands...)
+		swung[target] = c
+
+		cs[frame].DMXCommands = make([]cntl.DMXCommand, 0)
+		cs[frame].MIDICommands = make([]cntl.MIDICommand, 0)
+	}
+
+	if len(swung) == 0 {
+		return cs
+	}
+
+	if last := maxKey(swung); last >= uint64(len(cs)) {
+		cs = append(cs, makeCommandArray(last-uint64(len(cs))+1)...)
+	}
+
+	for frame, c := range swung {
+		cs[frame].DMXCommands = lastPerChannel(append(cs[frame].DMXCommands, c.DMXCommands...))
+		cs[frame].MIDICommands = append(cs[frame].MIDICommands, c.MIDICommands...)
+	}
+
+	return cs
+}
+
+// lastPerChannel returns the given commands with only the last value of every channel
+func lastPerChannel(cmds []cntl.DMXCommand) []cntl.DMXCommand {
+	type channel struct {
+		universe cntl.DMXUniverse
+		channel  cntl.DMXChannel
+	}
+
+	index := make(map[channel]int)
+	result := make([]cntl.DMXCommand, 0, len(cmds))
+	for _, c := range cmds {
+		key := channel{c.Universe, c.Channel}
+		if i, ok := index[key]; ok {
+			result[i] = c
+			continue
+		}
+
+		index[key] = len(result)
+		result = append(result, c)
+	}
+
+	return result
+}
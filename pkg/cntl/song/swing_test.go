@@ -0,0 +1,88 @@
+package song
+
+import (
+	"testing"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/internal/fixtures"
+)
+
+func TestSwing(t *testing.T) {
+	dmxCmd := cntl.DMXCommand{Universe: 1, Channel: 1, Value: *fixtures.Value255}
+	midiCmd := cntl.MIDICommand{Status: 0x90, Data1: 60, Data2: 100}
+
+	exp := []struct {
+		swing *cntl.Swing
+		from  uint64
+		to    uint64
+	}{
+		{swing: nil, from: 24, to: 24},
+		{swing: &cntl.Swing{NoteValue: 8, Amount: 50}, from: 24, to: 24},
+		{swing: &cntl.Swing{NoteValue: 8, Amount: 75}, from: 0, to: 0},
+		{swing: &cntl.Swing{NoteValue: 8, Amount: 75}, from: 24, to: 36},
+		{swing: &cntl.Swing{NoteValue: 8, Amount: 75}, from: 36, to: 42},
+		{swing: &cntl.Swing{NoteValue: 8, Amount: 75}, from: 48, to: 48},
+		{swing: &cntl.Swing{NoteValue: 8, Amount: 75}, from: 12, to: 18},
+		{swing: &cntl.Swing{NoteValue: 16, Amount: 67}, from: 180, to: 184},
+		{swing: &cntl.Swing{NoteValue: 8, Amount: 75}, from: 216, to: 228},
+	}
+
+	for i, e := range exp {
+		cs := makeCommandArray(192)
+		cs[0].BarChange = &cntl.BarChange{BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 120}, Swing: e.swing}
+		if e.from >= uint64(len(cs)) {
+			cs = append(cs, makeCommandArray(e.from-uint64(len(cs))+1)...)
+		}
+		cs[e.from].DMXCommands = cntl.DMXCommands{dmxCmd}
+		cs[e.from].MIDICommands = []cntl.MIDICommand{midiCmd}
+
+		cs = Swing(cs)
+
+		for frame := range cs {
+			expected := uint64(frame) == e.to
+			if cntl.DMXCommands(cs[frame].DMXCommands).Contains(dmxCmd) != expected {
+				t.Errorf("Expected DMX command at frame %d: %v, got %+v at index %d", frame, expected, cs[frame].DMXCommands, i)
+			}
+			if (len(cs[frame].MIDICommands) == 1) != expected {
+				t.Errorf("Expected MIDI command at frame %d: %v, got %+v at index %d", frame, expected, cs[frame].MIDICommands, i)
+			}
+		}
+	}
+}
+
+func TestSwing_StopsAtBarChange(t *testing.T) {
+	cmd := cntl.DMXCommand{Universe: 1, Channel: 1, Value: *fixtures.Value255}
+	cs := makeCommandArray(60)
+	cs[0].BarChange = &cntl.BarChange{BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 120}, Swing: &cntl.Swing{NoteValue: 8, Amount: 75}}
+	cs[30].BarChange = &cntl.BarChange{At: 30, BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 120}}
+	cs[24].DMXCommands = cntl.DMXCommands{cmd}
+
+	cs = Swing(cs)
+
+	if !cntl.DMXCommands(cs[29].DMXCommands).Contains(cmd) {
+		t.Errorf("Expected command to be swung right before the next bar change, got %+v", cs[29].DMXCommands)
+	}
+}
+
+func TestSwing_Collisions(t *testing.T) {
+	swing := &cntl.Swing{NoteValue: 8, Amount: 75}
+	cs := makeCommandArray(48)
+	cs[0].BarChange = &cntl.BarChange{BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 120}, Swing: swing}
+
+	// frames 38 and 39 of the compressed second eighth both land on frame 43
+	for frame := uint64(24); frame < 48; frame++ {
+		cs[frame].DMXCommands = cntl.DMXCommands{{Universe: 1, Channel: 1, Value: cntl.DMXValue{Value: uint8(frame)}}}
+	}
+
+	cs = Swing(cs)
+
+	for frame := range cs {
+		if len(cs[frame].DMXCommands) > 1 {
+			t.Errorf("Expected at most one command per channel at frame %d, got %+v", frame, cs[frame].DMXCommands)
+		}
+	}
+
+	if v := cs[43].DMXCommands[0].Value.Value; v != 39 {
+		t.Errorf("Expected the value of the latest frame to win, got %d", v)
+	}
+}
@@ -0,0 +1,128 @@
+package song
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/dmx"
+	"github.com/StageAutoControl/controller/pkg/cntl/timecode"
+)
+
+// ValidationErrors contains all problems found while validating a song
+type ValidationErrors []error
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Validate checks the given song for consistency with the given data store, including that its scenes render,
+// and returns all problems found as ValidationErrors
+func Validate(ds *cntl.DataStore, s *cntl.Song) error {
+	resolved, err := ResolvePositions(s)
+	if err != nil {
+		return ValidationErrors{fmt.Errorf("failed to resolve positions: %v", err)}
+	}
+
+	var errs ValidationErrors
+	if err := ValidateBarChanges(StreamlineBarChanges(resolved)); err != nil {
+		errs = append(errs, fmt.Errorf("failed to validate bar changes: %v", err))
+	}
+
+	end, hasEnd, err := ResolveEnd(resolved)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to resolve song end: %v", err))
+	}
+
+	ds = dmx.OverrideColorVariables(ds, resolved.ColorVariables)
+
+	// without an end the song ends with its last scene or MIDI command
+	var contentEnd uint64
+	rendered := make(map[string]error)
+
+	for _, sp := range resolved.DMXScenes {
+		sc, ok := ds.DMXScenes[sp.ID]
+		if !ok {
+			errs = append(errs, fmt.Errorf("cannot find DMXScene %q at frame %d", sp.ID, sp.At))
+			continue
+		}
+
+		err, ok := rendered[sc.ID]
+		if !ok {
+			_, err = dmx.RenderScene(ds, sc)
+			rendered[sc.ID] = err
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to render DMXScene %q: %v", sc.ID, err))
+			}
+		}
+		if err != nil {
+			continue
+		}
+
+		sceneEnd := sp.At + (uint64(sp.Repeat)+1)*dmx.CalcSceneLength(sc)
+		if sceneEnd > contentEnd {
+			contentEnd = sceneEnd
+		}
+		if hasEnd && sceneEnd > end {
+			errs = append(errs, fmt.Errorf("DMXScene %q at frame %d ends at frame %d, after the song end at frame %d", sc.ID, sp.At, sceneEnd, end))
+		}
+	}
+
+	for i, mc := range resolved.MIDICommands {
+		if mc.Status < 0x80 {
+			errs = append(errs, fmt.Errorf("MIDICommand %d at frame %d has an invalid status byte %#x", i, mc.At, mc.Status))
+		}
+
+		if mc.Data1 > 0x7F || mc.Data2 > 0x7F {
+			errs = append(errs, fmt.Errorf("MIDICommand %d at frame %d has data bytes above 0x7f", i, mc.At))
+		}
+
+		if mc.At+1 > contentEnd {
+			contentEnd = mc.At + 1
+		}
+		if hasEnd && mc.At >= end {
+			errs = append(errs, fmt.Errorf("MIDICommand %d at frame %d is after the song end at frame %d", i, mc.At, end))
+		}
+	}
+
+	if !hasEnd && contentEnd > 0 {
+		end, hasEnd = contentEnd, true
+	}
+
+	for _, bc := range resolved.BarChanges {
+		if hasEnd && bc.At > 0 && bc.At >= end {
+			errs = append(errs, fmt.Errorf("bar change at frame %d is after the song end at frame %d", bc.At, end))
+		}
+	}
+
+	names := make(map[string]bool)
+	for _, sec := range resolved.Sections {
+		if sec.Name == "" {
+			errs = append(errs, fmt.Errorf("section at frame %d needs a name", sec.At))
+		} else if names[sec.Name] {
+			errs = append(errs, fmt.Errorf("section name %q is used more than once", sec.Name))
+		}
+		names[sec.Name] = true
+
+		if hasEnd && sec.At >= end {
+			errs = append(errs, fmt.Errorf("section %q at frame %d is after the song end at frame %d", sec.Name, sec.At, end))
+		}
+	}
+
+	if resolved.Timecode != nil {
+		if _, err := timecode.Parse(resolved.Timecode.Start, resolved.Timecode.Rate); err != nil {
+			errs = append(errs, fmt.Errorf("invalid song timecode: %v", err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package song
+
+import (
+	"testing"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/internal/fixtures"
+)
+
+func TestValidate(t *testing.T) {
+	ds := fixtures.DataStore()
+	valid := ds.Songs["3c1065c8-0b14-11e7-96eb-5b134621c411"]
+
+	withEnd := *valid
+	withEnd.End = "40.1"
+
+	endTooEarly := *valid
+	endTooEarly.End = "30.1"
+
+	missingScene := *valid
+	missingScene.DMXScenes = append([]cntl.DMXScenePosition{{ID: "unknown"}}, valid.DMXScenes...)
+
+	invalidMIDI := *valid
+	invalidMIDI.MIDICommands = []cntl.MIDICommand{{Status: 0x90, Data1: 60, Data2: 100}, {Status: 0x10}, {Status: 0x90, Data1: 0x80}}
+
+	brokenBar := *valid
+	brokenBar.BarChanges = append([]cntl.BarChange{}, valid.BarChanges...)
+	brokenBar.BarChanges[1].At = 1500
+
+	lateBarChange := *valid
+	lateBarChange.BarChanges = append([]cntl.BarChange{}, valid.BarChanges...)
+	lateBarChange.BarChanges = append(lateBarChange.BarChanges, cntl.BarChange{Position: "60.1", BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4}})
+
+	withoutSpeed := *valid
+	withoutSpeed.BarChanges = append([]cntl.BarChange{}, valid.BarChanges...)
+	withoutSpeed.BarChanges[0].Speed = 0
+
+	withoutRampSpeed := *valid
+	withoutRampSpeed.BarChanges = append([]cntl.BarChange{}, valid.BarChanges...)
+	withoutRampSpeed.BarChanges[1].Ramp = &cntl.TempoRamp{Bars: 2}
+
+	withTimecode := *valid
+	withTimecode.Timecode = &cntl.SongTimecode{Start: "01:00:00:00", Rate: cntl.TimecodeRate25}
+
+	invalidTimecode := *valid
+	invalidTimecode.Timecode = &cntl.SongTimecode{Start: "01:00:00:25", Rate: cntl.TimecodeRate25}
+
+	exp := []struct {
+		s    *cntl.Song
+		errs int
+	}{
+		{valid, 0},
+		{&withEnd, 0},
+		{&endTooEarly, 3},
+		{&missingScene, 1},
+		{&invalidMIDI, 2},
+		{&brokenBar, 1},
+		{&lateBarChange, 1},
+		{&withoutSpeed, 1},
+		{&withoutRampSpeed, 1},
+		{&withTimecode, 0},
+		{&invalidTimecode, 1},
+	}
+
+	for i, e := range exp {
+		err := Validate(ds, e.s)
+		if e.errs == 0 {
+			if err != nil {
+				t.Errorf("Expected to get no error at index %d, got %v", i, err)
+			}
+			continue
+		}
+
+		errs, ok := err.(ValidationErrors)
+		if !ok || len(errs) != e.errs {
+			t.Errorf("Expected to get %d errors at index %d, got %v", e.errs, i, err)
+		}
+	}
+}
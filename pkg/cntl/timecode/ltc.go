@@ -0,0 +1,173 @@
+package timecode
+
+import (
+	"math"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+)
+
+// LTC frames consist of 80 biphase mark coded bits, ending with a sync word
+const (
+	ltcFrameBits = 80
+	ltcSyncWord  = "0011111111111101"
+	ltcMinFPS    = 20
+
+	// the signal has to cross this level to count as a transition, so noise around zero is ignored
+	ltcHysteresis = 0.05
+)
+
+// LTCDecoder decodes linear timecode from audio samples.
+// The bit period is adapted continuously, so timecode at all supported rates and slightly varying speeds is decoded.
+type LTCDecoder struct {
+	sampleRate float64
+	period     float64
+	high       bool
+	since      float64
+	half       bool
+	first      float64
+	bits       []byte
+}
+
+// NewLTCDecoder returns a decoder for audio with the given sample rate
+func NewLTCDecoder(sampleRate float64) *LTCDecoder {
+	return &LTCDecoder{
+		sampleRate: sampleRate,
+		// starting at 25 fps, short and long intervals of all other rates are still classified correctly
+		period: sampleRate / (ltcFrameBits * 25),
+		bits:   make([]byte, 0, 2*ltcFrameBits),
+	}
+}
+
+// Decode processes the given samples and returns the timecodes of the frames that start after them
+func (d *LTCDecoder) Decode(samples []float32) []Timecode {
+	var tcs []Timecode
+
+	for _, s := range samples {
+		d.since++
+
+		switch {
+		case !d.high && s > ltcHysteresis:
+			d.high = true
+		case d.high && s < -ltcHysteresis:
+			d.high = false
+		default:
+			continue
+		}
+
+		if tc, ok := d.transition(d.since); ok {
+			tcs = append(tcs, tc)
+		}
+		d.since = 0
+	}
+
+	return tcs
+}
+
+// transition classifies the interval since the last transition as a full or half bit period
+func (d *LTCDecoder) transition(interval float64) (Timecode, bool) {
+	switch {
+	case interval > 1.5*d.period:
+		// started on a half period, lock to the new interval unless the signal was lost
+		if interval < d.sampleRate/(ltcFrameBits*ltcMinFPS) {
+			d.period = interval
+		}
+		d.half = false
+		d.bits = d.bits[:0]
+		return Timecode{}, false
+
+	case interval >= 0.75*d.period:
+		d.period = (3*d.period + interval) / 4
+		d.half = false
+		return d.bit('0')
+
+	case d.half:
+		d.period = (3*d.period + d.first + interval) / 4
+		d.half = false
+		return d.bit('1')
+	}
+
+	d.half = true
+	d.first = interval
+	return Timecode{}, false
+}
+
+func (d *LTCDecoder) bit(b byte) (Timecode, bool) {
+	d.bits = append(d.bits, b)
+	if len(d.bits) > ltcFrameBits {
+		d.bits = append(d.bits[:0], d.bits[len(d.bits)-ltcFrameBits:]...)
+	}
+
+	if len(d.bits) < ltcFrameBits || string(d.bits[ltcFrameBits-len(ltcSyncWord):]) != ltcSyncWord {
+		return Timecode{}, false
+	}
+
+	tc, ok := d.frame()
+	if !ok {
+		return Timecode{}, false
+	}
+
+	// the frame has been transmitted completely, the next one starts now
+	return tc.Add(1), true
+}
+
+// frame decodes the BCD coded fields of the current 80 bits
+func (d *LTCDecoder) frame() (Timecode, bool) {
+	field := func(start, count int) (uint8, bool) {
+		var v uint8
+		for i := 0; i < count; i++ {
+			if d.bits[start+i] == '1' {
+				v |= 1 << uint(i)
+			}
+		}
+		return v, v <= 9
+	}
+
+	var values [8]uint8
+	for i, f := range [][2]int{{0, 4}, {8, 2}, {16, 4}, {24, 3}, {32, 4}, {40, 3}, {48, 4}, {56, 2}} {
+		v, ok := field(f[0], f[1])
+		if !ok {
+			return Timecode{}, false
+		}
+		values[i] = v
+	}
+
+	rate := d.rate()
+	fps, _ := nominalFPS(rate)
+	tc := Timecode{
+		Hours:   values[7]*10 + values[6],
+		Minutes: values[5]*10 + values[4],
+		Seconds: values[3]*10 + values[2],
+		Frames:  values[1]*10 + values[0],
+		Rate:    rate,
+	}
+
+	if tc.Hours > 23 || tc.Minutes > 59 || tc.Seconds > 59 || uint64(tc.Frames) >= fps {
+		return Timecode{}, false
+	}
+
+	return tc, true
+}
+
+// rate estimates the frame rate from the bit period, 29.97 is recognized by the drop frame flag
+func (d *LTCDecoder) rate() cntl.TimecodeRate {
+	if d.bits[10] == '1' {
+		return cntl.TimecodeRate2997
+	}
+
+	fps := d.sampleRate / (ltcFrameBits * d.period)
+	rate, diff := cntl.TimecodeRate25, math.Inf(1)
+	for _, r := range []struct {
+		rate cntl.TimecodeRate
+		fps  float64
+	}{
+		{cntl.TimecodeRate24, 24},
+		{cntl.TimecodeRate25, 25},
+		{cntl.TimecodeRate30, 30},
+	} {
+		if dd := math.Abs(fps - r.fps); dd < diff {
+			rate, diff = r.rate, dd
+		}
+	}
+
+	return rate
+}
@@ -0,0 +1,164 @@
+package timecode
+
+import (
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/audio"
+)
+
+// encodeLTC returns the biphase mark coded LTC of count frames starting at the given timecode
+func encodeLTC(start Timecode, count int, sampleRate float64) []float32 {
+	fps := map[cntl.TimecodeRate]float64{
+		cntl.TimecodeRate24:   24,
+		cntl.TimecodeRate25:   25,
+		cntl.TimecodeRate2997: 30000.0 / 1001,
+		cntl.TimecodeRate30:   30,
+	}[start.Rate]
+	halfPeriod := sampleRate / (ltcFrameBits * fps * 2)
+
+	// some silence before the signal starts
+	samples := make([]float32, 100)
+	level := float32(0.5)
+	halves := 0
+
+	for i := 0; i < count; i++ {
+		tc := start.Add(int64(i))
+		bits := make([]byte, ltcFrameBits)
+		for i := range bits {
+			bits[i] = '0'
+		}
+
+		set := func(at, count int, v uint8) {
+			for i := 0; i < count; i++ {
+				if v&(1<<uint(i)) != 0 {
+					bits[at+i] = '1'
+				}
+			}
+		}
+		set(0, 4, tc.Frames%10)
+		set(8, 2, tc.Frames/10)
+		set(16, 4, tc.Seconds%10)
+		set(24, 3, tc.Seconds/10)
+		set(32, 4, tc.Minutes%10)
+		set(40, 3, tc.Minutes/10)
+		set(48, 4, tc.Hours%10)
+		set(56, 2, tc.Hours/10)
+		if tc.Rate == cntl.TimecodeRate2997 {
+			bits[10] = '1'
+		}
+		copy(bits[ltcFrameBits-len(ltcSyncWord):], ltcSyncWord)
+
+		for _, b := range bits {
+			for half := 0; half < 2; half++ {
+				// every bit starts with a transition, ones have another one in the middle
+				if half == 0 || b == '1' {
+					level = -level
+				}
+
+				halves++
+				for len(samples) < 100+int(math.Round(float64(halves)*halfPeriod)) {
+					samples = append(samples, level)
+				}
+			}
+		}
+	}
+
+	// the transition at the start of the next bit ends the last one
+	for i := 0; i < 10; i++ {
+		samples = append(samples, -level)
+	}
+
+	return samples
+}
+
+func TestLTCDecoder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ltc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	exp := []struct {
+		start      string
+		rate       cntl.TimecodeRate
+		sampleRate uint32
+		invert     bool
+	}{
+		{"10:00:00:00", cntl.TimecodeRate25, 44100, false},
+		{"01:59:59:20", cntl.TimecodeRate24, 48000, false},
+		{"00:00:59:25", cntl.TimecodeRate30, 44100, true},
+		{"00:00:59:25", cntl.TimecodeRate2997, 48000, false},
+		{"23:09:59:28", cntl.TimecodeRate2997, 44100, true},
+	}
+
+	for i, e := range exp {
+		start, err := Parse(e.start, e.rate)
+		if err != nil {
+			t.Fatalf("Unexpected error at index %d: %v", i, err)
+		}
+
+		samples := encodeLTC(start, 50, float64(e.sampleRate))
+		if e.invert {
+			for j := range samples {
+				samples[j] = -samples[j]
+			}
+		}
+
+		path := filepath.Join(dir, "ltc.wav")
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := audio.WriteWAV(f, e.sampleRate, samples); err != nil {
+			t.Fatalf("Unexpected error at index %d: %v", i, err)
+		}
+		f.Close()
+
+		wav, err := audio.OpenWAV(path)
+		if err != nil {
+			t.Fatalf("Unexpected error at index %d: %v", i, err)
+		}
+
+		d := NewLTCDecoder(wav.SampleRate())
+		var res []Timecode
+		buf := make([]float32, 64)
+		for {
+			n, err := wav.Read(buf)
+			res = append(res, d.Decode(buf[:n])...)
+			if err == io.EOF {
+				break
+			}
+		}
+
+		// the first frame may be missed while locking to the signal
+		if len(res) < 49 {
+			t.Fatalf("Expected to decode at least 49 timecodes at index %d, got %d", i, len(res))
+		}
+
+		last := start.Add(50)
+		for j, tc := range res {
+			exp := last.Add(int64(j - len(res) + 1))
+			if tc != exp {
+				t.Errorf("Expected to get timecode %v at %s at index %d/%d, got %v at %s", exp, exp.Rate, i, j, tc, tc.Rate)
+			}
+		}
+	}
+}
+
+func TestLTCDecoder_Noise(t *testing.T) {
+	d := NewLTCDecoder(44100)
+	samples := make([]float32, 44100)
+	for i := range samples {
+		samples[i] = float32(math.Sin(float64(i) * 0.3))
+	}
+
+	if tcs := d.Decode(samples); len(tcs) != 0 {
+		t.Errorf("Expected to decode no timecode from a sine, got %v", tcs)
+	}
+}
@@ -0,0 +1,163 @@
+// Package timecode converts SMPTE timecode from and to durations, supporting 24, 25, 29.97 drop frame and 30 fps.
+package timecode
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+)
+
+// drop frame timecode skips frame numbers 0 and 1 at the start of every minute, except every tenth minute
+const (
+	dropFramesPerMinute   = 2
+	dropFramesPer10Minute = 10*30*60 - 9*dropFramesPerMinute
+	dropFramesPerMinuteDF = 30*60 - dropFramesPerMinute
+)
+
+// Timecode is a SMPTE timecode at the given rate
+type Timecode struct {
+	Hours   uint8
+	Minutes uint8
+	Seconds uint8
+	Frames  uint8
+	Rate    cntl.TimecodeRate
+}
+
+// ValidateRate returns an error if the given rate is not supported
+func ValidateRate(rate cntl.TimecodeRate) error {
+	if _, err := nominalFPS(rate); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// nominalFPS returns the frames counted per second of the given rate, 29.97 counts 30 frames like 30 fps does
+func nominalFPS(rate cntl.TimecodeRate) (uint64, error) {
+	switch rate {
+	case cntl.TimecodeRate24:
+		return 24, nil
+	case cntl.TimecodeRate25:
+		return 25, nil
+	case cntl.TimecodeRate2997, cntl.TimecodeRate30:
+		return 30, nil
+	}
+
+	return 0, fmt.Errorf("timecode rate %q is not supported", rate)
+}
+
+// Parse parses a timecode in the form of hh:mm:ss:ff, the last separator may be a semicolon as used for drop frame
+func Parse(s string, rate cntl.TimecodeRate) (Timecode, error) {
+	fps, err := nominalFPS(rate)
+	if err != nil {
+		return Timecode{}, err
+	}
+
+	parts := strings.Split(strings.Replace(s, ";", ":", 1), ":")
+	if len(parts) != 4 {
+		return Timecode{}, fmt.Errorf("timecode %q needs to be in the form of hh:mm:ss:ff", s)
+	}
+
+	values := make([]uint64, 4)
+	for i, part := range parts {
+		if values[i], err = strconv.ParseUint(part, 10, 8); err != nil {
+			return Timecode{}, fmt.Errorf("timecode %q needs to be in the form of hh:mm:ss:ff", s)
+		}
+	}
+
+	if values[0] > 23 || values[1] > 59 || values[2] > 59 || values[3] >= fps {
+		return Timecode{}, fmt.Errorf("timecode %q is out of range at %s fps", s, rate)
+	}
+
+	tc := Timecode{uint8(values[0]), uint8(values[1]), uint8(values[2]), uint8(values[3]), rate}
+	if rate == cntl.TimecodeRate2997 && tc.Seconds == 0 && tc.Frames < dropFramesPerMinute && tc.Minutes%10 != 0 {
+		return Timecode{}, fmt.Errorf("timecode %q does not exist in drop frame", s)
+	}
+
+	return tc, nil
+}
+
+// String formats the timecode as hh:mm:ss:ff
+func (t Timecode) String() string {
+	return fmt.Sprintf("%02d:%02d:%02d:%02d", t.Hours, t.Minutes, t.Seconds, t.Frames)
+}
+
+// FrameCount returns the number of frames since 00:00:00:00
+func (t Timecode) FrameCount() uint64 {
+	fps, _ := nominalFPS(t.Rate)
+	minutes := uint64(t.Hours)*60 + uint64(t.Minutes)
+	count := (minutes*60+uint64(t.Seconds))*fps + uint64(t.Frames)
+
+	if t.Rate == cntl.TimecodeRate2997 {
+		count -= dropFramesPerMinute * (minutes - minutes/10)
+	}
+
+	return count
+}
+
+// Duration returns the time since 00:00:00:00
+func (t Timecode) Duration() time.Duration {
+	return frameDuration(t.FrameCount(), t.Rate)
+}
+
+// Add returns the timecode the given number of frames later, or earlier if negative
+func (t Timecode) Add(frames int64) Timecode {
+	count := int64(t.FrameCount()) + frames
+	if count < 0 {
+		count = 0
+	}
+
+	return FromFrameCount(uint64(count), t.Rate)
+}
+
+// FromFrameCount returns the timecode of the given number of frames since 00:00:00:00
+func FromFrameCount(count uint64, rate cntl.TimecodeRate) Timecode {
+	fps, _ := nominalFPS(rate)
+
+	if rate == cntl.TimecodeRate2997 {
+		tens, rest := count/dropFramesPer10Minute, count%dropFramesPer10Minute
+		count += 9 * dropFramesPerMinute * tens
+		if rest >= dropFramesPerMinute {
+			count += dropFramesPerMinute * ((rest - dropFramesPerMinute) / dropFramesPerMinuteDF)
+		}
+	}
+
+	return Timecode{
+		Hours:   uint8(count / (fps * 3600) % 24),
+		Minutes: uint8(count / (fps * 60) % 60),
+		Seconds: uint8(count / fps % 60),
+		Frames:  uint8(count % fps),
+		Rate:    rate,
+	}
+}
+
+// FromDuration returns the timecode of the frame that is played at the given time since 00:00:00:00
+func FromDuration(d time.Duration, rate cntl.TimecodeRate) Timecode {
+	if d < 0 {
+		d = 0
+	}
+
+	if rate == cntl.TimecodeRate2997 {
+		return FromFrameCount(uint64(d)*30000/1001/uint64(time.Second), rate)
+	}
+
+	fps, _ := nominalFPS(rate)
+	return FromFrameCount(uint64(d)*fps/uint64(time.Second), rate)
+}
+
+// FrameDuration returns the duration of a single frame at the given rate
+func FrameDuration(rate cntl.TimecodeRate) time.Duration {
+	return frameDuration(1, rate)
+}
+
+func frameDuration(count uint64, rate cntl.TimecodeRate) time.Duration {
+	if rate == cntl.TimecodeRate2997 {
+		return time.Duration(count * 1001 * uint64(time.Second) / 30000)
+	}
+
+	fps, _ := nominalFPS(rate)
+	return time.Duration(count * uint64(time.Second) / fps)
+}
@@ -0,0 +1,85 @@
+package timecode
+
+import (
+	"testing"
+	"time"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+)
+
+func TestParse(t *testing.T) {
+	exp := []struct {
+		s    string
+		rate cntl.TimecodeRate
+		tc   Timecode
+		err  bool
+	}{
+		{"01:02:03:04", cntl.TimecodeRate25, Timecode{1, 2, 3, 4, cntl.TimecodeRate25}, false},
+		{"00:10:00;00", cntl.TimecodeRate2997, Timecode{0, 10, 0, 0, cntl.TimecodeRate2997}, false},
+		{"00:00:00:23", cntl.TimecodeRate24, Timecode{0, 0, 0, 23, cntl.TimecodeRate24}, false},
+		{"00:00:00:24", cntl.TimecodeRate24, Timecode{}, true},
+		{"00:01:00;00", cntl.TimecodeRate2997, Timecode{}, true},
+		{"00:00:00:00", "50", Timecode{}, true},
+		{"24:00:00:00", cntl.TimecodeRate30, Timecode{}, true},
+		{"00:00:00", cntl.TimecodeRate30, Timecode{}, true},
+		{"aa:00:00:00", cntl.TimecodeRate30, Timecode{}, true},
+	}
+
+	for i, e := range exp {
+		tc, err := Parse(e.s, e.rate)
+		if (err != nil) != e.err {
+			t.Errorf("Expected to get error %t at index %d, got %v", e.err, i, err)
+		}
+		if tc != e.tc {
+			t.Errorf("Expected to get timecode %+v at index %d, got %+v", e.tc, i, tc)
+		}
+	}
+}
+
+func TestTimecode_FrameCount(t *testing.T) {
+	exp := []struct {
+		tc    string
+		rate  cntl.TimecodeRate
+		count uint64
+		d     time.Duration
+	}{
+		{"00:00:01:00", cntl.TimecodeRate25, 25, time.Second},
+		{"01:00:00:00", cntl.TimecodeRate24, 86400, time.Hour},
+		{"00:00:00:15", cntl.TimecodeRate30, 15, 500 * time.Millisecond},
+		{"00:01:00;02", cntl.TimecodeRate2997, 1800, 1800 * 1001 * time.Second / 30000},
+		{"00:10:00;00", cntl.TimecodeRate2997, 17982, 17982 * 1001 * time.Second / 30000},
+		{"01:00:00;00", cntl.TimecodeRate2997, 107892, 107892 * 1001 * time.Second / 30000},
+	}
+
+	for i, e := range exp {
+		tc, err := Parse(e.tc, e.rate)
+		if err != nil {
+			t.Fatalf("Unexpected error at index %d: %v", i, err)
+		}
+
+		if count := tc.FrameCount(); count != e.count {
+			t.Errorf("Expected to get frame count %d at index %d, got %d", e.count, i, count)
+		}
+		if d := tc.Duration(); d != e.d {
+			t.Errorf("Expected to get duration %v at index %d, got %v", e.d, i, d)
+		}
+		if back := FromFrameCount(e.count, e.rate); back != tc {
+			t.Errorf("Expected to get timecode %v from frame count at index %d, got %v", tc, i, back)
+		}
+		if back := FromDuration(e.d, e.rate); back != tc {
+			t.Errorf("Expected to get timecode %v from duration at index %d, got %v", tc, i, back)
+		}
+	}
+}
+
+func TestTimecode_Add(t *testing.T) {
+	tc := Timecode{0, 0, 59, 29, cntl.TimecodeRate2997}
+	if next := tc.Add(1); next.String() != "00:01:00:02" {
+		t.Errorf("Expected to skip the dropped frames, got %v", next)
+	}
+
+	tc = Timecode{0, 0, 0, 1, cntl.TimecodeRate25}
+	if prev := tc.Add(-2); prev.String() != "00:00:00:00" {
+		t.Errorf("Expected to stop at zero, got %v", prev)
+	}
+}
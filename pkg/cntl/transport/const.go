@@ -7,4 +7,13 @@ const (
 	TypeArtNet     = "artnet"
 	TypeMidi       = "midi"
 	TypeBarLogger  = "barLogger"
+	TypeLTC        = "ltc"
+)
+
+const (
+	// ltcSampleRate is the sample rate LTC is recorded from an audio input with
+	ltcSampleRate = 48000
+
+	// ltcBufferSize is the number of samples decoded at once, about a third of a frame at 30 fps
+	ltcBufferSize = 512
 )
@@ -0,0 +1,82 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/StageAutoControl/controller/pkg/cntl/audio"
+	"github.com/StageAutoControl/controller/pkg/cntl/timecode"
+	"github.com/StageAutoControl/controller/pkg/internal/logging"
+)
+
+// LTCInput decodes linear timecode from an audio source, e.g. an audio line from a video playback
+type LTCInput struct {
+	logger logging.Logger
+	source audio.Source
+}
+
+// NewLTCInput returns an LTCInput reading from the given audio source
+func NewLTCInput(logger logging.Logger, source audio.Source) *LTCInput {
+	return &LTCInput{logger, source}
+}
+
+// OpenLTCInput returns an LTCInput reading from the default audio input device,
+// or replaying the given WAV file in real time if a path is given
+func OpenLTCInput(logger logging.Logger, wavPath string) (*LTCInput, error) {
+	if wavPath != "" {
+		wav, err := audio.OpenWAV(wavPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open ltc file: %v", err)
+		}
+
+		return NewLTCInput(logger, audio.Realtime(wav)), nil
+	}
+
+	in, err := audio.NewInput(ltcSampleRate, ltcBufferSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLTCInput(logger, in), nil
+}
+
+// Listen sends all timecodes decoded from the audio source to the returned channel,
+// until the given context is done or the source has no more samples
+func (l *LTCInput) Listen(ctx context.Context) <-chan timecode.Timecode {
+	tcs := make(chan timecode.Timecode, 1024)
+
+	go func() {
+		defer close(tcs)
+		defer func() {
+			if err := l.source.Close(); err != nil {
+				l.logger.Errorf("failed to close ltc input: %v", err)
+			}
+		}()
+
+		d := timecode.NewLTCDecoder(l.source.SampleRate())
+		buf := make([]float32, ltcBufferSize)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			n, err := l.source.Read(buf)
+			for _, tc := range d.Decode(buf[:n]) {
+				tcs <- tc
+			}
+
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				l.logger.Errorf("failed to read ltc input: %v", err)
+				return
+			}
+		}
+	}()
+
+	return tcs
+}
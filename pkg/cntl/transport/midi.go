@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/midi"
 	"github.com/StageAutoControl/controller/pkg/internal/logging"
 
 	"github.com/rakyll/portmidi"
@@ -15,6 +16,7 @@ type MIDI struct {
 	deviceInfo *portmidi.DeviceInfo
 	deviceID   portmidi.DeviceID
 	out        *portmidi.Stream
+	clock      *midi.Clock
 }
 
 // NewMIDI creates a new MIDI transport
@@ -42,16 +44,43 @@ func NewMIDI(logger logging.Logger, deviceID int8) (*MIDI, error) {
 
 	logger.Infof("Using midi device %d", d)
 
-	return &MIDI{logger, info, d, out}, nil
+	return &MIDI{logger: logger, deviceInfo: info, deviceID: d, out: out}, nil
+}
+
+// SendClock enables sending MIDI clock ticks, and start, stop, continue and song position pointer messages if transport is true
+func (m *MIDI) SendClock(transport bool) {
+	m.clock = midi.NewClock(transport)
 }
 
 // Write writes MIDI signals to portmidi
 func (m *MIDI) Write(cmd cntl.Command) error {
-	if len(cmd.MIDICommands) == 0 {
+	var clock []cntl.MIDICommand
+	if m.clock != nil {
+		clock = m.clock.Messages(cmd)
+	}
+
+	if len(clock) == 0 && len(cmd.MIDICommands) == 0 {
+		return nil
+	}
+
+	return m.write(append(clock, cmd.MIDICommands...))
+}
+
+// Stop sends a stop message if the MIDI clock is sent and a song was played
+func (m *MIDI) Stop() error {
+	if m.clock == nil {
 		return nil
 	}
 
-	events := m.convertEvents(cmd)
+	if msgs := m.clock.Stop(); len(msgs) > 0 {
+		return m.write(msgs)
+	}
+
+	return nil
+}
+
+func (m *MIDI) write(cmds []cntl.MIDICommand) error {
+	events := m.convertEvents(cmds)
 	if err := m.out.Write(events); err != nil {
 		return err
 	}
@@ -61,8 +90,8 @@ func (m *MIDI) Write(cmd cntl.Command) error {
 	return nil
 }
 
-func (m *MIDI) convertEvents(cmd cntl.Command) (events []portmidi.Event) {
-	for _, c := range cmd.MIDICommands {
+func (m *MIDI) convertEvents(cmds []cntl.MIDICommand) (events []portmidi.Event) {
+	for _, c := range cmds {
 		events = append(events, portmidi.Event{
 			Status: int64(c.Status),
 			Data1:  int64(c.Data1),
@@ -0,0 +1,95 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/internal/logging"
+
+	"github.com/rakyll/portmidi"
+)
+
+// midiInputPollInterval defines how often the input device is polled for new events
+const midiInputPollInterval = time.Millisecond
+
+// MIDIInput reads MIDI commands from a device using portmidi.
+type MIDIInput struct {
+	logger   logging.Logger
+	deviceID portmidi.DeviceID
+	in       *portmidi.Stream
+}
+
+// NewMIDIInput opens the MIDI input device with the given ID, or the default one if the ID is negative
+func NewMIDIInput(logger logging.Logger, deviceID int8) (*MIDIInput, error) {
+	in, d, err := openMIDIInput(logger, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MIDIInput{logger, d, in}, nil
+}
+
+func openMIDIInput(logger logging.Logger, deviceID int8) (*portmidi.Stream, portmidi.DeviceID, error) {
+	if err := portmidi.Initialize(); err != nil {
+		return nil, 0, err
+	}
+
+	var d portmidi.DeviceID
+	if deviceID < 0 {
+		d = portmidi.DefaultInputDeviceID()
+	} else {
+		d = portmidi.DeviceID(deviceID)
+	}
+
+	if info := portmidi.Info(d); info == nil || !info.IsInputAvailable {
+		return nil, 0, errors.New("unable to read midi input device")
+	}
+
+	in, err := portmidi.NewInputStream(d, 1024)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	logger.Infof("Using midi input device %d", d)
+
+	return in, d, nil
+}
+
+// Listen sends all incoming MIDI commands to the returned channel, until the given context is done
+func (m *MIDIInput) Listen(ctx context.Context) <-chan cntl.MIDICommand {
+	cmds := make(chan cntl.MIDICommand, 1024)
+
+	go func() {
+		defer close(cmds)
+		defer func() {
+			if err := m.in.Close(); err != nil {
+				m.logger.Errorf("failed to close midi input: %v", err)
+			}
+		}()
+
+		t := time.NewTicker(midiInputPollInterval)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-t.C:
+				events, err := m.in.Read(1024)
+				if err != nil {
+					m.logger.Errorf("failed to read midi input: %v", err)
+					continue
+				}
+
+				for _, e := range events {
+					cmds <- cntl.MIDICommand{Status: uint8(e.Status), Data1: uint8(e.Data1), Data2: uint8(e.Data2)}
+				}
+			}
+		}
+	}()
+
+	return cmds
+}
@@ -0,0 +1,100 @@
+package transport
+
+import (
+	"context"
+	"time"
+
+	"github.com/StageAutoControl/controller/pkg/cntl/midi"
+	"github.com/StageAutoControl/controller/pkg/cntl/timecode"
+	"github.com/StageAutoControl/controller/pkg/internal/logging"
+
+	"github.com/rakyll/portmidi"
+)
+
+// MTCInput reads MIDI timecode from a device using portmidi.
+type MTCInput struct {
+	logger   logging.Logger
+	deviceID portmidi.DeviceID
+	in       *portmidi.Stream
+}
+
+// NewMTCInput opens the MIDI input device with the given ID, or the default one if the ID is negative
+func NewMTCInput(logger logging.Logger, deviceID int8) (*MTCInput, error) {
+	in, d, err := openMIDIInput(logger, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MTCInput{logger, d, in}, nil
+}
+
+// Listen sends all timecodes decoded from quarter frame and full frame messages to the returned channel,
+// until the given context is done
+func (m *MTCInput) Listen(ctx context.Context) <-chan timecode.Timecode {
+	tcs := make(chan timecode.Timecode, 1024)
+
+	go func() {
+		defer close(tcs)
+		defer func() {
+			if err := m.in.Close(); err != nil {
+				m.logger.Errorf("failed to close mtc input: %v", err)
+			}
+		}()
+
+		t := time.NewTicker(midiInputPollInterval)
+		defer t.Stop()
+
+		var d midi.MTCDecoder
+		var sysEx []byte
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-t.C:
+				// every event holds four bytes, either a short message or a part of a system exclusive message
+				msg, err := m.in.ReadSysExBytes(1024)
+				if err != nil {
+					m.logger.Errorf("failed to read mtc input: %v", err)
+					continue
+				}
+
+				for i := 0; i+4 <= len(msg); i += 4 {
+					event := msg[i : i+4]
+
+					// real time messages may be interleaved with system exclusive messages
+					if sysEx != nil && event[0] < 0xF8 {
+						sysEx = appendSysEx(sysEx, event)
+					} else if event[0] == 0xF0 {
+						sysEx = appendSysEx([]byte{}, event)
+					} else if event[0] == midi.StatusQuarterFrame {
+						if tc, ok := d.QuarterFrame(event[1]); ok {
+							tcs <- tc
+						}
+					}
+
+					if n := len(sysEx); n > 0 && sysEx[n-1] == 0xF7 {
+						if tc, ok := d.FullFrame(sysEx); ok {
+							tcs <- tc
+						}
+						sysEx = nil
+					}
+				}
+			}
+		}
+	}()
+
+	return tcs
+}
+
+// appendSysEx appends the bytes of the given event up to the end of the system exclusive message
+func appendSysEx(sysEx []byte, event []byte) []byte {
+	for _, b := range event {
+		sysEx = append(sysEx, b)
+		if b == 0xF7 {
+			break
+		}
+	}
+
+	return sysEx
+}
@@ -2,9 +2,10 @@ package cntl
 
 // SetList is a set of songs in a specific order
 type SetList struct {
-	ID    string   `json:"id" yaml:"id"`
-	Name  string   `json:"name" yaml:"name"`
-	Songs []string `json:"songs" yaml:"songs"`
+	ID             string             `json:"id" yaml:"id"`
+	Name           string             `json:"name" yaml:"name"`
+	Songs          []string           `json:"songs" yaml:"songs"`
+	ColorVariables []DMXColorVariable `json:"colorVariables" yaml:"colorVariables"`
 }
 
 // BarParams are a reusable informational struct on how fast and in what scheme something should be played
@@ -18,23 +19,78 @@ type BarParams struct {
 type BarChange struct {
 	BarParams
 	At uint64 `json:"at" yaml:"at"`
+	// Position is given as bar.beat(.tick) and takes precedence over At
+	Position string     `json:"position" yaml:"position"`
+	Swing    *Swing     `json:"swing" yaml:"swing"`
+	Ramp     *TempoRamp `json:"ramp" yaml:"ramp"`
 }
 
 // DMXScenePosition describes the position of a DMX scene within a song
 type DMXScenePosition struct {
-	ID     string `json:"id" yaml:"id"`
-	At     uint64 `json:"at" yaml:"at"`
-	Repeat uint8  `json:"repeat" yaml:"repeat"`
-	Marker string `json:"marker" yaml:"marker"`
+	ID string `json:"id" yaml:"id"`
+	At uint64 `json:"at" yaml:"at"`
+	// Position is given as bar.beat(.tick) and takes precedence over At
+	Position string `json:"position" yaml:"position"`
+	Repeat   uint8  `json:"repeat" yaml:"repeat"`
+	Marker   string `json:"marker" yaml:"marker"`
 }
 
 // Song is the whole container for everything that needs to be controlled during a song.
 type Song struct {
-	ID           string             `json:"id" yaml:"id"`
-	Name         string             `json:"name" yaml:"name"`
-	BarChanges   []BarChange        `json:"barChanges" yaml:"barChanges"`
-	DMXScenes    []DMXScenePosition `json:"dmxScenes" yaml:"dmxScenes"`
-	MIDICommands []MIDICommand      `json:"midiCommands" yaml:"midiCommands"`
+	ID             string             `json:"id" yaml:"id"`
+	Name           string             `json:"name" yaml:"name"`
+	BarChanges     []BarChange        `json:"barChanges" yaml:"barChanges"`
+	DMXScenes      []DMXScenePosition `json:"dmxScenes" yaml:"dmxScenes"`
+	MIDICommands   []MIDICommand      `json:"midiCommands" yaml:"midiCommands"`
+	MoveInDark     *MoveInDark        `json:"moveInDark" yaml:"moveInDark"`
+	ColorVariables []DMXColorVariable `json:"colorVariables" yaml:"colorVariables"`
+	Sections       []SongSection      `json:"sections" yaml:"sections"`
+	// End optionally marks the end of the song as bar.beat(.tick), nothing may be placed after it
+	End string `json:"end" yaml:"end"`
+	// Timecode optionally positions the song in SMPTE timecode, e.g. to lock it to video
+	Timecode *SongTimecode `json:"timecode" yaml:"timecode"`
+	// FormatVersion is the SongFormatVersion the song is stored in, older songs are converted by song.Migrate
+	FormatVersion uint8 `json:"formatVersion" yaml:"formatVersion"`
+}
+
+// SongTimecode defines the timecode the first frame of a song is played at
+type SongTimecode struct {
+	// Start is given as hh:mm:ss:ff
+	Start string       `json:"start" yaml:"start"`
+	Rate  TimecodeRate `json:"rate" yaml:"rate"`
+}
+
+// TimecodeRate is the frame rate of a SMPTE timecode
+type TimecodeRate string
+
+// SongSection names a part of a song like intro, verse or chorus, it lasts until the next section starts
+type SongSection struct {
+	Name string `json:"name" yaml:"name"`
+	At   uint64 `json:"at" yaml:"at"`
+	// Position is given as bar.beat(.tick) and takes precedence over At
+	Position string `json:"position" yaml:"position"`
+}
+
+// MoveInDark configures the pre-positioning of moving heads while they are dark.
+// Pan, tilt and color are prepared, gobo changes are not as device types have no gobo channel yet.
+type MoveInDark struct {
+	// LeadTime is the amount of notes a moving head is prepared before the change that needs it
+	LeadTime uint16 `json:"leadTime" yaml:"leadTime"`
+}
+
+// Swing delays every second note of the given note value until the next bar change
+type Swing struct {
+	NoteValue uint8 `json:"noteValue" yaml:"noteValue"`
+	// Amount is the share in percent the on-beat note takes of a note pair, 50 is straight, 67 a triplet feel
+	Amount uint8 `json:"amount" yaml:"amount"`
+}
+
+// TempoRamp changes the speed of a bar change gradually to the given speed over the given amount of bars
+type TempoRamp struct {
+	Speed uint16 `json:"speed" yaml:"speed"`
+	Bars  uint16 `json:"bars" yaml:"bars"`
+	// Ease defaults to linear
+	Ease EaseFunc `json:"ease" yaml:"ease"`
 }
 
 // Tag is a string literal tagging a DMX device
@@ -64,8 +120,10 @@ type DMXDeviceType struct {
 	ModeChannel         DMXChannel `json:"modeChannel" yaml:"modeChannel"`
 	Moving              bool       `json:"moving" yaml:"moving"`
 	PanChannel          DMXChannel `json:"panChannel" yaml:"panChannel"`
+	PanFineEnabled      bool       `json:"panFineEnabled" yaml:"panFineEnabled"`
 	PanFineChannel      DMXChannel `json:"panFineChannel" yaml:"panFineChannel"`
 	TiltChannel         DMXChannel `json:"tiltChannel" yaml:"tiltChannel"`
+	TiltFineEnabled     bool       `json:"tiltFineEnabled" yaml:"tiltFineEnabled"`
 	TiltFineChannel     DMXChannel `json:"tiltFineChannel" yaml:"tiltFineChannel"`
 	PanTiltSpeedChannel DMXChannel `json:"panTiltSpeedChannel" yaml:"panTiltSpeedChannel"`
 	LEDs                []LED      `json:"leds"`
@@ -115,6 +173,17 @@ type DMXSubScene struct {
 	At           []uint64          `json:"at" yaml:"at"`
 	DeviceParams []DMXDeviceParams `json:"deviceParams" yaml:"deviceParams"`
 	Preset       *string           `json:"preset" yaml:"preset"`
+	PresetArgs   DMXPresetArgs     `json:"presetArgs" yaml:"presetArgs"`
+	Scene        *DMXSceneRef      `json:"scene" yaml:"scene"`
+}
+
+// DMXSceneRef embeds another DMXScene into a sub scene
+type DMXSceneRef struct {
+	ID string `json:"id" yaml:"id"`
+	// Offset shifts the embedded scene by the given amount of its own notes
+	Offset uint16 `json:"offset" yaml:"offset"`
+	// NoteValue optionally renders the embedded scene with another note value, e.g. twice as fast
+	NoteValue uint8 `json:"noteValue" yaml:"noteValue"`
 }
 
 // DMXColorVariable is a global variable for a DMX color
@@ -127,11 +196,26 @@ type DMXColorVariable struct {
 	White *DMXValue `json:"white" yaml:"white"`
 }
 
+// DMXPalette is a named set of position and beam values, either global or for a specific device or group.
+// Multiple palettes can share a name, the one matching a device most specifically is used.
+type DMXPalette struct {
+	ID     string    `json:"id" yaml:"id"`
+	Name   string    `json:"name" yaml:"name"`
+	Device *string   `json:"device" yaml:"device"`
+	Group  *string   `json:"group" yaml:"group"`
+	Pan    *DMXValue `json:"pan" yaml:"pan"`
+	Tilt   *DMXValue `json:"tilt" yaml:"tilt"`
+	Strobe *DMXValue `json:"strobe" yaml:"strobe"`
+	Mode   *DMXValue `json:"mode" yaml:"mode"`
+}
+
 // DMXParams is a DMX parameter object
 type DMXParams struct {
 	LEDAll       bool      `json:"ledAll"`
 	LED          uint16    `json:"led" yaml:"led"`
 	ColorVar     *string   `json:"$color" yaml:"$color"`
+	DimmerVar    *string   `json:"$dimmer" yaml:"$dimmer"`
+	Palette      *string   `json:"$palette" yaml:"$palette"`
 	Red          *DMXValue `json:"red" yaml:"red"`
 	Green        *DMXValue `json:"green" yaml:"green"`
 	Blue         *DMXValue `json:"blue" yaml:"blue"`
@@ -181,15 +265,32 @@ type DMXAnimationFrame struct {
 type DMXPreset struct {
 	ID           string            `json:"id" yaml:"id"`
 	Name         string            `json:"name" yaml:"name"`
+	Params       []DMXPresetParam  `json:"params" yaml:"params"`
 	DeviceParams []DMXDeviceParams `json:"deviceParams" yaml:"deviceParams"`
 }
 
+// DMXPresetParam declares a named parameter of a DMXPreset. The device params of the preset reference it
+// by its name prefixed with a $, e.g. a group "$washes" or a color var "$color".
+type DMXPresetParam struct {
+	Name    string             `json:"name" yaml:"name"`
+	Type    DMXPresetParamType `json:"type" yaml:"type"`
+	Default *string            `json:"default" yaml:"default"`
+}
+
+// DMXPresetParamType names what kind of value a DMXPresetParam takes
+type DMXPresetParamType string
+
+// DMXPresetArgs are the arguments passed to a DMXPreset, indexed by parameter name
+type DMXPresetArgs map[string]string
+
 // Command is a container to set settings
 type Command struct {
 	FrameState
 	DMXCommands  DMXCommands  `json:"dmxCommands" yaml:"dmxCommands"`
 	MIDICommands MIDICommands `json:"midiCommands" yaml:"midiCommands"`
 	BarChange    *BarChange   `json:"barChange" yaml:"barChange"`
+	// Paused is set on commands sent while the playback is paused, e.g. when it pauses or is seeked
+	Paused bool `json:"paused" yaml:"paused"`
 }
 
 // FrameState stores information about which bar and note the command is in
@@ -222,11 +323,35 @@ type DMXValue struct {
 
 // MIDICommand tells a MIDI controller to set a channel to a specific value
 type MIDICommand struct {
-	At     uint64 `json:"at" yaml:"at"`
-	Status uint8  `json:"status" yaml:"status"`
-	Data1  uint8  `json:"data1" yaml:"data1"`
-	Data2  uint8  `json:"data2" yaml:"data2"`
+	At uint64 `json:"at" yaml:"at"`
+	// Position is given as bar.beat(.tick) and takes precedence over At
+	Position string `json:"position" yaml:"position"`
+	Status   uint8  `json:"status" yaml:"status"`
+	Data1    uint8  `json:"data1" yaml:"data1"`
+	Data2    uint8  `json:"data2" yaml:"data2"`
 }
 
 // MIDICommands is an array of MIDICommands
 type MIDICommands []MIDICommand
+
+// MIDIMapping triggers a live action when a MIDI message is received, e.g. from a control surface
+type MIDIMapping struct {
+	ID      string          `json:"id" yaml:"id"`
+	Name    string          `json:"name" yaml:"name"`
+	Message MIDIMessageType `json:"message" yaml:"message"`
+	// Channel is the MIDI channel from 1 to 16, 0 matches all channels
+	Channel uint8 `json:"channel" yaml:"channel"`
+	// Data1 is the note, controller or program number
+	Data1  uint8             `json:"data1" yaml:"data1"`
+	Action MIDIMappingAction `json:"action" yaml:"action"`
+	// Target is the ID of the scene or preset to play, or of the device group to flash
+	Target     string        `json:"target" yaml:"target"`
+	BarParams  BarParams     `json:"barParams" yaml:"barParams"`
+	PresetArgs DMXPresetArgs `json:"presetArgs" yaml:"presetArgs"`
+}
+
+// MIDIMessageType names the kind of MIDI message a MIDIMapping is triggered by
+type MIDIMessageType string
+
+// MIDIMappingAction names what a MIDIMapping does when triggered
+type MIDIMappingAction string
@@ -10,14 +10,39 @@ func (v1 *SetList) Equals(v2 *SetList) bool {
 // Equals returns whether the two given objects are equal
 func (v1 BarChange) Equals(v2 BarChange) bool {
 	return v1.At == v2.At &&
+		v1.Position == v2.Position &&
 		v1.NoteCount == v2.NoteCount &&
 		v1.NoteValue == v2.NoteValue &&
-		v1.Speed == v2.Speed
+		v1.Speed == v2.Speed &&
+		v1.Swing.Equals(v2.Swing) &&
+		v1.Ramp.Equals(v2.Ramp)
+}
+
+// Equals returns whether the two given objects are equal
+func (v1 *Swing) Equals(v2 *Swing) bool {
+	if v1 == nil || v2 == nil {
+		return v1 == v2
+	}
+
+	return v1.NoteValue == v2.NoteValue &&
+		v1.Amount == v2.Amount
+}
+
+// Equals returns whether the two given objects are equal
+func (v1 *TempoRamp) Equals(v2 *TempoRamp) bool {
+	if v1 == nil || v2 == nil {
+		return v1 == v2
+	}
+
+	return v1.Speed == v2.Speed &&
+		v1.Bars == v2.Bars &&
+		v1.Ease == v2.Ease
 }
 
 // Equals returns whether the two given objects are equal
 func (v1 DMXScenePosition) Equals(v2 DMXScenePosition) bool {
 	return v1.At == v2.At &&
+		v1.Position == v2.Position &&
 		v1.ID == v2.ID &&
 		v1.Repeat == v2.Repeat
 }
@@ -27,7 +52,59 @@ func (v1 *Song) Equals(v2 *Song) bool {
 	return v1.ID == v2.ID &&
 		v1.Name == v2.Name &&
 		barChangeList(v1.BarChanges).Equals(barChangeList(v2.BarChanges)) &&
-		scenePositionList(v1.DMXScenes).Equals(scenePositionList(v2.DMXScenes))
+		scenePositionList(v1.DMXScenes).Equals(scenePositionList(v2.DMXScenes)) &&
+		midiCommandList(v1.MIDICommands).Equals(midiCommandList(v2.MIDICommands)) &&
+		v1.MoveInDark.Equals(v2.MoveInDark) &&
+		colorVariableList(v1.ColorVariables).Equals(colorVariableList(v2.ColorVariables)) &&
+		songSectionList(v1.Sections).Equals(songSectionList(v2.Sections)) &&
+		v1.End == v2.End &&
+		v1.Timecode.Equals(v2.Timecode) &&
+		v1.FormatVersion == v2.FormatVersion
+}
+
+// Equals returns whether the two given objects are equal
+func (v1 MIDICommand) Equals(v2 MIDICommand) bool {
+	return v1.At == v2.At &&
+		v1.Position == v2.Position &&
+		v1.Status == v2.Status &&
+		v1.Data1 == v2.Data1 &&
+		v1.Data2 == v2.Data2
+}
+
+// Equals returns whether the two given objects are equal
+func (v1 *MoveInDark) Equals(v2 *MoveInDark) bool {
+	if v1 == nil || v2 == nil {
+		return v1 == v2
+	}
+
+	return v1.LeadTime == v2.LeadTime
+}
+
+// Equals returns whether the two given objects are equal
+func (v1 DMXColorVariable) Equals(v2 DMXColorVariable) bool {
+	return v1.ID == v2.ID &&
+		v1.Name == v2.Name &&
+		dmxValuePtrEquals(v1.Red, v2.Red) &&
+		dmxValuePtrEquals(v1.Green, v2.Green) &&
+		dmxValuePtrEquals(v1.Blue, v2.Blue) &&
+		dmxValuePtrEquals(v1.White, v2.White)
+}
+
+// Equals returns whether the two given objects are equal
+func (v1 SongSection) Equals(v2 SongSection) bool {
+	return v1.Name == v2.Name &&
+		v1.At == v2.At &&
+		v1.Position == v2.Position
+}
+
+// Equals returns whether the two given objects are equal
+func (v1 *SongTimecode) Equals(v2 *SongTimecode) bool {
+	if v1 == nil || v2 == nil {
+		return v1 == v2
+	}
+
+	return v1.Start == v2.Start &&
+		v1.Rate == v2.Rate
 }
 
 // Equals returns whether the two given objects are equal
@@ -57,6 +134,14 @@ func (v1 *DMXDeviceType) Equals(v2 *DMXDeviceType) bool {
 		v1.DimmerChannel == v2.DimmerChannel &&
 		v1.ModeEnabled == v2.ModeEnabled &&
 		v1.ModeChannel == v2.ModeChannel &&
+		v1.Moving == v2.Moving &&
+		v1.PanChannel == v2.PanChannel &&
+		v1.PanFineEnabled == v2.PanFineEnabled &&
+		v1.PanFineChannel == v2.PanFineChannel &&
+		v1.TiltChannel == v2.TiltChannel &&
+		v1.TiltFineEnabled == v2.TiltFineEnabled &&
+		v1.TiltFineChannel == v2.TiltFineChannel &&
+		v1.PanTiltSpeedChannel == v2.PanTiltSpeedChannel &&
 		ledList(v1.LEDs).Equals(ledList(v2.LEDs))
 
 }
@@ -111,10 +196,22 @@ func (v1 DMXScene) Equals(v2 DMXScene) bool {
 // Equals returns whether the two given objects are equal
 func (v1 DMXSubScene) Equals(v2 DMXSubScene) bool {
 	return atList(v1.At).Equals(atList(v2.At)) &&
-		v1.Preset == v2.Preset &&
+		stringPtrEquals(v1.Preset, v2.Preset) &&
+		v1.PresetArgs.Equals(v2.PresetArgs) &&
+		v1.Scene.Equals(v2.Scene) &&
 		dmxDeviceParamsList(v1.DeviceParams).Equals(dmxDeviceParamsList(v2.DeviceParams))
 }
 
+// Equals returns whether the two given objects are equal
+func (v1 *DMXSceneRef) Equals(v2 *DMXSceneRef) bool {
+	if v1 == nil || v2 == nil {
+		return v1 == v2
+	}
+
+	return v1.ID == v2.ID &&
+		v1.Offset == v2.Offset
+}
+
 // Equals returns whether the two given objects are equal
 func (v1 DMXParams) Equals(v2 DMXParams) bool {
 	return v1.LED == v2.LED &&
@@ -142,9 +239,32 @@ func (v1 DMXAnimationFrame) Equals(v2 DMXAnimationFrame) bool {
 func (v1 DMXPreset) Equals(v2 DMXPreset) bool {
 	return v1.ID == v2.ID &&
 		v1.Name == v2.Name &&
+		dmxPresetParamList(v1.Params).Equals(dmxPresetParamList(v2.Params)) &&
 		dmxDeviceParamsList(v1.DeviceParams).Equals(dmxDeviceParamsList(v2.DeviceParams))
 }
 
+// Equals returns whether the two given objects are equal
+func (v1 DMXPresetParam) Equals(v2 DMXPresetParam) bool {
+	return v1.Name == v2.Name &&
+		v1.Type == v2.Type &&
+		stringPtrEquals(v1.Default, v2.Default)
+}
+
+// Equals returns whether the two given objects are equal
+func (v1 DMXPresetArgs) Equals(v2 DMXPresetArgs) bool {
+	if len(v1) != len(v2) {
+		return false
+	}
+
+	for name, value := range v1 {
+		if value2, ok := v2[name]; !ok || value != value2 {
+			return false
+		}
+	}
+
+	return true
+}
+
 // Contains returns whether given DMXCommand is in the called collection
 func (cmds DMXCommands) Contains(c DMXCommand) bool {
 	for _, cmd := range cmds {
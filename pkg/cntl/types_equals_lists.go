@@ -175,3 +175,83 @@ func (v1 dmxAnimationFrameList) Equals(v2 dmxAnimationFrameList) bool {
 
 	return true
 }
+
+type dmxPresetParamList []DMXPresetParam
+
+func (v1 dmxPresetParamList) Equals(v2 dmxPresetParamList) bool {
+	if len(v1) != len(v2) {
+		return false
+	}
+
+	for i := range v1 {
+		if !v1[i].Equals(v2[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+type midiCommandList []MIDICommand
+
+func (v1 midiCommandList) Equals(v2 midiCommandList) bool {
+	if len(v1) != len(v2) {
+		return false
+	}
+
+	for i := range v1 {
+		if !v1[i].Equals(v2[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+type colorVariableList []DMXColorVariable
+
+func (v1 colorVariableList) Equals(v2 colorVariableList) bool {
+	if len(v1) != len(v2) {
+		return false
+	}
+
+	for i := range v1 {
+		if !v1[i].Equals(v2[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+type songSectionList []SongSection
+
+func (v1 songSectionList) Equals(v2 songSectionList) bool {
+	if len(v1) != len(v2) {
+		return false
+	}
+
+	for i := range v1 {
+		if !v1[i].Equals(v2[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func dmxValuePtrEquals(v1, v2 *DMXValue) bool {
+	if v1 == nil || v2 == nil {
+		return v1 == v2
+	}
+
+	return v1.Equals(v2)
+}
+
+func stringPtrEquals(v1, v2 *string) bool {
+	if v1 == nil || v2 == nil {
+		return v1 == v2
+	}
+
+	return *v1 == *v2
+}
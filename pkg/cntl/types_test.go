@@ -150,3 +150,44 @@ func TestDMXCommands_Contains(t *testing.T) {
 		}
 	}
 }
+
+func TestSong_Equals(t *testing.T) {
+	song := func() *Song {
+		return &Song{
+			ID:             "song",
+			Name:           "Song",
+			BarChanges:     []BarChange{{At: 0, BarParams: BarParams{NoteCount: 4, NoteValue: 4, Speed: 120}}},
+			DMXScenes:      []DMXScenePosition{{At: 0, ID: "scene"}},
+			MIDICommands:   []MIDICommand{{Position: "2.1", Status: 0xC0, Data1: 5}},
+			MoveInDark:     &MoveInDark{LeadTime: 2},
+			ColorVariables: []DMXColorVariable{{ID: "red", Red: &DMXValue{255}}},
+			Sections:       []SongSection{{Name: "intro", Position: "1.1"}},
+			End:            "9.1",
+			Timecode:       &SongTimecode{Start: "01:00:00:00", Rate: "25"},
+			FormatVersion:  SongFormatVersion,
+		}
+	}
+
+	exp := []struct {
+		change func(s *Song)
+		equal  bool
+	}{
+		{change: func(s *Song) {}, equal: true},
+		{change: func(s *Song) { s.MIDICommands[0].Data1 = 6 }, equal: false},
+		{change: func(s *Song) { s.MoveInDark = nil }, equal: false},
+		{change: func(s *Song) { s.ColorVariables[0].Red = &DMXValue{128} }, equal: false},
+		{change: func(s *Song) { s.ColorVariables[0].Green = &DMXValue{0} }, equal: false},
+		{change: func(s *Song) { s.Sections[0].Position = "2.1" }, equal: false},
+		{change: func(s *Song) { s.End = "10.1" }, equal: false},
+		{change: func(s *Song) { s.Timecode.Rate = "30" }, equal: false},
+		{change: func(s *Song) { s.FormatVersion = 0 }, equal: false},
+	}
+
+	for i, e := range exp {
+		s := song()
+		e.change(s)
+		if eq := song().Equals(s); eq != e.equal {
+			t.Errorf("Expected Equals to return %t at index %d, got %t", e.equal, i, eq)
+		}
+	}
+}
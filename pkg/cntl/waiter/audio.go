@@ -1,45 +1,49 @@
 package waiter
 
 import (
-	"fmt"
-
-	"github.com/gordonklaus/portaudio"
+	"io"
 
+	"github.com/StageAutoControl/controller/pkg/cntl/audio"
 	"github.com/StageAutoControl/controller/pkg/internal/logging"
 )
 
-// Audio is a waiter that does nothing
+// Audio is a waiter that waits for a peak in an audio source
 type Audio struct {
 	logger    logging.Logger
 	threshold float32
+	open      func() (audio.Source, error)
 	notify    chan struct{}
 	buf       []float32
-	stream    *portaudio.Stream
+	source    audio.Source
 	cancel    chan struct{}
 	err       chan error
 }
 
-// NewAudio creates a new Audio waiter
+// NewAudio creates a new Audio waiter listening to the default input device
 func NewAudio(logger logging.Logger, threshold float32) *Audio {
+	return NewAudioFrom(logger, threshold, func() (audio.Source, error) {
+		return audio.NewInput(sampleRate, bufferSize)
+	})
+}
+
+// NewAudioFrom creates a new Audio waiter listening to the sources returned by open, e.g. a WAV file
+func NewAudioFrom(logger logging.Logger, threshold float32, open func() (audio.Source, error)) *Audio {
 	return &Audio{
 		logger:    logger,
 		threshold: threshold,
+		open:      open,
 	}
 }
 
 func (a *Audio) start() (err error) {
 	a.notify = make(chan struct{}, 1)
-	a.buf = make([]float32, 64)
+	a.buf = make([]float32, bufferSize)
 	a.cancel = make(chan struct{}, 1)
 	a.err = make(chan error, 5)
 
-	a.stream, err = portaudio.OpenDefaultStream(1, 0, sampleRate, len(a.buf), a.buf)
+	a.source, err = a.open()
 	if err != nil {
-		return fmt.Errorf("failed to open default portaudio stream: %v", err)
-	}
-
-	if err := a.stream.Start(); err != nil {
-		return fmt.Errorf("failed to start portaudio stream: %v", err)
+		return err
 	}
 
 	go a.readStream()
@@ -49,14 +53,17 @@ func (a *Audio) start() (err error) {
 
 func (a *Audio) readStream() {
 	for {
-		err := a.stream.Read()
-		if err != nil {
-			a.err <- err
-			a.logger.Infof("Error reading portaudio stream: %s", err)
+		n, err := a.source.Read(a.buf)
+		if a.checkForPeak(a.buf[:n]) {
 			return
 		}
 
-		if a.checkForPeak() {
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			a.err <- err
+			a.logger.Infof("Error reading audio source: %s", err)
 			return
 		}
 
@@ -68,8 +75,8 @@ func (a *Audio) readStream() {
 	}
 }
 
-func (a *Audio) checkForPeak() bool {
-	for _, i := range a.buf {
+func (a *Audio) checkForPeak(buf []float32) bool {
+	for _, i := range buf {
 		if i >= a.threshold || i <= (a.threshold*-1) {
 			a.notify <- struct{}{}
 			return true
@@ -105,22 +112,10 @@ loop:
 func (a *Audio) stop() (err error) {
 	a.cancel <- struct{}{}
 
-	if err := a.stream.Stop(); err != nil {
-		a.err <- err
-		a.logger.Errorf("failed to stop portaudio stream: %v", err)
-		// don't return the error, stream.Close has to be called
-		// return err
-	}
-
-	if err := a.stream.Close(); err != nil {
-		a.err <- err
-		a.logger.Errorf("failed to close portaudio stream: %v", err)
+	if err := a.source.Close(); err != nil {
+		a.logger.Errorf("failed to close audio source: %v", err)
 		return err
 	}
 
-	close(a.notify)
-	close(a.cancel)
-	close(a.err)
-
 	return nil
 }
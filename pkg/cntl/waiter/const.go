@@ -6,4 +6,5 @@ const (
 	TypeAudio = "audio"
 
 	sampleRate = 44100
+	bufferSize = 64
 )
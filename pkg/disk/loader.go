@@ -1,6 +1,11 @@
 package disk
 
-import "github.com/StageAutoControl/controller/pkg/cntl"
+import (
+	"fmt"
+
+	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/song"
+)
 
 // Loader loads the DataStore from the storage
 type Loader struct {
@@ -29,13 +34,19 @@ func (l *Loader) Load() (*cntl.DataStore, error) {
 	}
 
 	for _, id := range l.storage.List(&cntl.Song{}) {
-		song := &cntl.Song{}
-		err := l.storage.Read(id, song)
+		s := &cntl.Song{}
+		err := l.storage.Read(id, s)
 		if err != nil {
 			return nil, err
 		}
 
-		data.Songs[id] = song
+		// songs stored in an older format are converted in memory, the migrate command persists them
+		migrated, _, err := song.Migrate(s)
+		if err != nil {
+			return nil, fmt.Errorf("failed to migrate song %s: %v", id, err)
+		}
+
+		data.Songs[id] = migrated
 	}
 
 	for _, id := range l.storage.List(&cntl.DMXDevice{}) {
@@ -118,5 +129,25 @@ func (l *Loader) Load() (*cntl.DataStore, error) {
 		data.DMXColorVariables[id] = dmxColorVariable
 	}
 
+	for _, id := range l.storage.List(&cntl.DMXPalette{}) {
+		dmxPalette := &cntl.DMXPalette{}
+		err := l.storage.Read(id, dmxPalette)
+		if err != nil {
+			return nil, err
+		}
+
+		data.DMXPalettes[id] = dmxPalette
+	}
+
+	for _, id := range l.storage.List(&cntl.MIDIMapping{}) {
+		midiMapping := &cntl.MIDIMapping{}
+		err := l.storage.Read(id, midiMapping)
+		if err != nil {
+			return nil, err
+		}
+
+		data.MIDIMappings[id] = midiMapping
+	}
+
 	return data, nil
 }
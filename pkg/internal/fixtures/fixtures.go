@@ -31,19 +31,20 @@ var data = &cntl.DataStore{
 	},
 	Songs: map[string]*cntl.Song{
 		"3c1065c8-0b14-11e7-96eb-5b134621c411": {
-			ID:   "3c1065c8-0b14-11e7-96eb-5b134621c411",
-			Name: "Test song",
+			ID:            "3c1065c8-0b14-11e7-96eb-5b134621c411",
+			Name:          "Test song",
+			FormatVersion: cntl.SongFormatVersion,
 			BarChanges: []cntl.BarChange{
 				{At: 0, BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4, Speed: 160}},
-				{At: 512, BarParams: cntl.BarParams{NoteCount: 3, NoteValue: 4}},
-				{At: 1184, BarParams: cntl.BarParams{NoteCount: 7, NoteValue: 8}},
-				{At: 1632, BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4}},
+				{At: 1536, BarParams: cntl.BarParams{NoteCount: 3, NoteValue: 4}},
+				{At: 3552, BarParams: cntl.BarParams{NoteCount: 7, NoteValue: 8}},
+				{At: 4896, BarParams: cntl.BarParams{NoteCount: 4, NoteValue: 4}},
 			},
 			DMXScenes: []cntl.DMXScenePosition{
 				{At: 0, ID: "492cef2e-0b14-11e7-be89-c3fa25f9cabb", Repeat: 3},
-				{At: 512, ID: "a44f8dee-0b14-11e7-b5b9-bf1015384192", Repeat: 3},
-				{At: 1408, ID: "99b86a5e-0e7a-11e7-a01a-5b5fbdeba3d6", Repeat: 2},
-				{At: 1920, ID: "b82f4750-0e7a-11e7-9522-0f9d6d69958a"},
+				{At: 1536, ID: "a44f8dee-0b14-11e7-b5b9-bf1015384192", Repeat: 3},
+				{At: 4224, ID: "99b86a5e-0e7a-11e7-a01a-5b5fbdeba3d6", Repeat: 2},
+				{At: 5760, ID: "b82f4750-0e7a-11e7-9522-0f9d6d69958a"},
 			},
 		},
 	},
@@ -419,6 +420,39 @@ var data = &cntl.DataStore{
 			Green: Value255,
 		},
 	},
+	DMXPalettes: map[string]*cntl.DMXPalette{
+		"f1c2a6c4-6b0e-4c55-9f43-8a2c0b1fd8e7": {
+			ID:     "f1c2a6c4-6b0e-4c55-9f43-8a2c0b1fd8e7",
+			Name:   "Drums strobe",
+			Device: StrPtr("35cae00a-0b17-11e7-8bca-bbf30c56f20e"),
+			Strobe: Value127,
+			Mode:   Value31,
+		},
+		"8b0e6c1a-2f6e-4a2b-b1b0-6f0a8e4c2d11": {
+			ID:     "8b0e6c1a-2f6e-4a2b-b1b0-6f0a8e4c2d11",
+			Name:   "Drums strobe",
+			Strobe: Value255,
+		},
+	},
+	MIDIMappings: map[string]*cntl.MIDIMapping{
+		"c3a9e2b4-5d1f-4f7a-9b6e-2e8d4a1c7f30": {
+			ID:      "c3a9e2b4-5d1f-4f7a-9b6e-2e8d4a1c7f30",
+			Name:    "Pad 1 plays scene 1",
+			Message: cntl.MIDIMessageNote,
+			Channel: 10,
+			Data1:   36,
+			Action:  cntl.MIDIActionScene,
+			Target:  "492cef2e-0b14-11e7-be89-c3fa25f9cabb",
+		},
+		"7e4b1d92-0a6c-4e3b-8f25-91d6c0e3a5b8": {
+			ID:      "7e4b1d92-0a6c-4e3b-8f25-91d6c0e3a5b8",
+			Name:    "Fader 1 is the master",
+			Message: cntl.MIDIMessageControlChange,
+			Channel: 1,
+			Data1:   7,
+			Action:  cntl.MIDIActionMaster,
+		},
+	},
 }
 
 // DataStore returns the go object representation of a working set of fixtures
@@ -1,7 +1,10 @@
 package files
 
 import (
+	"fmt"
+
 	"github.com/StageAutoControl/controller/pkg/cntl"
+	"github.com/StageAutoControl/controller/pkg/cntl/song"
 )
 
 type fileData struct {
@@ -15,6 +18,8 @@ type fileData struct {
 	DMXDeviceTypes    []*cntl.DMXDeviceType    `json:"dmx_device_types"`
 	DMXDeviceGroups   []*cntl.DMXDeviceGroup   `json:"dmx_device_groups"`
 	DMXColorVariables []*cntl.DMXColorVariable `json:"dmx_color_variables"`
+	DMXPalettes       []*cntl.DMXPalette       `json:"dmx_palettes"`
+	MIDIMappings      []*cntl.MIDIMapping      `json:"midi_mappings"`
 }
 
 // Database is a file repository
@@ -40,5 +45,12 @@ func (d *Database) Load() (*cntl.DataStore, error) {
 
 	expandData(store, data)
 
+	// files are edited by hand, songs in an older format are converted when reading them
+	for id, s := range store.Songs {
+		if store.Songs[id], _, err = song.Migrate(s); err != nil {
+			return nil, fmt.Errorf("failed to migrate song %s: %v", id, err)
+		}
+	}
+
 	return store, nil
 }
@@ -36,6 +36,13 @@ func TestRepository_Load(t *testing.T) {
 		if ds.ID != s.ID {
 			t.Errorf("ID %q is not equal \n", key)
 		}
+
+		// the song fixture is stored on the legacy bar grid
+		for i, bc := range s.BarChanges {
+			if ds.BarChanges[i].At != bc.At {
+				t.Errorf("Expected bar change %d of song %q to be migrated to frame %d, got %d", i, key, bc.At, ds.BarChanges[i].At)
+			}
+		}
 	}
 
 	for key, s := range fix.DMXScenes {
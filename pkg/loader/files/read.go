@@ -56,6 +56,8 @@ func mergeFileData(data, fd *fileData) *fileData {
 	newData.DMXDeviceTypes = append(data.DMXDeviceTypes, fd.DMXDeviceTypes...)
 	newData.DMXDeviceGroups = append(data.DMXDeviceGroups, fd.DMXDeviceGroups...)
 	newData.DMXColorVariables = append(data.DMXColorVariables, fd.DMXColorVariables...)
+	newData.DMXPalettes = append(data.DMXPalettes, fd.DMXPalettes...)
+	newData.MIDIMappings = append(data.MIDIMappings, fd.MIDIMappings...)
 
 	return newData
 }
@@ -100,4 +102,12 @@ func expandData(data *cntl.DataStore, fileData *fileData) {
 	for _, t := range fileData.DMXColorVariables {
 		data.DMXColorVariables[t.ID] = t
 	}
+
+	for _, p := range fileData.DMXPalettes {
+		data.DMXPalettes[p.ID] = p
+	}
+
+	for _, m := range fileData.MIDIMappings {
+		data.MIDIMappings[m.ID] = m
+	}
 }